@@ -8,55 +8,27 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/types"
-	dynssz "github.com/pk910/dynamic-ssz"
-	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
-	"github.com/ethpandaops/eth-beacon-genesis/validators"
 )
 
 type altairBuilder struct {
-	elGenesis       *core.Genesis
-	clConfig        *beaconconfig.Config
-	dynSsz          *dynssz.DynSsz
-	shadowForkBlock *types.Block
-	validators      []*validators.Validator
+	*builderBase
 }
 
 func NewAltairBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config) BeaconGenesisBuilder {
 	return &altairBuilder{
-		elGenesis: elGenesis,
-		clConfig:  clConfig,
-		dynSsz:    beaconutils.GetDynSSZ(clConfig),
+		builderBase: newBuilderBase(elGenesis, clConfig),
 	}
 }
 
-func (b *altairBuilder) SetShadowForkBlock(block *types.Block) {
-	b.shadowForkBlock = block
-}
-
-func (b *altairBuilder) AddValidators(val []*validators.Validator) {
-	b.validators = append(b.validators, val...)
-}
-
 func (b *altairBuilder) BuildState() (*spec.VersionedBeaconState, error) {
-	genesisBlock := b.shadowForkBlock
-	if genesisBlock == nil {
-		genesisBlock = b.elGenesis.ToBlock()
-	}
-
-	genesisBlockHash := genesisBlock.Hash()
-
-	extra := genesisBlock.Extra()
-	if len(extra) > 32 {
-		return nil, fmt.Errorf("extra data is %d bytes, max is %d", len(extra), 32)
-	}
+	b.diagnostics.reset()
 
-	depositRoot, err := beaconutils.ComputeDepositRoot(b.clConfig)
+	genesis, err := b.prepareGenesis(spec.DataVersionAltair)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute deposit root: %w", err)
+		return nil, err
 	}
 
 	syncCommitteeSize := b.clConfig.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)
@@ -75,47 +47,54 @@ func (b *altairBuilder) BuildState() (*spec.VersionedBeaconState, error) {
 		},
 	}
 
-	genesisBlockBodyRoot, err := b.dynSsz.HashTreeRoot(genesisBlockBody)
+	genesisBlockBodyRoot, err := b.rooter.HashTreeRoot(genesisBlockBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute genesis block body root: %w", err)
 	}
 
-	clValidators, validatorsRoot := beaconutils.GetGenesisValidators(b.clConfig, b.validators)
+	clValidators, validatorsRoot, err := b.getGenesisValidators(genesis.validators)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkExpectedValidatorsRoot(b.clConfig, validatorsRoot); err != nil {
+		return nil, err
+	}
 
-	syncCommittee, err := beaconutils.GetGenesisSyncCommittee(b.clConfig, clValidators, phase0.Hash32(genesisBlockHash))
+	proposerIndex, err := getGenesisProposerIndex(b.clConfig, len(clValidators))
+	if err != nil {
+		return nil, err
+	}
+
+	syncCommittee, err := beaconutils.GetGenesisSyncCommittee(b.clConfig, clValidators, phase0.Hash32(genesis.blockHash), b.diagnostics.helperWarn())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get genesis sync committee: %w", err)
 	}
 
-	genesisDelay := b.clConfig.GetUintDefault("GENESIS_DELAY", 604800)
 	blocksPerHistoricalRoot := b.clConfig.GetUintDefault("SLOTS_PER_HISTORICAL_ROOT", 8192)
 	epochsPerSlashingVector := b.clConfig.GetUintDefault("EPOCHS_PER_SLASHINGS_VECTOR", 8192)
 
-	minGenesisTime := b.clConfig.GetUintDefault("MIN_GENESIS_TIME", 0)
-	if minGenesisTime == 0 {
-		minGenesisTime = genesisBlock.Time()
-	}
+	genesisTime, genesisTimeSource := getGenesisTime(b.clConfig, b.diagnostics, genesis.block)
 
 	genesisState := &altair.BeaconState{
-		GenesisTime:           minGenesisTime + genesisDelay,
+		GenesisTime:           genesisTime,
 		GenesisValidatorsRoot: validatorsRoot,
 		Fork:                  GetStateForkConfig(spec.DataVersionAltair, b.clConfig),
 		LatestBlockHeader: &phase0.BeaconBlockHeader{
-			BodyRoot: genesisBlockBodyRoot,
-		},
-		BlockRoots: make([]phase0.Root, blocksPerHistoricalRoot),
-		StateRoots: make([]phase0.Root, blocksPerHistoricalRoot),
-		ETH1Data: &phase0.ETH1Data{
-			DepositRoot: depositRoot,
-			BlockHash:   genesisBlockHash[:],
+			ProposerIndex: proposerIndex,
+			BodyRoot:      genesisBlockBodyRoot,
 		},
+		BlockRoots:                  make([]phase0.Root, blocksPerHistoricalRoot),
+		StateRoots:                  make([]phase0.Root, blocksPerHistoricalRoot),
+		HistoricalRoots:             []phase0.Root{},
+		ETH1Data:                    genesis.eth1Data,
 		JustificationBits:           make([]byte, 1),
 		PreviousJustifiedCheckpoint: &phase0.Checkpoint{},
 		CurrentJustifiedCheckpoint:  &phase0.Checkpoint{},
 		FinalizedCheckpoint:         &phase0.Checkpoint{},
-		RANDAOMixes:                 beaconutils.SeedRandomMixes(phase0.Hash32(genesisBlockHash), b.clConfig),
+		RANDAOMixes:                 beaconutils.SeedRandomMixes(phase0.Hash32(genesis.blockHash), b.clConfig),
 		Validators:                  clValidators,
-		Balances:                    beaconutils.GetGenesisBalances(b.clConfig, b.validators),
+		Balances:                    genesis.balances,
 		Slashings:                   make([]phase0.Gwei, epochsPerSlashingVector),
 		PreviousEpochParticipation:  make([]altair.ParticipationFlags, len(clValidators)),
 		CurrentEpochParticipation:   make([]altair.ParticipationFlags, len(clValidators)),
@@ -124,31 +103,62 @@ func (b *altairBuilder) BuildState() (*spec.VersionedBeaconState, error) {
 		NextSyncCommittee:           syncCommittee,
 	}
 
-	beaconutils.ApplyTEEToHeaderFromConfig(genesisState.LatestBlockHeader, b.clConfig, b.validators)
+	if err := b.applyGenesisTEE(spec.DataVersionAltair, genesisState.LatestBlockHeader, genesis, validatorsRoot); err != nil {
+		return nil, err
+	}
+
+	if err := checkValidatorListLengths(len(genesisState.Validators),
+		validatorListLength{"balances", len(genesisState.Balances)},
+		validatorListLength{"previous_epoch_participation", len(genesisState.PreviousEpochParticipation)},
+		validatorListLength{"current_epoch_participation", len(genesisState.CurrentEpochParticipation)},
+		validatorListLength{"inactivity_scores", len(genesisState.InactivityScores)},
+	); err != nil {
+		return nil, err
+	}
 
 	versionedState := &spec.VersionedBeaconState{
 		Version: spec.DataVersionAltair,
 		Altair:  genesisState,
 	}
 
-	logrus.Infof("genesis version: altair")
-	logrus.Infof("genesis time: %v", genesisState.GenesisTime)
-	logrus.Infof("genesis validators root: 0x%x", genesisState.GenesisValidatorsRoot)
+	blockRoot, err := buildBlockRoot(b.rooter, versionedState)
+	if err != nil {
+		return nil, err
+	}
+
+	LogForkConfig(spec.DataVersionAltair, b.clConfig)
+	logBuiltState(spec.DataVersionAltair, genesisState.GenesisTime, genesisState.GenesisValidatorsRoot, blockRoot)
+	if err := b.recordBuildStats(genesisState.Validators, genesisState.Balances, genesisTimeSource); err != nil {
+		return nil, err
+	}
 
 	return versionedState, nil
 }
 
 func (b *altairBuilder) Serialize(state *spec.VersionedBeaconState, contentType http.ContentType) ([]byte, error) {
 	if state.Version != spec.DataVersionAltair {
-		return nil, fmt.Errorf("unsupported version: %s", state.Version)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
 	}
 
+	var (
+		data []byte
+		err  error
+	)
+
 	switch contentType {
 	case http.ContentTypeSSZ:
-		return b.dynSsz.MarshalSSZ(state.Altair)
+		data, err = b.dynSsz.MarshalSSZ(state.Altair)
 	case http.ContentTypeJSON:
-		return state.Altair.MarshalJSON()
+		data, err = state.Altair.MarshalJSON()
 	default:
-		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	logSerializedState(b.dynSsz, state, contentType, len(data))
+
+	return data, nil
 }
@@ -0,0 +1,38 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestAltairBuilderWithoutExecutionGenesis(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionAltair, map[string]interface{}{
+		"MIN_GENESIS_TIME": uint64(1_800_000_000),
+	})
+
+	builder := NewAltairBuilder(nil, cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Version != spec.DataVersionAltair {
+		t.Fatalf("unexpected state version: %s", state.Version)
+	}
+
+	if state.Altair.GenesisTime != 1_800_000_000+604800 {
+		t.Errorf("unexpected genesis time: got %d, want %d", state.Altair.GenesisTime, 1_800_000_000+604800)
+	}
+
+	if state.Altair.CurrentSyncCommittee == nil || len(state.Altair.CurrentSyncCommittee.Pubkeys) == 0 {
+		t.Errorf("expected a populated sync committee")
+	}
+
+	if _, err := builder.Serialize(state, http.ContentTypeSSZ); err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package beaconchain
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// appendedValidatorData holds the validator dependent state fields recomputed for the combined validator set.
+type appendedValidatorData struct {
+	validators     []*phase0.Validator
+	validatorsRoot phase0.Root
+	balances       []phase0.Gwei
+	eth1Data       *phase0.ETH1Data
+	syncCommittee  *altair.SyncCommittee
+}
+
+// AppendValidatorsToState returns a copy of a genesis state with newVals appended to its validator set.
+// Balances, participation, inactivity scores, sync committees, proposer lookahead and the deposit root are
+// recomputed, so the result equals a genesis state built from the combined validator set.
+// The input state is not modified.
+func AppendValidatorsToState(state *spec.VersionedBeaconState, newVals []*validators.Validator, cfg *beaconconfig.Config) (*spec.VersionedBeaconState, error) {
+	vals, err := state.Validators()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state validators: %w", err)
+	}
+
+	balances, err := state.ValidatorBalances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state balances: %w", err)
+	}
+
+	eth1Data, err := stateETH1Data(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) != len(balances) {
+		return nil, fmt.Errorf("state has %d validators but %d balances", len(vals), len(balances))
+	}
+
+	combined := make([]*validators.Validator, 0, len(vals)+len(newVals))
+	pubkeyMap := make(map[phase0.BLSPubKey]bool, len(vals)+len(newVals))
+
+	// the vendor type of the existing validators is not part of the state, it only selects the proposer TEE
+	// vendor of the genesis block header, which is kept as is
+	for idx, val := range vals {
+		balance := uint64(balances[idx])
+		effectiveBalance := uint64(val.EffectiveBalance)
+
+		combined = append(combined, &validators.Validator{
+			PublicKey:             val.PublicKey,
+			WithdrawalCredentials: val.WithdrawalCredentials,
+			Balance:               &balance,
+			EffectiveBalance:      &effectiveBalance,
+		})
+		pubkeyMap[val.PublicKey] = true
+	}
+
+	for _, val := range newVals {
+		if pubkeyMap[val.PublicKey] {
+			return nil, fmt.Errorf("duplicate public key in validator set: %s", val.PublicKey.String())
+		}
+
+		combined = append(combined, val)
+		pubkeyMap[val.PublicKey] = true
+	}
+
+	combined = orderGenesisValidators(cfg, combined)
+
+	if err := checkValidatorRegistryLimit(cfg, len(combined)); err != nil {
+		return nil, err
+	}
+
+	data := &appendedValidatorData{
+		balances: beaconutils.GetGenesisBalances(cfg, combined),
+	}
+
+	data.validators, data.validatorsRoot, err = beaconutils.GetGenesisValidatorsWithOptions(cfg, combined, beaconutils.GenesisValidatorsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute genesis validators: %w", err)
+	}
+
+	depositRoot, err := beaconutils.ComputeDepositRoot(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute deposit root: %w", err)
+	}
+
+	data.eth1Data = &phase0.ETH1Data{
+		DepositRoot:  depositRoot,
+		DepositCount: eth1Data.DepositCount,
+		BlockHash:    eth1Data.BlockHash,
+	}
+
+	eth1BlockHash := phase0.Hash32(eth1Data.BlockHash)
+
+	if state.Version >= spec.DataVersionAltair {
+		data.syncCommittee, err = beaconutils.GetGenesisSyncCommittee(cfg, data.validators, eth1BlockHash, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get genesis sync committee: %w", err)
+		}
+	}
+
+	newState := &spec.VersionedBeaconState{
+		Version: state.Version,
+	}
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		stateCopy := *state.Phase0
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		newState.Phase0 = &stateCopy
+	case spec.DataVersionAltair:
+		stateCopy := *state.Altair
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		newState.Altair = &stateCopy
+	case spec.DataVersionBellatrix:
+		stateCopy := *state.Bellatrix
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		newState.Bellatrix = &stateCopy
+	case spec.DataVersionCapella:
+		stateCopy := *state.Capella
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		newState.Capella = &stateCopy
+	case spec.DataVersionDeneb:
+		stateCopy := *state.Deneb
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		newState.Deneb = &stateCopy
+	case spec.DataVersionElectra:
+		stateCopy := *state.Electra
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		newState.Electra = &stateCopy
+	case spec.DataVersionFulu:
+		proposers, err := beaconutils.GetGenesisProposers(cfg, data.validators, eth1BlockHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate proposer lookahead: %w", err)
+		}
+
+		stateCopy := *state.Fulu
+		stateCopy.GenesisValidatorsRoot, stateCopy.ETH1Data = data.validatorsRoot, data.eth1Data
+		stateCopy.Validators, stateCopy.Balances = data.validators, data.balances
+		stateCopy.PreviousEpochParticipation, stateCopy.CurrentEpochParticipation, stateCopy.InactivityScores = data.participation()
+		stateCopy.CurrentSyncCommittee, stateCopy.NextSyncCommittee = data.syncCommittee, data.syncCommittee
+		stateCopy.ProposerLookahead = proposers
+		newState.Fulu = &stateCopy
+	}
+
+	return newState, nil
+}
+
+// participation returns empty participation flags and inactivity scores for the combined validator set.
+func (d *appendedValidatorData) participation() (previous, current []altair.ParticipationFlags, inactivityScores []uint64) {
+	return make([]altair.ParticipationFlags, len(d.validators)),
+		make([]altair.ParticipationFlags, len(d.validators)),
+		make([]uint64, len(d.validators))
+}
+
+// stateETH1Data returns the eth1 data of the state, go-eth2-client has no versioned accessor for it.
+func stateETH1Data(state *spec.VersionedBeaconState) (*phase0.ETH1Data, error) {
+	var eth1Data *phase0.ETH1Data
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		eth1Data = state.Phase0.ETH1Data
+	case spec.DataVersionAltair:
+		eth1Data = state.Altair.ETH1Data
+	case spec.DataVersionBellatrix:
+		eth1Data = state.Bellatrix.ETH1Data
+	case spec.DataVersionCapella:
+		eth1Data = state.Capella.ETH1Data
+	case spec.DataVersionDeneb:
+		eth1Data = state.Deneb.ETH1Data
+	case spec.DataVersionElectra:
+		eth1Data = state.Electra.ETH1Data
+	case spec.DataVersionFulu:
+		eth1Data = state.Fulu.ETH1Data
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+
+	if eth1Data == nil {
+		return nil, fmt.Errorf("state has no eth1 data")
+	}
+
+	return eth1Data, nil
+}
@@ -0,0 +1,73 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestAppendValidatorsToState(t *testing.T) {
+	for _, version := range []spec.DataVersion{spec.DataVersionPhase0, spec.DataVersionDeneb, spec.DataVersionFulu} {
+		t.Run(version.String(), func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", version, map[string]interface{}{})
+			vals := createTestValidators(t, 12)
+
+			// the effective balance override of an existing validator must survive the append
+			balance, effectiveBalance := uint64(32_000_000_000), uint64(16_000_000_000)
+			vals[1].Balance, vals[1].EffectiveBalance = &balance, &effectiveBalance
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(vals[:8])
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			originalRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+			if err != nil {
+				t.Fatalf("failed to compute state root: %v", err)
+			}
+
+			appendedState, err := AppendValidatorsToState(state, vals[8:], cfg)
+			if err != nil {
+				t.Fatalf("failed to append validators: %v", err)
+			}
+
+			fullBuilder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			fullBuilder.AddValidators(vals)
+
+			fullState, err := fullBuilder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			appendedRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), appendedState)
+			if err != nil {
+				t.Fatalf("failed to compute state root: %v", err)
+			}
+
+			fullRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), fullState)
+			if err != nil {
+				t.Fatalf("failed to compute state root: %v", err)
+			}
+
+			if appendedRoot != fullRoot {
+				t.Fatalf("state root mismatch: appended %x, full rebuild %x", appendedRoot, fullRoot)
+			}
+
+			unchangedRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+			if err != nil {
+				t.Fatalf("failed to compute state root: %v", err)
+			}
+
+			if unchangedRoot != originalRoot {
+				t.Fatalf("input state was modified")
+			}
+
+			if _, err := AppendValidatorsToState(state, vals[:1], cfg); err == nil {
+				t.Fatalf("expected error for duplicate validator")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package beaconchain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestBellatrixBuilderMergeAtGenesis(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionBellatrix, map[string]interface{}{})
+
+	elGenesis := createTestELGenesis()
+	elGenesis.ExtraData = []byte("merge-at-genesis")
+	elGenesisBlock := elGenesis.ToBlock()
+
+	builder := NewBellatrixBuilder(elGenesis, cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Version != spec.DataVersionBellatrix {
+		t.Fatalf("unexpected state version: %s", state.Version)
+	}
+
+	execHeader := state.Bellatrix.LatestExecutionPayloadHeader
+	if execHeader == nil {
+		t.Fatalf("expected an execution payload header")
+	}
+
+	if execHeader.BlockHash != phase0.Hash32(elGenesisBlock.Hash()) {
+		t.Errorf("unexpected block hash: got %x, want %x", execHeader.BlockHash, elGenesisBlock.Hash())
+	}
+
+	if execHeader.StateRoot != phase0.Root(elGenesisBlock.Root()) {
+		t.Errorf("unexpected state root: got %x, want %x", execHeader.StateRoot, elGenesisBlock.Root())
+	}
+
+	if execHeader.GasLimit != elGenesis.GasLimit || execHeader.Timestamp != elGenesis.Timestamp {
+		t.Errorf("unexpected gas limit or timestamp: %d, %d", execHeader.GasLimit, execHeader.Timestamp)
+	}
+
+	if !bytes.Equal(execHeader.ExtraData, elGenesis.ExtraData) {
+		t.Errorf("unexpected extra data: %x", execHeader.ExtraData)
+	}
+
+	if !bytes.Equal(state.Bellatrix.ETH1Data.BlockHash, execHeader.BlockHash[:]) {
+		t.Errorf("expected eth1 block hash to match the execution block hash")
+	}
+
+	if _, err := builder.Serialize(state, http.ContentTypeSSZ); err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+}
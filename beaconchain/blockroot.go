@@ -0,0 +1,107 @@
+package beaconchain
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+// BuildBlockRoot returns the root of the genesis block of the state, hashing the state with mainnet preset sizes.
+// Use BuildBlockRootWithDynSSZ for states of other presets.
+func BuildBlockRoot(state *spec.VersionedBeaconState) (phase0.Root, error) {
+	return BuildBlockRootWithDynSSZ(dynssz.NewDynSsz(nil), state)
+}
+
+// BuildBlockRootWithDynSSZ returns the root of the genesis block of the state, using the given dynssz instance
+// to compute the state root. The genesis block is assembled from the latest block header of the state
+// (slot 0, zero parent root and the genesis body root) with the state root filled in.
+// The proposer TEE fields of the header are not part of the block and are not hashed.
+func BuildBlockRootWithDynSSZ(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) (phase0.Root, error) {
+	return buildBlockRoot(ds, state)
+}
+
+// genesisBlockContainer has the layout of the BeaconBlock container with the body replaced by its root,
+// so it hashes to the block root. Unlike the latest block header it has no proposer TEE fields.
+type genesisBlockContainer struct {
+	Slot          uint64
+	ProposerIndex uint64
+	ParentRoot    phase0.Root
+	StateRoot     phase0.Root
+	BodyRoot      phase0.Root
+}
+
+// buildBlockRoot works like BuildBlockRootWithDynSSZ, with the state and block roots computed by rooter.
+func buildBlockRoot(rooter HashTreeRooter, state *spec.VersionedBeaconState) (phase0.Root, error) {
+	header, err := getLatestBlockHeader(state)
+	if err != nil {
+		return phase0.Root{}, err
+	}
+
+	stateRoot, err := computeStateRoot(rooter, state)
+	if err != nil {
+		return phase0.Root{}, err
+	}
+
+	blockRoot, err := rooter.HashTreeRoot(&genesisBlockContainer{
+		Slot:          uint64(header.Slot),
+		ProposerIndex: uint64(header.ProposerIndex),
+		ParentRoot:    header.ParentRoot,
+		StateRoot:     stateRoot,
+		BodyRoot:      header.BodyRoot,
+	})
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to compute genesis block root: %w", err)
+	}
+
+	return blockRoot, nil
+}
+
+// GenesisCheckpoint returns the checkpoint clients bootstrap from: epoch 0 and the genesis block root.
+// Like BuildBlockRoot it hashes the state with mainnet preset sizes, use GenesisCheckpointWithDynSSZ for other presets.
+func GenesisCheckpoint(state *spec.VersionedBeaconState) (*phase0.Checkpoint, error) {
+	return GenesisCheckpointWithDynSSZ(dynssz.NewDynSsz(nil), state)
+}
+
+// GenesisCheckpointWithDynSSZ returns the genesis checkpoint, using the given dynssz instance to compute the block root.
+func GenesisCheckpointWithDynSSZ(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) (*phase0.Checkpoint, error) {
+	blockRoot, err := BuildBlockRootWithDynSSZ(ds, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &phase0.Checkpoint{
+		Epoch: 0,
+		Root:  blockRoot,
+	}, nil
+}
+
+func getLatestBlockHeader(state *spec.VersionedBeaconState) (*phase0.BeaconBlockHeader, error) {
+	var header *phase0.BeaconBlockHeader
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		header = state.Phase0.LatestBlockHeader
+	case spec.DataVersionAltair:
+		header = state.Altair.LatestBlockHeader
+	case spec.DataVersionBellatrix:
+		header = state.Bellatrix.LatestBlockHeader
+	case spec.DataVersionCapella:
+		header = state.Capella.LatestBlockHeader
+	case spec.DataVersionDeneb:
+		header = state.Deneb.LatestBlockHeader
+	case spec.DataVersionElectra:
+		header = state.Electra.LatestBlockHeader
+	case spec.DataVersionFulu:
+		header = state.Fulu.LatestBlockHeader
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+
+	if header == nil {
+		return nil, fmt.Errorf("state has no latest block header")
+	}
+
+	return header, nil
+}
@@ -0,0 +1,122 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/holiman/uint256"
+)
+
+func TestBuildBlockRoot(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	blockRoot, err := BuildBlockRootWithDynSSZ(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute block root: %v", err)
+	}
+
+	stateRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	// the genesis block as assembled by clients from the genesis state
+	genesisBlock := &deneb.BeaconBlock{
+		StateRoot: stateRoot,
+		Body: &deneb.BeaconBlockBody{
+			ETH1Data: &phase0.ETH1Data{
+				BlockHash: make([]byte, 32),
+			},
+			SyncAggregate: &altair.SyncAggregate{
+				SyncCommitteeBits: make([]byte, cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)/8),
+			},
+			ExecutionPayload: &deneb.ExecutionPayload{
+				BaseFeePerGas: uint256.NewInt(0),
+			},
+		},
+	}
+
+	expectedRoot, err := builder.(DynSSZProvider).DynSSZ().HashTreeRoot(genesisBlock)
+	if err != nil {
+		t.Fatalf("failed to hash genesis block: %v", err)
+	}
+
+	if blockRoot != expectedRoot {
+		t.Fatalf("block root mismatch: got %#x, want %#x", blockRoot, expectedRoot)
+	}
+
+	// the proposer TEE fields are not part of the block
+	state.Deneb.LatestBlockHeader.ProposerTEEType++
+	state.Deneb.LatestBlockHeader.ProposerTEEQuote[0]++
+
+	stateRootTEE, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	genesisBlock.StateRoot = stateRootTEE
+
+	expectedRoot, err = builder.(DynSSZProvider).DynSSZ().HashTreeRoot(genesisBlock)
+	if err != nil {
+		t.Fatalf("failed to hash genesis block: %v", err)
+	}
+
+	blockRoot, err = BuildBlockRootWithDynSSZ(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute block root: %v", err)
+	}
+
+	if blockRoot != expectedRoot {
+		t.Fatalf("block root mismatch after TEE change: got %#x, want %#x", blockRoot, expectedRoot)
+	}
+
+	if _, err := BuildBlockRoot(&spec.VersionedBeaconState{Version: spec.DataVersionDeneb, Deneb: &deneb.BeaconState{}}); err == nil {
+		t.Fatalf("expected error for state without latest block header")
+	}
+}
+
+func TestGenesisCheckpoint(t *testing.T) {
+	cfg := createTestConfig(t, "mainnet", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	checkpoint, err := GenesisCheckpoint(state)
+	if err != nil {
+		t.Fatalf("failed to compute genesis checkpoint: %v", err)
+	}
+
+	blockRoot, err := BuildBlockRoot(state)
+	if err != nil {
+		t.Fatalf("failed to compute block root: %v", err)
+	}
+
+	if checkpoint.Epoch != 0 || checkpoint.Root != blockRoot {
+		t.Fatalf("unexpected genesis checkpoint: got %d/%x, want 0/%x", checkpoint.Epoch, checkpoint.Root, blockRoot)
+	}
+
+	dynCheckpoint, err := GenesisCheckpointWithDynSSZ(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute genesis checkpoint: %v", err)
+	}
+
+	if dynCheckpoint.Root != checkpoint.Root {
+		t.Fatalf("mainnet checkpoint roots differ: %x != %x", dynCheckpoint.Root, checkpoint.Root)
+	}
+}
@@ -0,0 +1,195 @@
+package beaconchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	dynssz "github.com/pk910/dynamic-ssz"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// HashTreeRooter computes SSZ hash tree roots. The builders compute the genesis block body, validator registry,
+// state and block roots through it so tests can observe or replace the hashing, by default it is the builder's
+// dynssz instance. The default rooter reuses the validators root of the genesis validator conversion, which may
+// come from the validators cache or a shared conversion, instead of hashing the registry again.
+type HashTreeRooter interface {
+	HashTreeRoot(source interface{}) ([32]byte, error)
+}
+
+// builderBase holds the state and methods shared by all fork specific genesis builders.
+type builderBase struct {
+	elGenesis       *core.Genesis
+	clConfig        *beaconconfig.Config
+	dynSsz          *dynssz.DynSsz
+	rooter          HashTreeRooter
+	customRooter    bool
+	shadowForkBlock *types.Block
+
+	validatorsMutex sync.Mutex
+	validators      []*validators.Validator
+	// stateValidators holds the validators of the last built state in state order.
+	stateValidators []*validators.Validator
+
+	stats       *BuildStats
+	progress    beaconutils.ProgressFn
+	diagnostics *diagnosticCollector
+
+	// sharedValidators is set when several builders share the genesis validator conversion, see BuildStatesForELGeneses.
+	sharedValidators *sharedGenesisValidators
+	// validatorStream is set for staged builds, it replaces the validators added with AddValidators.
+	validatorStream *beaconutils.GenesisValidatorStream
+}
+
+func newBuilderBase(elGenesis *core.Genesis, clConfig *beaconconfig.Config) *builderBase {
+	ds := beaconutils.GetDynSSZ(clConfig)
+
+	return &builderBase{
+		elGenesis:   elGenesis,
+		clConfig:    clConfig,
+		dynSsz:      ds,
+		rooter:      ds,
+		diagnostics: &diagnosticCollector{},
+	}
+}
+
+func (b *builderBase) DynSSZ() *dynssz.DynSsz {
+	return b.dynSsz
+}
+
+// SetHashTreeRooter replaces the hash tree rooter of the builder, nil restores the dynssz instance. The sync committee
+// has no root of its own in the genesis state, it is hashed as part of the state root.
+func (b *builderBase) SetHashTreeRooter(rooter HashTreeRooter) {
+	b.customRooter = rooter != nil
+
+	if rooter == nil {
+		rooter = b.dynSsz
+	}
+
+	b.rooter = rooter
+}
+
+func (b *builderBase) SetShadowForkBlock(block *types.Block) {
+	b.shadowForkBlock = block
+}
+
+// AddValidators appends validators to the genesis validator set. It is safe for concurrent use,
+// but concurrent calls append in an unspecified order. Use validators.Accumulator to collect
+// validators from concurrent loaders in a deterministic order.
+func (b *builderBase) AddValidators(val []*validators.Validator) {
+	b.validatorsMutex.Lock()
+	defer b.validatorsMutex.Unlock()
+
+	b.validators = append(b.validators, val...)
+}
+
+// SetProgressCallback registers a callback that receives progress updates while the validators are processed.
+func (b *builderBase) SetProgressCallback(progress beaconutils.ProgressFn) {
+	b.progress = progress
+}
+
+// Stats returns the summary of the last built state, or nil if no state has been built yet.
+func (b *builderBase) Stats() *BuildStats {
+	return b.stats
+}
+
+// recordBuildStats computes and logs the summary of a built state.
+func (b *builderBase) recordBuildStats(vals []*phase0.Validator, balances []phase0.Gwei, genesisTimeSource GenesisTimeSource) error {
+	stats, err := newBuildStats(vals, balances)
+	if err != nil {
+		return err
+	}
+
+	b.stats = stats
+	b.stats.GenesisTimeSource = genesisTimeSource
+
+	b.stats.Diagnostics = b.diagnostics.list()
+
+	logBuildStats(b.stats)
+
+	return nil
+}
+
+// Diagnostics returns the warnings and errors recorded by the last BuildState call and the Serialize calls since,
+// including those of a build that failed.
+func (b *builderBase) Diagnostics() []Diagnostic {
+	return b.diagnostics.list()
+}
+
+// StateValidators returns the validators of the last built state in state order, i.e. sorted with
+// SORT_VALIDATORS_BY_PUBKEY and including the FILLER_VALIDATORS placeholders. Returns nil before the first build
+// and for staged builds, which do not keep the input validators.
+func (b *builderBase) StateValidators() []*validators.Validator {
+	return b.stateValidators
+}
+
+// getValidators returns a snapshot of the validators added so far.
+func (b *builderBase) getValidators() []*validators.Validator {
+	b.validatorsMutex.Lock()
+	defer b.validatorsMutex.Unlock()
+
+	vals := make([]*validators.Validator, len(b.validators))
+	copy(vals, b.validators)
+
+	return vals
+}
+
+// validatorRegistry is a container with the validator registry as single field, its root is the registry root.
+type validatorRegistry struct {
+	Validators []*phase0.Validator `ssz-max:"1099511627776" dynssz-max:"VALIDATOR_REGISTRY_LIMIT"`
+}
+
+// getGenesisValidators converts the validators to genesis validator records and returns them with the validators
+// root. The conversion is done once per shared validator set if the builder has one, staged builds take the records
+// and root from their validator stream. The registry is only hashed again if a custom rooter is set.
+func (b *builderBase) getGenesisValidators(vals []*validators.Validator) ([]*phase0.Validator, phase0.Root, error) {
+	var (
+		clValidators   []*phase0.Validator
+		validatorsRoot phase0.Root
+		err            error
+	)
+
+	if b.validatorStream == nil {
+		logValidatorsFingerprint(validators.Fingerprint(vals))
+	}
+
+	switch {
+	case b.validatorStream != nil:
+		clValidators, validatorsRoot, err = b.validatorStream.Finish(b.diagnostics.helperWarn())
+	case b.sharedValidators != nil:
+		clValidators, validatorsRoot, err = b.sharedValidators.get(b.clConfig, vals, b.progress, b.diagnostics.helperWarn())
+	default:
+		clValidators, validatorsRoot, err = beaconutils.GetGenesisValidatorsWithOptions(b.clConfig, vals, beaconutils.GenesisValidatorsOptions{
+			Progress: b.progress,
+			Warn:     b.diagnostics.helperWarn(),
+		})
+	}
+
+	if err != nil {
+		return nil, phase0.Root{}, fmt.Errorf("failed to compute genesis validators: %w", err)
+	}
+
+	if !b.customRooter {
+		return clValidators, validatorsRoot, nil
+	}
+
+	validatorsRoot, err = b.rooter.HashTreeRoot(&validatorRegistry{Validators: clValidators})
+	if err != nil {
+		return nil, phase0.Root{}, fmt.Errorf("failed to compute genesis validators root: %w", err)
+	}
+
+	return clValidators, validatorsRoot, nil
+}
+
+func (b *builderBase) setSharedValidators(shared *sharedGenesisValidators) {
+	b.sharedValidators = shared
+}
+
+func (b *builderBase) setValidatorStream(stream *beaconutils.GenesisValidatorStream) {
+	b.validatorStream = stream
+}
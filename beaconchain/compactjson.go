@@ -0,0 +1,125 @@
+package beaconchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+const compactJSONNotice = "lossy view: all-zero vectors and empty lists are omitted (see _elided), not for client consumption"
+
+// SerializeCompactJSON returns a lossy, human readable JSON view of the state.
+// Lists that are empty, null or only contain zero values (e.g. the 8192 entry block roots) are removed
+// and their paths are listed in the "_elided" field. The output must not be used as a genesis file.
+func SerializeCompactJSON(state *spec.VersionedBeaconState) ([]byte, error) {
+	jsonData, err := marshalStateJSON(state)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	fields := map[string]interface{}{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode state json: %w", err)
+	}
+
+	elided := []string{}
+	compactJSONObject("", fields, &elided)
+	sort.Strings(elided)
+
+	fields["_compact"] = compactJSONNotice
+	fields["_elided"] = elided
+
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+func marshalStateJSON(state *spec.VersionedBeaconState) ([]byte, error) {
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		return state.Phase0.MarshalJSON()
+	case spec.DataVersionAltair:
+		return state.Altair.MarshalJSON()
+	case spec.DataVersionBellatrix:
+		return state.Bellatrix.MarshalJSON()
+	case spec.DataVersionCapella:
+		return state.Capella.MarshalJSON()
+	case spec.DataVersionDeneb:
+		return state.Deneb.MarshalJSON()
+	case spec.DataVersionElectra:
+		return state.Electra.MarshalJSON()
+	case spec.DataVersionFulu:
+		return state.Fulu.MarshalJSON()
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+}
+
+func compactJSONObject(path string, fields map[string]interface{}, elided *[]string) {
+	for key, value := range fields {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		switch val := value.(type) {
+		case nil:
+			// nil lists are encoded as null
+			delete(fields, key)
+
+			*elided = append(*elided, fieldPath)
+		case []interface{}:
+			if isZeroJSONValue(val) {
+				delete(fields, key)
+
+				*elided = append(*elided, fieldPath)
+
+				continue
+			}
+
+			for idx, item := range val {
+				if obj, ok := item.(map[string]interface{}); ok {
+					compactJSONObject(fmt.Sprintf("%s[%d]", fieldPath, idx), obj, elided)
+				}
+			}
+		case map[string]interface{}:
+			compactJSONObject(fieldPath, val, elided)
+		}
+	}
+}
+
+func isZeroJSONValue(value interface{}) bool {
+	switch val := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case json.Number:
+		return strings.Trim(val.String(), "0") == ""
+	case string:
+		return strings.Trim(strings.TrimPrefix(val, "0x"), "0") == ""
+	case []interface{}:
+		for _, item := range val {
+			if !isZeroJSONValue(item) {
+				return false
+			}
+		}
+
+		return true
+	case map[string]interface{}:
+		for _, item := range val {
+			if !isZeroJSONValue(item) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
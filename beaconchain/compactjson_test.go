@@ -0,0 +1,66 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestSerializeCompactJSON(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	compactData, err := SerializeCompactJSON(state)
+	if err != nil {
+		t.Fatalf("failed to serialize compact json: %v", err)
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(compactData, &fields); err != nil {
+		t.Fatalf("failed to parse compact json: %v", err)
+	}
+
+	if fields["_compact"] == nil {
+		t.Fatalf("expected compact view to be marked")
+	}
+
+	elided := []string{}
+	for _, path := range fields["_elided"].([]interface{}) {
+		elided = append(elided, path.(string))
+	}
+
+	for _, key := range []string{"block_roots", "state_roots", "slashings", "historical_roots", "pending_deposits"} {
+		if _, found := fields[key]; found {
+			t.Errorf("expected %s to be elided", key)
+		}
+
+		if !slices.Contains(elided, key) {
+			t.Errorf("expected %s to be listed in _elided", key)
+		}
+	}
+
+	for _, key := range []string{"validators", "balances", "randao_mixes", "genesis_validators_root"} {
+		if _, found := fields[key]; !found {
+			t.Errorf("expected %s to be retained", key)
+		}
+	}
+
+	if vals := fields["validators"].([]interface{}); len(vals) != 8 {
+		t.Errorf("expected 8 validators, got %d", len(vals))
+	}
+
+	expectedRoot := fmt.Sprintf("%#x", state.Electra.GenesisValidatorsRoot)
+	if fields["genesis_validators_root"] != expectedRoot {
+		t.Errorf("unexpected validators root: got %v, want %s", fields["genesis_validators_root"], expectedRoot)
+	}
+}
@@ -0,0 +1,69 @@
+package beaconchain
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/core"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// defaultELGenesis is a post-merge execution genesis with all forks up to Prague active at genesis.
+//
+//go:embed defaults/genesis.json
+var defaultELGenesis []byte
+
+// DefaultELGenesis returns the embedded default execution genesis. It is meant for demos and tests,
+// not for real networks. Every call returns a fresh copy, so callers may modify it.
+func DefaultELGenesis() (*core.Genesis, error) {
+	var elGenesis core.Genesis
+
+	if err := json.NewDecoder(bytes.NewReader(defaultELGenesis)).Decode(&elGenesis); err != nil {
+		return nil, fmt.Errorf("failed to decode default execution genesis: %w", err)
+	}
+
+	return &elGenesis, nil
+}
+
+// BuildDefault builds a genesis state for the given fork from the embedded default execution genesis
+// and consensus config (see DefaultELGenesis and beaconconfig.Default), so only the validators are needed.
+// Forks up to version are activated at genesis, later forks are left unscheduled.
+func BuildDefault(version spec.DataVersion, vals []*validators.Validator) (*spec.VersionedBeaconState, error) {
+	if GetForkConfig(version) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, version)
+	}
+
+	elGenesis, err := DefaultELGenesis()
+	if err != nil {
+		return nil, err
+	}
+
+	clConfig, err := beaconconfig.Default()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default consensus config: %w", err)
+	}
+
+	farFutureEpoch := clConfig.GetUintDefault("FAR_FUTURE_EPOCH", 18446744073709551615)
+
+	for _, forkConfig := range ForkConfigs {
+		if forkConfig.EpochField == "" {
+			continue
+		}
+
+		if forkConfig.Version <= version {
+			clConfig.SetUint(forkConfig.EpochField, 0)
+		} else {
+			clConfig.SetUint(forkConfig.EpochField, farFutureEpoch)
+		}
+	}
+
+	builder := NewGenesisBuilder(elGenesis, clConfig)
+	builder.AddValidators(vals)
+
+	return builder.BuildState()
+}
@@ -0,0 +1,41 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+func TestBuildDefault(t *testing.T) {
+	for _, version := range []spec.DataVersion{spec.DataVersionPhase0, spec.DataVersionDeneb, spec.DataVersionElectra} {
+		t.Run(version.String(), func(t *testing.T) {
+			state, err := BuildDefault(version, createTestValidators(t, 4))
+			if err != nil {
+				t.Fatalf("failed to build default genesis: %v", err)
+			}
+
+			if state.Version != version {
+				t.Fatalf("unexpected state version: got %s, want %s", state.Version, version)
+			}
+
+			meta, err := NewGenesisMeta(dynssz.NewDynSsz(nil), state, "demo", "test")
+			if err != nil {
+				t.Fatalf("failed to collect genesis meta: %v", err)
+			}
+
+			if meta.GenesisTime != 1_700_000_060 {
+				t.Errorf("unexpected genesis time: got %d, want %d", meta.GenesisTime, 1_700_000_060)
+			}
+		})
+	}
+
+	elGenesis, err := DefaultELGenesis()
+	if err != nil {
+		t.Fatalf("failed to load default execution genesis: %v", err)
+	}
+
+	if elGenesis.Config.ChainID.Uint64() != 1337 {
+		t.Errorf("unexpected default chain id: %v", elGenesis.Config.ChainID)
+	}
+}
@@ -0,0 +1,131 @@
+package beaconchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+// DiagnosticSeverity is the severity of a build diagnostic.
+type DiagnosticSeverity string
+
+const (
+	DiagnosticSeverityWarning DiagnosticSeverity = "warning"
+	DiagnosticSeverityError   DiagnosticSeverity = "error"
+)
+
+// DiagnosticCode identifies the check that produced a diagnostic.
+type DiagnosticCode string
+
+const (
+	DiagnosticGenesisBlockNumber  DiagnosticCode = "genesis_block_number"
+	DiagnosticGenesisWithdrawals  DiagnosticCode = "genesis_withdrawals"
+	DiagnosticGenesisTransactions DiagnosticCode = "genesis_transactions"
+	DiagnosticGenesisStateRoot    DiagnosticCode = "genesis_state_root"
+	DiagnosticGenesisBlobSchedule DiagnosticCode = "genesis_blob_schedule"
+	DiagnosticGasLimit            DiagnosticCode = "gas_limit"
+	DiagnosticDepositContract     DiagnosticCode = "deposit_contract"
+	DiagnosticFillerValidators    DiagnosticCode = "filler_validators"
+	DiagnosticGenesisExtraData    DiagnosticCode = "genesis_extra_data"
+	DiagnosticGenesisTime         DiagnosticCode = "genesis_time"
+	DiagnosticSerialize           DiagnosticCode = "serialize"
+
+	// codes of the warnings raised by the beaconutils helpers, see diagnosticCollector.helperWarn
+	DiagnosticTEE                DiagnosticCode = beaconutils.WarnCodeTEE
+	DiagnosticSyncCommittee      DiagnosticCode = beaconutils.WarnCodeSyncCommittee
+	DiagnosticValidatorsRootSalt DiagnosticCode = beaconutils.WarnCodeValidatorsRootSalt
+	DiagnosticValidatorsCache    DiagnosticCode = beaconutils.WarnCodeValidatorsCache
+	DiagnosticRemoteFetch        DiagnosticCode = beaconutils.WarnCodeRemoteFetch
+)
+
+// diagnosticCodeField is the logrus field that carries the diagnostic code of a log entry.
+const diagnosticCodeField = "code"
+
+// Diagnostic is a warning or error recorded while a genesis state was built. Sanity checks that fail the build in
+// strict mode record an error, all other diagnostics are warnings.
+type Diagnostic struct {
+	Code     DiagnosticCode     `json:"code"`
+	Message  string             `json:"message"`
+	Severity DiagnosticSeverity `json:"severity"`
+}
+
+// diagnosticCollector records the warnings of a build as diagnostics. The warn sites of the builders call it
+// directly, the beaconutils helpers report their warnings through helperWarn. A nil collector logs without recording.
+type diagnosticCollector struct {
+	mutex       sync.Mutex
+	diagnostics []Diagnostic
+}
+
+// warn logs a warning with the given diagnostic code and records it.
+func (c *diagnosticCollector) warn(code DiagnosticCode, format string, args ...any) {
+	c.warnWithFields(code, nil, format, args...)
+}
+
+// warnWithFields is warn with additional log fields, the fields are not recorded.
+func (c *diagnosticCollector) warnWithFields(code DiagnosticCode, fields logrus.Fields, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	logrus.WithFields(fields).WithField(diagnosticCodeField, code).Warn(message)
+
+	c.record(Diagnostic{
+		Code:     code,
+		Message:  message,
+		Severity: DiagnosticSeverityWarning,
+	})
+}
+
+// helperWarn returns a beaconutils.WarnFn that logs and records the warnings of the beaconutils helpers under
+// their warning code.
+func (c *diagnosticCollector) helperWarn() beaconutils.WarnFn {
+	return func(code, message string) {
+		c.warn(DiagnosticCode(code), "%s", message)
+	}
+}
+
+// fail records an error diagnostic for a sanity check that fails the build. It is not logged, the caller
+// returns the error.
+func (c *diagnosticCollector) fail(code DiagnosticCode, message string) {
+	c.record(Diagnostic{
+		Code:     code,
+		Message:  message,
+		Severity: DiagnosticSeverityError,
+	})
+}
+
+func (c *diagnosticCollector) record(diagnostic Diagnostic) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.diagnostics = append(c.diagnostics, diagnostic)
+}
+
+// reset drops the diagnostics recorded so far, the builders call it at the start of each build.
+func (c *diagnosticCollector) reset() {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.diagnostics = nil
+}
+
+// list returns a copy of the diagnostics recorded so far.
+func (c *diagnosticCollector) list() []Diagnostic {
+	if c == nil {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]Diagnostic{}, c.diagnostics...)
+}
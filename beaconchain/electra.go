@@ -6,106 +6,39 @@ import (
 	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
-	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/attestantio/go-eth2-client/spec/deneb"
 	"github.com/attestantio/go-eth2-client/spec/electra"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/holiman/uint256"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
-	"github.com/ethpandaops/eth-beacon-genesis/validators"
-	dynssz "github.com/pk910/dynamic-ssz"
 )
 
 type electraBuilder struct {
-	elGenesis       *core.Genesis
-	clConfig        *beaconconfig.Config
-	dynSsz          *dynssz.DynSsz
-	shadowForkBlock *types.Block
-	validators      []*validators.Validator
+	*builderBase
 }
 
 func NewElectraBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config) BeaconGenesisBuilder {
 	return &electraBuilder{
-		elGenesis: elGenesis,
-		clConfig:  clConfig,
-		dynSsz:    beaconutils.GetDynSSZ(clConfig),
+		builderBase: newBuilderBase(elGenesis, clConfig),
 	}
 }
 
-func (b *electraBuilder) SetShadowForkBlock(block *types.Block) {
-	b.shadowForkBlock = block
-}
-
-func (b *electraBuilder) AddValidators(val []*validators.Validator) {
-	b.validators = append(b.validators, val...)
-}
-
 func (b *electraBuilder) BuildState() (*spec.VersionedBeaconState, error) {
-	genesisBlock := b.shadowForkBlock
-	if genesisBlock == nil {
-		genesisBlock = b.elGenesis.ToBlock()
-	}
-
-	genesisBlockHash := genesisBlock.Hash()
-
-	extra := genesisBlock.Extra()
-	if len(extra) > 32 {
-		return nil, fmt.Errorf("extra data is %d bytes, max is %d", len(extra), 32)
-	}
-
-	baseFee, _ := uint256.FromBig(genesisBlock.BaseFee())
-
-	var withdrawalsRoot phase0.Root
-
-	if genesisBlock.Withdrawals() != nil {
-		root, err := beaconutils.ComputeWithdrawalsRoot(genesisBlock.Withdrawals(), b.clConfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to compute withdrawals root: %w", err)
-		}
-
-		withdrawalsRoot = root
-	}
+	b.diagnostics.reset()
 
-	transactionsRoot, err := beaconutils.ComputeTransactionsRoot(genesisBlock.Transactions(), b.clConfig)
+	genesis, err := b.prepareGenesis(spec.DataVersionElectra)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute transactions root: %w", err)
+		return nil, err
 	}
 
-	if genesisBlock.BlobGasUsed() == nil {
-		return nil, fmt.Errorf("execution-layer Block has missing blob-gas-used field")
-	}
-
-	if genesisBlock.ExcessBlobGas() == nil {
-		return nil, fmt.Errorf("execution-layer Block has missing excess-blob-gas field")
-	}
-
-	execHeader := &deneb.ExecutionPayloadHeader{
-		ParentHash:       phase0.Hash32(genesisBlock.ParentHash()),
-		FeeRecipient:     bellatrix.ExecutionAddress(genesisBlock.Coinbase()),
-		StateRoot:        phase0.Root(genesisBlock.Root()),
-		ReceiptsRoot:     phase0.Root(genesisBlock.ReceiptHash()),
-		LogsBloom:        genesisBlock.Bloom(),
-		BlockNumber:      genesisBlock.NumberU64(),
-		GasLimit:         genesisBlock.GasLimit(),
-		GasUsed:          genesisBlock.GasUsed(),
-		Timestamp:        genesisBlock.Time(),
-		ExtraData:        extra,
-		BaseFeePerGas:    baseFee,
-		BlockHash:        phase0.Hash32(genesisBlockHash),
-		TransactionsRoot: transactionsRoot,
-		WithdrawalsRoot:  withdrawalsRoot,
-		BlobGasUsed:      *genesisBlock.BlobGasUsed(),
-		ExcessBlobGas:    *genesisBlock.ExcessBlobGas(),
-	}
-
-	depositRoot, err := beaconutils.ComputeDepositRoot(b.clConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to compute deposit root: %w", err)
+	execHeader, ok := genesis.executionHeader.(*deneb.ExecutionPayloadHeader)
+	if !ok {
+		return nil, fmt.Errorf("unexpected execution payload header type %T", genesis.executionHeader)
 	}
 
 	syncCommitteeSize := b.clConfig.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)
@@ -125,50 +58,58 @@ func (b *electraBuilder) BuildState() (*spec.VersionedBeaconState, error) {
 		ExecutionPayload: &deneb.ExecutionPayload{
 			BaseFeePerGas: uint256.NewInt(0),
 		},
-		ExecutionRequests: &electra.ExecutionRequests{},
+		ExecutionRequests: newGenesisExecutionRequests(),
 	}
 
-	genesisBlockBodyRoot, err := b.dynSsz.HashTreeRoot(genesisBlockBody)
+	genesisBlockBodyRoot, err := b.rooter.HashTreeRoot(genesisBlockBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute genesis block body root: %w", err)
 	}
 
-	clValidators, validatorsRoot := beaconutils.GetGenesisValidators(b.clConfig, b.validators)
+	clValidators, validatorsRoot, err := b.getGenesisValidators(genesis.validators)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkExpectedValidatorsRoot(b.clConfig, validatorsRoot); err != nil {
+		return nil, err
+	}
 
-	syncCommittee, err := beaconutils.GetGenesisSyncCommittee(b.clConfig, clValidators, phase0.Hash32(genesisBlockHash))
+	proposerIndex, err := getGenesisProposerIndex(b.clConfig, len(clValidators))
+	if err != nil {
+		return nil, err
+	}
+
+	syncCommittee, err := beaconutils.GetGenesisSyncCommittee(b.clConfig, clValidators, phase0.Hash32(genesis.blockHash), b.diagnostics.helperWarn())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get genesis sync committee: %w", err)
 	}
 
-	genesisDelay := b.clConfig.GetUintDefault("GENESIS_DELAY", 604800)
 	blocksPerHistoricalRoot := b.clConfig.GetUintDefault("SLOTS_PER_HISTORICAL_ROOT", 8192)
 	epochsPerSlashingVector := b.clConfig.GetUintDefault("EPOCHS_PER_SLASHINGS_VECTOR", 8192)
 
-	minGenesisTime := b.clConfig.GetUintDefault("MIN_GENESIS_TIME", 0)
-	if minGenesisTime == 0 {
-		minGenesisTime = genesisBlock.Time()
-	}
+	genesisTime, genesisTimeSource := getGenesisTime(b.clConfig, b.diagnostics, genesis.block)
 
 	genesisState := &electra.BeaconState{
-		GenesisTime:           minGenesisTime + genesisDelay,
+		GenesisTime:           genesisTime,
 		GenesisValidatorsRoot: validatorsRoot,
 		Fork:                  GetStateForkConfig(spec.DataVersionElectra, b.clConfig),
 		LatestBlockHeader: &phase0.BeaconBlockHeader{
-			BodyRoot: genesisBlockBodyRoot,
-		},
-		BlockRoots: make([]phase0.Root, blocksPerHistoricalRoot),
-		StateRoots: make([]phase0.Root, blocksPerHistoricalRoot),
-		ETH1Data: &phase0.ETH1Data{
-			DepositRoot: depositRoot,
-			BlockHash:   genesisBlockHash[:],
+			ProposerIndex: proposerIndex,
+			BodyRoot:      genesisBlockBodyRoot,
 		},
+		BlockRoots:                   make([]phase0.Root, blocksPerHistoricalRoot),
+		StateRoots:                   make([]phase0.Root, blocksPerHistoricalRoot),
+		HistoricalRoots:              []phase0.Root{},
+		HistoricalSummaries:          []*capella.HistoricalSummary{},
+		ETH1Data:                     genesis.eth1Data,
 		JustificationBits:            make([]byte, 1),
 		PreviousJustifiedCheckpoint:  &phase0.Checkpoint{},
 		CurrentJustifiedCheckpoint:   &phase0.Checkpoint{},
 		FinalizedCheckpoint:          &phase0.Checkpoint{},
-		RANDAOMixes:                  beaconutils.SeedRandomMixes(phase0.Hash32(genesisBlockHash), b.clConfig),
+		RANDAOMixes:                  beaconutils.SeedRandomMixes(phase0.Hash32(genesis.blockHash), b.clConfig),
 		Validators:                   clValidators,
-		Balances:                     beaconutils.GetGenesisBalances(b.clConfig, b.validators),
+		Balances:                     genesis.balances,
 		Slashings:                    make([]phase0.Gwei, epochsPerSlashingVector),
 		PreviousEpochParticipation:   make([]altair.ParticipationFlags, len(clValidators)),
 		CurrentEpochParticipation:    make([]altair.ParticipationFlags, len(clValidators)),
@@ -176,21 +117,42 @@ func (b *electraBuilder) BuildState() (*spec.VersionedBeaconState, error) {
 		CurrentSyncCommittee:         syncCommittee,
 		NextSyncCommittee:            syncCommittee,
 		LatestExecutionPayloadHeader: execHeader,
+		DepositRequestsStartIndex:    b.clConfig.GetUintDefault("UNSET_DEPOSIT_REQUESTS_START_INDEX", 18446744073709551615),
+		DepositBalanceToConsume:      phase0.Gwei(b.clConfig.GetUintDefault("GENESIS_DEPOSIT_BALANCE_TO_CONSUME", 0)),
+		ExitBalanceToConsume:         phase0.Gwei(b.clConfig.GetUintDefault("GENESIS_EXIT_BALANCE_TO_CONSUME", 0)),
+	}
+
+	if err := b.applyGenesisTEE(spec.DataVersionElectra, genesisState.LatestBlockHeader, genesis, validatorsRoot); err != nil {
+		return nil, err
 	}
 
-	beaconutils.ApplyTEEToHeaderFromConfig(genesisState.LatestBlockHeader, b.clConfig, b.validators)
+	if err := checkValidatorListLengths(len(genesisState.Validators),
+		validatorListLength{"balances", len(genesisState.Balances)},
+		validatorListLength{"previous_epoch_participation", len(genesisState.PreviousEpochParticipation)},
+		validatorListLength{"current_epoch_participation", len(genesisState.CurrentEpochParticipation)},
+		validatorListLength{"inactivity_scores", len(genesisState.InactivityScores)},
+	); err != nil {
+		return nil, err
+	}
 
 	// Log header size after TEE fields are applied
 	if genesisState.LatestBlockHeader != nil {
 		// Try to get SSZ size of header
 		headerSSZ, err := b.dynSsz.MarshalSSZ(genesisState.LatestBlockHeader)
 		if err == nil {
-			logrus.Infof("🔍 GENESIS GENERATOR: BeaconBlockHeader SSZ size after TEE application: %d bytes (expected TEE: 8305, standard: 112)", len(headerSSZ))
+			headerFields := logrus.Fields{
+				"header_size":          len(headerSSZ),
+				"expected_tee_size":    8305,
+				"expected_legacy_size": 112,
+			}
+
+			logrus.WithFields(headerFields).Debug("genesis block header SSZ size after TEE application")
+
 			if len(headerSSZ) != 8305 && len(headerSSZ) != 112 {
-				logrus.Warnf("⚠️  GENESIS GENERATOR: Unexpected header size %d bytes - neither TEE (8305) nor standard (112)", len(headerSSZ))
+				b.diagnostics.warnWithFields(DiagnosticSerialize, headerFields, "unexpected genesis block header SSZ size %d bytes, neither TEE nor standard header", len(headerSSZ))
 			}
 		} else {
-			logrus.Warnf("Failed to marshal header to SSZ for size check: %v", err)
+			b.diagnostics.warn(DiagnosticSerialize, "failed to marshal genesis block header to SSZ for size check: %v", err)
 		}
 	}
 
@@ -199,16 +161,23 @@ func (b *electraBuilder) BuildState() (*spec.VersionedBeaconState, error) {
 		Electra: genesisState,
 	}
 
-	logrus.Infof("genesis version: electra")
-	logrus.Infof("genesis time: %v", genesisState.GenesisTime)
-	logrus.Infof("genesis validators root: 0x%x", genesisState.GenesisValidatorsRoot)
+	blockRoot, err := buildBlockRoot(b.rooter, versionedState)
+	if err != nil {
+		return nil, err
+	}
+
+	LogForkConfig(spec.DataVersionElectra, b.clConfig)
+	logBuiltState(spec.DataVersionElectra, genesisState.GenesisTime, genesisState.GenesisValidatorsRoot, blockRoot)
+	if err := b.recordBuildStats(genesisState.Validators, genesisState.Balances, genesisTimeSource); err != nil {
+		return nil, err
+	}
 
 	return versionedState, nil
 }
 
 func (b *electraBuilder) Serialize(state *spec.VersionedBeaconState, contentType http.ContentType) ([]byte, error) {
 	if state.Version != spec.DataVersionElectra {
-		return nil, fmt.Errorf("unsupported version: %s", state.Version)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
 	}
 
 	switch contentType {
@@ -217,10 +186,10 @@ func (b *electraBuilder) Serialize(state *spec.VersionedBeaconState, contentType
 		if state.Electra != nil && state.Electra.LatestBlockHeader != nil {
 			headerSSZ, headerErr := b.dynSsz.MarshalSSZ(state.Electra.LatestBlockHeader)
 			if headerErr == nil {
-				logrus.Infof("🔍 GENESIS GENERATOR: BeaconBlockHeader SSZ size before state encoding: %d bytes (expected TEE: 8305, standard: 112)", len(headerSSZ))
+				logrus.WithField("header_size", len(headerSSZ)).Debug("genesis block header SSZ size before state encoding")
 			}
 		}
-		
+
 		// Use the generated MarshalSSZTo method directly to ensure correct offset calculation
 		// The generated code in beaconstate_ssz.go now calculates offsets dynamically based on actual header size
 		// Call MarshalSSZTo directly instead of MarshalSSZ to ensure our fixed offset calculation is used
@@ -228,37 +197,61 @@ func (b *electraBuilder) Serialize(state *spec.VersionedBeaconState, contentType
 		if err != nil {
 			return nil, err
 		}
-		
+
 		// Analyze the encoded SSZ to check offset values
 		// Calculate expected fixed portion: genesis_time(8) + genesis_validators_root(32) + slot(8) + fork(16) + header(variable) + BlockRoots(8192*32) + StateRoots(8192*32)
 		if state.Electra != nil && state.Electra.LatestBlockHeader != nil {
 			headerSize := state.Electra.LatestBlockHeader.SizeSSZ()
-			expectedFixedEnd := 8 + 32 + 8 + 16 + headerSize + 8192*32 + 8192*32 // All fixed-length fields
+			expectedFixedEnd := 8 + 32 + 8 + 16 + headerSize + 8192*32 + 8192*32  // All fixed-length fields
 			expectedFixedEndStandard := 8 + 32 + 8 + 16 + 112 + 8192*32 + 8192*32 // With standard 112-byte header
-			
-			logrus.Infof("🔍 GENESIS GENERATOR: Header size: %d bytes, Expected fixed portion ends at: %d (TEE) or %d (standard)", headerSize, expectedFixedEnd, expectedFixedEndStandard)
-			
+
+			offsetFields := logrus.Fields{
+				"header_size":             headerSize,
+				"fixed_end":               expectedFixedEnd,
+				"fixed_end_legacy_header": expectedFixedEndStandard,
+			}
+
+			logrus.WithFields(offsetFields).Debug("genesis state SSZ fixed portion")
+
 			// Read first offset (should be at position expectedFixedEnd)
-			if len(sszBytes) >= expectedFixedEnd + 4 {
+			if len(sszBytes) >= expectedFixedEnd+4 {
 				offset1 := uint32(sszBytes[expectedFixedEnd]) | uint32(sszBytes[expectedFixedEnd+1])<<8 | uint32(sszBytes[expectedFixedEnd+2])<<16 | uint32(sszBytes[expectedFixedEnd+3])<<24
-				logrus.Infof("🔍 GENESIS GENERATOR: First offset at position %d: %d (points to byte %d)", expectedFixedEnd, offset1, offset1)
-				
+				offsetFields["first_offset"] = offset1
+
 				if offset1 < uint32(expectedFixedEnd) {
-					logrus.Warnf("⚠️  GENESIS GENERATOR: Offset %d points INTO fixed portion (ends at %d). This suggests offset calculation assumed %d byte header instead of %d bytes", 
-						offset1, expectedFixedEnd, 112, headerSize)
+					b.diagnostics.warnWithFields(DiagnosticSerialize, offsetFields, "first genesis state SSZ offset %d points into the fixed portion, offsets assume a %d byte header instead of %d bytes",
+						offset1, 112, headerSize)
 				} else {
-					logrus.Infof("✅ GENESIS GENERATOR: Offset %d correctly points beyond fixed portion (ends at %d)", offset1, expectedFixedEnd)
+					logrus.WithFields(offsetFields).Debug("first genesis state SSZ offset points beyond the fixed portion")
 				}
 			} else {
-				logrus.Warnf("⚠️  GENESIS GENERATOR: SSZ bytes too short to read offset at position %d (have %d bytes)", expectedFixedEnd, len(sszBytes))
+				b.diagnostics.warnWithFields(DiagnosticSerialize, offsetFields, "genesis state SSZ is too short to read the first offset (%d bytes)", len(sszBytes))
 			}
 		}
-		
-		logrus.Infof("🔍 GENESIS GENERATOR: Total BeaconState SSZ size: %d bytes", len(sszBytes))
+
+		logSerializedState(b.dynSsz, state, contentType, len(sszBytes))
+
 		return sszBytes, nil
 	case http.ContentTypeJSON:
-		return state.Electra.MarshalJSON()
+		jsonBytes, err := state.Electra.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		logSerializedState(b.dynSsz, state, contentType, len(jsonBytes))
+
+		return jsonBytes, nil
 	default:
-		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
+
+// newGenesisExecutionRequests returns the execution requests of the genesis block body. Every request list of the
+// electra spec is listed explicitly as empty list, a new field in the spec type shows up in the body root test.
+func newGenesisExecutionRequests() *electra.ExecutionRequests {
+	return &electra.ExecutionRequests{
+		Deposits:       []*electra.DepositRequest{},
+		Withdrawals:    []*electra.WithdrawalRequest{},
+		Consolidations: []*electra.ConsolidationRequest{},
 	}
 }
@@ -0,0 +1,257 @@
+package beaconchain
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+func TestElectraDepositRequestsStartIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]interface{}
+		expected uint64
+	}{
+		{
+			name:     "default sentinel",
+			values:   map[string]interface{}{},
+			expected: 18446744073709551615,
+		},
+		{
+			name: "configured sentinel",
+			values: map[string]interface{}{
+				"UNSET_DEPOSIT_REQUESTS_START_INDEX": uint64(1 << 62),
+			},
+			expected: 1 << 62,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, tt.values)
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 8))
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			if state.Electra.DepositRequestsStartIndex != tt.expected {
+				t.Fatalf("unexpected deposit requests start index: got %d, want %d", state.Electra.DepositRequestsStartIndex, tt.expected)
+			}
+		})
+	}
+}
+
+func TestElectraBalanceToConsume(t *testing.T) {
+	buildState := func(values map[string]interface{}) (*electraBuilder, *spec.VersionedBeaconState) {
+		cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, values)
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(createTestValidators(t, 8))
+
+		state, err := builder.BuildState()
+		if err != nil {
+			t.Fatalf("failed to build state: %v", err)
+		}
+
+		return builder.(*electraBuilder), state
+	}
+
+	_, defaultState := buildState(map[string]interface{}{})
+	if defaultState.Electra.DepositBalanceToConsume != 0 || defaultState.Electra.ExitBalanceToConsume != 0 {
+		t.Fatalf("expected zero balances to consume by default")
+	}
+
+	builder, seededState := buildState(map[string]interface{}{
+		"GENESIS_DEPOSIT_BALANCE_TO_CONSUME": uint64(64_000_000_000),
+		"GENESIS_EXIT_BALANCE_TO_CONSUME":    uint64(128_000_000_000),
+	})
+
+	if seededState.Electra.DepositBalanceToConsume != 64_000_000_000 {
+		t.Errorf("unexpected deposit balance to consume: %d", seededState.Electra.DepositBalanceToConsume)
+	}
+
+	if seededState.Electra.ExitBalanceToConsume != 128_000_000_000 {
+		t.Errorf("unexpected exit balance to consume: %d", seededState.Electra.ExitBalanceToConsume)
+	}
+
+	defaultRoot, err := builder.dynSsz.HashTreeRoot(defaultState.Electra)
+	if err != nil {
+		t.Fatalf("failed to hash default state: %v", err)
+	}
+
+	seededRoot, err := builder.dynSsz.HashTreeRoot(seededState.Electra)
+	if err != nil {
+		t.Fatalf("failed to hash seeded state: %v", err)
+	}
+
+	if defaultRoot == seededRoot {
+		t.Errorf("expected state root to reflect the seeded balances")
+	}
+}
+
+func TestElectraSerializationRootsAgree(t *testing.T) {
+	cfg := createTestConfig(t, "mainnet", spec.DataVersionElectra, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	expectedRoot, err := state.Electra.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("failed to hash in-memory state: %v", err)
+	}
+
+	sszData, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state to SSZ: %v", err)
+	}
+
+	jsonData, err := builder.Serialize(state, http.ContentTypeJSON)
+	if err != nil {
+		t.Fatalf("failed to serialize state to JSON: %v", err)
+	}
+
+	sszState := &electra.BeaconState{}
+	if err := sszState.UnmarshalSSZ(sszData); err != nil {
+		t.Fatalf("failed to decode SSZ state: %v", err)
+	}
+
+	jsonState := &electra.BeaconState{}
+	if err := json.Unmarshal(jsonData, jsonState); err != nil {
+		t.Fatalf("failed to decode JSON state: %v", err)
+	}
+
+	sszRoot, err := sszState.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("failed to hash SSZ state: %v", err)
+	}
+
+	jsonRoot, err := jsonState.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("failed to hash JSON state: %v", err)
+	}
+
+	if sszRoot != jsonRoot {
+		t.Fatalf("SSZ and JSON state roots differ: %#x != %#x", sszRoot, jsonRoot)
+	}
+
+	if sszRoot != expectedRoot {
+		t.Fatalf("decoded state root %#x does not match the in-memory state root %#x", sszRoot, expectedRoot)
+	}
+
+	if jsonState.LatestBlockHeader.ProposerTEEType != state.Electra.LatestBlockHeader.ProposerTEEType ||
+		jsonState.LatestBlockHeader.ProposerTEEQuote != state.Electra.LatestBlockHeader.ProposerTEEQuote {
+		t.Fatalf("TEE fields were not preserved by the JSON round trip")
+	}
+}
+
+// TestElectraGenesisBodyRootGolden locks the genesis block body root with empty execution requests, so a change of
+// the spec types (e.g. a new request list) is caught instead of silently shifting the genesis body root.
+func TestElectraGenesisBodyRootGolden(t *testing.T) {
+	const emptyRequestsRoot = "0x85e253b40599d0df756be043ea6949e49a07e756deef72b3588a4b05362206b5"
+
+	tests := []struct {
+		preset   string
+		bodyRoot string
+	}{
+		{preset: "minimal", bodyRoot: "0xdf89c8202680d48494ded9d70338458aa3f056a8e85e109e271d218552149b14"},
+		{preset: "mainnet", bodyRoot: "0xca4f98890bc98a59f015d06375a5e00546b8f2ac1e88d31b1774ea28d4b3e7d1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			cfg := createTestConfig(t, tt.preset, spec.DataVersionElectra, map[string]interface{}{})
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			requestsRoot, err := builder.(DynSSZProvider).DynSSZ().HashTreeRoot(newGenesisExecutionRequests())
+			if err != nil {
+				t.Fatalf("failed to compute execution requests root: %v", err)
+			}
+
+			if phase0.Root(requestsRoot).String() != emptyRequestsRoot {
+				t.Fatalf("empty execution requests root changed: got %s, want %s", phase0.Root(requestsRoot).String(), emptyRequestsRoot)
+			}
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			if bodyRoot := state.Electra.LatestBlockHeader.BodyRoot.String(); bodyRoot != tt.bodyRoot {
+				t.Fatalf("genesis body root changed: got %s, want %s", bodyRoot, tt.bodyRoot)
+			}
+		})
+	}
+}
+
+func TestElectraHistoricalSummariesEmpty(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Electra.HistoricalSummaries == nil || len(state.Electra.HistoricalSummaries) != 0 {
+		t.Fatalf("expected empty non-nil historical summaries, got %v", state.Electra.HistoricalSummaries)
+	}
+
+	if state.Electra.HistoricalRoots == nil || len(state.Electra.HistoricalRoots) != 0 {
+		t.Fatalf("expected empty non-nil historical roots, got %v", state.Electra.HistoricalRoots)
+	}
+
+	// subtree root of the list as hashed in the state container
+	summariesRoot, err := beaconutils.HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
+		indx := hh.Index()
+
+		for _, summary := range state.Electra.HistoricalSummaries {
+			if err := summary.HashTreeRootWith(hh); err != nil {
+				return err
+			}
+		}
+
+		hh.MerkleizeWithMixin(indx, uint64(len(state.Electra.HistoricalSummaries)), cfg.GetUintDefault("HISTORICAL_ROOTS_LIMIT", 16777216))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to hash historical summaries: %v", err)
+	}
+
+	// canonical empty list root: the zero subtree of depth log2(HISTORICAL_ROOTS_LIMIT) mixed in with length 0
+	emptyListRoot := [32]byte{}
+	for i := 0; i < 24; i++ {
+		emptyListRoot = sha256.Sum256(append(emptyListRoot[:], emptyListRoot[:]...))
+	}
+
+	emptyListRoot = sha256.Sum256(append(emptyListRoot[:], make([]byte, 32)...))
+
+	if summariesRoot != emptyListRoot {
+		t.Fatalf("historical summaries root %x does not match the empty list root %x", summariesRoot, emptyListRoot)
+	}
+
+	if summariesRoot == ([32]byte{}) {
+		t.Fatalf("historical summaries root is zero")
+	}
+}
@@ -0,0 +1,61 @@
+package beaconchain
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// Per validator costs measured with BenchmarkBuildStateFillerValidators (Deneb, minimal preset).
+// The time does not include deriving validator keys from mnemonics, which is far more expensive per validator.
+const (
+	estimateBaseBuildTime           = 10 * time.Millisecond
+	estimateBuildTimePerValidator   = 9 * time.Microsecond
+	estimateHeapBytesPerValidator   = 730
+	estimateStateFixedFieldsSize    = 9216 // block header with the 8192 byte TEE quote, fork, eth1 data, checkpoints, ...
+	estimateValidatorRecordSize     = 121  // phase0.Validator
+	estimateValidatorBalanceSize    = 8
+	estimateValidatorAltairSizeDiff = 1 + 1 + 8 // epoch participation flags and inactivity score
+)
+
+// BuildCostEstimate is a rough estimate of the resources a genesis build needs.
+type BuildCostEstimate struct {
+	ValidatorCount uint64
+	// StateSize is the SSZ size of the genesis state in bytes.
+	StateSize uint64
+	// PeakMemory is the peak heap usage of the build in bytes, the state plus the per validator build overhead.
+	PeakMemory uint64
+	// Duration is the build time of the state, excluding loading the validators.
+	Duration time.Duration
+}
+
+// EstimateBuildCost estimates the state size, peak memory and build time of a genesis state with validatorCount
+// validators for the genesis fork and preset of cfg. The estimates scale linearly with the validator count and
+// are meant for sizing build machines, not as exact numbers.
+func EstimateBuildCost(validatorCount uint64, cfg *beaconconfig.Config) BuildCostEstimate {
+	version := GetGenesisForkVersion(cfg)
+
+	stateSize := uint64(estimateStateFixedFieldsSize)
+	stateSize += cfg.GetUintDefault("SLOTS_PER_HISTORICAL_ROOT", 8192) * 32 * 2 // block and state roots
+	stateSize += cfg.GetUintDefault("EPOCHS_PER_HISTORICAL_VECTOR", 65536) * 32 // randao mixes
+	stateSize += cfg.GetUintDefault("EPOCHS_PER_SLASHINGS_VECTOR", 8192) * 8
+
+	validatorSize := uint64(estimateValidatorRecordSize + estimateValidatorBalanceSize)
+
+	if version >= spec.DataVersionAltair {
+		// current and next sync committee
+		stateSize += 2 * (cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512) + 1) * 48
+		validatorSize += estimateValidatorAltairSizeDiff
+	}
+
+	stateSize += validatorCount * validatorSize
+
+	return BuildCostEstimate{
+		ValidatorCount: validatorCount,
+		StateSize:      stateSize,
+		PeakMemory:     stateSize + validatorCount*estimateHeapBytesPerValidator,
+		Duration:       estimateBaseBuildTime + time.Duration(validatorCount)*estimateBuildTimePerValidator, //nolint:gosec // no overflow for realistic counts
+	}
+}
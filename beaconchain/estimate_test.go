@@ -0,0 +1,48 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestEstimateBuildCost(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"FILLER_VALIDATORS": uint64(1000),
+	})
+
+	base := EstimateBuildCost(0, cfg)
+	small := EstimateBuildCost(10_000, cfg)
+	large := EstimateBuildCost(100_000, cfg)
+
+	// ten times the validators cost about ten times the resources on top of the base cost
+	checkLinear := func(name string, base, small, large float64) {
+		if ratio := (large - base) / (small - base); ratio < 9.9 || ratio > 10.1 {
+			t.Fatalf("%s does not scale linearly: %.0f for 10k, %.0f for 100k validators", name, small, large)
+		}
+	}
+
+	checkLinear("state size", float64(base.StateSize), float64(small.StateSize), float64(large.StateSize))
+	checkLinear("peak memory", float64(base.PeakMemory), float64(small.PeakMemory), float64(large.PeakMemory))
+	checkLinear("duration", float64(base.Duration), float64(small.Duration), float64(large.Duration))
+
+	// the state size estimate is close to the actual SSZ size
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	data, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+
+	estimate := EstimateBuildCost(1004, cfg)
+	if diff := int64(estimate.StateSize) - int64(len(data)); diff < -int64(len(data))/20 || diff > int64(len(data))/20 {
+		t.Fatalf("estimated state size %d differs more than 5%% from actual size %d", estimate.StateSize, len(data))
+	}
+}
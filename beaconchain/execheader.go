@@ -0,0 +1,161 @@
+package beaconchain
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/fulu"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+// stateExecutionPayloadHeaderTypes holds the LatestExecutionPayloadHeader field type of the beacon state of each post-merge fork.
+var stateExecutionPayloadHeaderTypes = map[spec.DataVersion]reflect.Type{
+	spec.DataVersionBellatrix: stateFieldType(bellatrix.BeaconState{}, "LatestExecutionPayloadHeader"),
+	spec.DataVersionCapella:   stateFieldType(capella.BeaconState{}, "LatestExecutionPayloadHeader"),
+	spec.DataVersionDeneb:     stateFieldType(deneb.BeaconState{}, "LatestExecutionPayloadHeader"),
+	spec.DataVersionElectra:   stateFieldType(electra.BeaconState{}, "LatestExecutionPayloadHeader"),
+	spec.DataVersionFulu:      stateFieldType(fulu.BeaconState{}, "LatestExecutionPayloadHeader"),
+}
+
+func stateFieldType(state any, name string) reflect.Type {
+	field, ok := reflect.TypeOf(state).FieldByName(name)
+	if !ok {
+		panic(fmt.Sprintf("%T has no field %s", state, name))
+	}
+
+	return field.Type
+}
+
+// buildExecutionPayloadHeader builds the execution payload header for the genesis state of the given fork
+// from the genesis execution block. The header type is selected by version and checked against the
+// LatestExecutionPayloadHeader field of the fork's beacon state.
+func buildExecutionPayloadHeader(version spec.DataVersion, cfg *beaconconfig.Config, genesisBlock *types.Block, genesisBlockHash common.Hash, extra []byte) (any, error) {
+	if _, ok := stateExecutionPayloadHeaderTypes[version]; !ok {
+		return nil, fmt.Errorf("%w: %s has no execution payload header", ErrUnsupportedVersion, version.String())
+	}
+
+	baseFee, _ := uint256.FromBig(genesisBlock.BaseFee())
+
+	transactionsRoot, err := beaconutils.ComputeTransactionsRoot(genesisBlock.Transactions(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute transactions root: %w", err)
+	}
+
+	var withdrawalsRoot phase0.Root
+
+	if version >= spec.DataVersionCapella && genesisBlock.Withdrawals() != nil {
+		root, err := beaconutils.ComputeWithdrawalsRoot(genesisBlock.Withdrawals(), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute withdrawals root: %w", err)
+		}
+
+		withdrawalsRoot = root
+	}
+
+	// pre-deneb headers store the base fee as little-endian bytes
+	baseFeeBytes := baseFee.Bytes32()
+	for i, j := 0, len(baseFeeBytes)-1; i < j; i, j = i+1, j-1 {
+		baseFeeBytes[i], baseFeeBytes[j] = baseFeeBytes[j], baseFeeBytes[i]
+	}
+
+	var header any
+
+	switch version {
+	case spec.DataVersionBellatrix:
+		header = &bellatrix.ExecutionPayloadHeader{
+			ParentHash:       phase0.Hash32(genesisBlock.ParentHash()),
+			FeeRecipient:     bellatrix.ExecutionAddress(genesisBlock.Coinbase()),
+			StateRoot:        phase0.Root(genesisBlock.Root()),
+			ReceiptsRoot:     phase0.Root(genesisBlock.ReceiptHash()),
+			LogsBloom:        genesisBlock.Bloom(),
+			PrevRandao:       genesisBlock.MixDigest(),
+			BlockNumber:      genesisBlock.NumberU64(),
+			GasLimit:         genesisBlock.GasLimit(),
+			GasUsed:          genesisBlock.GasUsed(),
+			Timestamp:        genesisBlock.Time(),
+			ExtraData:        extra,
+			BaseFeePerGas:    baseFeeBytes,
+			BlockHash:        phase0.Hash32(genesisBlockHash),
+			TransactionsRoot: transactionsRoot,
+		}
+	case spec.DataVersionCapella:
+		header = &capella.ExecutionPayloadHeader{
+			ParentHash:       phase0.Hash32(genesisBlock.ParentHash()),
+			FeeRecipient:     bellatrix.ExecutionAddress(genesisBlock.Coinbase()),
+			StateRoot:        phase0.Root(genesisBlock.Root()),
+			ReceiptsRoot:     phase0.Root(genesisBlock.ReceiptHash()),
+			LogsBloom:        genesisBlock.Bloom(),
+			PrevRandao:       genesisBlock.MixDigest(),
+			BlockNumber:      genesisBlock.NumberU64(),
+			GasLimit:         genesisBlock.GasLimit(),
+			GasUsed:          genesisBlock.GasUsed(),
+			Timestamp:        genesisBlock.Time(),
+			ExtraData:        extra,
+			BaseFeePerGas:    baseFeeBytes,
+			BlockHash:        phase0.Hash32(genesisBlockHash),
+			TransactionsRoot: transactionsRoot,
+			WithdrawalsRoot:  withdrawalsRoot,
+		}
+	case spec.DataVersionDeneb, spec.DataVersionElectra, spec.DataVersionFulu:
+		if genesisBlock.BlobGasUsed() == nil {
+			return nil, fmt.Errorf("execution-layer Block has missing blob-gas-used field")
+		}
+
+		if genesisBlock.ExcessBlobGas() == nil {
+			return nil, fmt.Errorf("execution-layer Block has missing excess-blob-gas field")
+		}
+
+		header = &deneb.ExecutionPayloadHeader{
+			ParentHash:       phase0.Hash32(genesisBlock.ParentHash()),
+			FeeRecipient:     bellatrix.ExecutionAddress(genesisBlock.Coinbase()),
+			StateRoot:        phase0.Root(genesisBlock.Root()),
+			ReceiptsRoot:     phase0.Root(genesisBlock.ReceiptHash()),
+			LogsBloom:        genesisBlock.Bloom(),
+			PrevRandao:       genesisBlock.MixDigest(),
+			BlockNumber:      genesisBlock.NumberU64(),
+			GasLimit:         genesisBlock.GasLimit(),
+			GasUsed:          genesisBlock.GasUsed(),
+			Timestamp:        genesisBlock.Time(),
+			ExtraData:        extra,
+			BaseFeePerGas:    baseFee,
+			BlockHash:        phase0.Hash32(genesisBlockHash),
+			TransactionsRoot: transactionsRoot,
+			WithdrawalsRoot:  withdrawalsRoot,
+			BlobGasUsed:      *genesisBlock.BlobGasUsed(),
+			ExcessBlobGas:    *genesisBlock.ExcessBlobGas(),
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s has no execution payload header", ErrUnsupportedVersion, version.String())
+	}
+
+	if err := checkExecutionPayloadHeaderType(version, header); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// checkExecutionPayloadHeaderType checks that header has the type the beacon state of the given fork expects.
+func checkExecutionPayloadHeaderType(version spec.DataVersion, header any) error {
+	expected, ok := stateExecutionPayloadHeaderTypes[version]
+	if !ok {
+		return fmt.Errorf("%w: %s has no execution payload header", ErrUnsupportedVersion, version.String())
+	}
+
+	if reflect.TypeOf(header) != expected {
+		return fmt.Errorf("execution payload header type mismatch for %s: got %T, state expects %s", version.String(), header, expected.String())
+	}
+
+	return nil
+}
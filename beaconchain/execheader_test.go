@@ -0,0 +1,106 @@
+package beaconchain
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestExecutionPayloadHeaderSelection(t *testing.T) {
+	tests := []struct {
+		version spec.DataVersion
+		check   func(header any) bool
+	}{
+		{version: spec.DataVersionCapella, check: func(h any) bool { _, ok := h.(*capella.ExecutionPayloadHeader); return ok }},
+		{version: spec.DataVersionDeneb, check: func(h any) bool { _, ok := h.(*deneb.ExecutionPayloadHeader); return ok }},
+		{version: spec.DataVersionElectra, check: func(h any) bool { _, ok := h.(*deneb.ExecutionPayloadHeader); return ok }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version.String(), func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", tt.version, nil)
+			block := createTestELGenesis().ToBlock()
+
+			header, err := buildExecutionPayloadHeader(tt.version, cfg, block, block.Hash(), block.Extra())
+			if err != nil {
+				t.Fatalf("failed to build execution payload header: %v", err)
+			}
+
+			if !tt.check(header) {
+				t.Fatalf("unexpected execution payload header type %T for %s", header, tt.version.String())
+			}
+		})
+	}
+
+	// a capella header does not match the deneb or electra state
+	for _, version := range []spec.DataVersion{spec.DataVersionDeneb, spec.DataVersionElectra} {
+		err := checkExecutionPayloadHeaderType(version, &capella.ExecutionPayloadHeader{})
+		if err == nil || !strings.Contains(err.Error(), "type mismatch") {
+			t.Fatalf("expected header type mismatch for %s, got %v", version.String(), err)
+		}
+	}
+
+	if _, err := buildExecutionPayloadHeader(spec.DataVersionAltair, nil, createTestELGenesis().ToBlock(), [32]byte{}, nil); err == nil {
+		t.Fatalf("expected error for pre-merge fork")
+	}
+}
+
+// TestExecutionPayloadHeaderCompleteness builds the header of each post-merge fork from a block without zero
+// values and checks that every header field is set, so fields added to a header type are not left zero.
+func TestExecutionPayloadHeaderCompleteness(t *testing.T) {
+	blobGasUsed := uint64(131072)
+	excessBlobGas := uint64(262144)
+
+	elHeader := &types.Header{
+		ParentHash:    common.HexToHash("0x01"),
+		Coinbase:      common.HexToAddress("0x02"),
+		Root:          common.HexToHash("0x03"),
+		ReceiptHash:   common.HexToHash("0x04"),
+		Bloom:         types.BytesToBloom([]byte{0x05}),
+		MixDigest:     common.HexToHash("0x06"),
+		Difficulty:    big.NewInt(0),
+		Number:        big.NewInt(6),
+		GasLimit:      30_000_000,
+		GasUsed:       21_000,
+		Time:          1_700_000_000,
+		Extra:         []byte("genesis"),
+		BaseFee:       big.NewInt(1_000_000_000),
+		BlobGasUsed:   &blobGasUsed,
+		ExcessBlobGas: &excessBlobGas,
+	}
+
+	block := types.NewBlockWithHeader(elHeader).WithBody(types.Body{
+		Withdrawals: []*types.Withdrawal{{Index: 1, Validator: 2, Address: common.HexToAddress("0x03"), Amount: 4}},
+	})
+
+	for _, version := range []spec.DataVersion{
+		spec.DataVersionBellatrix,
+		spec.DataVersionCapella,
+		spec.DataVersionDeneb,
+		spec.DataVersionElectra,
+		spec.DataVersionFulu,
+	} {
+		t.Run(version.String(), func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", version, nil)
+
+			header, err := buildExecutionPayloadHeader(version, cfg, block, block.Hash(), block.Extra())
+			if err != nil {
+				t.Fatalf("failed to build execution payload header: %v", err)
+			}
+
+			value := reflect.ValueOf(header).Elem()
+			for i := 0; i < value.NumField(); i++ {
+				if value.Field(i).IsZero() {
+					t.Errorf("%s execution payload header field %s is not set", version.String(), value.Type().Field(i).Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestFuluDepositRequestsStartIndex(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionFulu, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Fulu.DepositRequestsStartIndex != 18446744073709551615 {
+		t.Fatalf("unexpected deposit requests start index: got %d", state.Fulu.DepositRequestsStartIndex)
+	}
+}
@@ -1,14 +1,27 @@
 package beaconchain
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	hbls "github.com/herumi/bls-eth-go-binary/bls"
+	dynssz "github.com/pk910/dynamic-ssz"
+	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
 	"github.com/ethpandaops/eth-beacon-genesis/validators"
 )
 
@@ -21,6 +34,40 @@ type BeaconGenesisBuilder interface {
 	Serialize(state *spec.VersionedBeaconState, contentType http.ContentType) ([]byte, error)
 }
 
+// The following interfaces are optional builder features. All builders of this package implement them, builders
+// registered with RegisterBuilder may implement any of them. Check for them with a type assertion.
+
+// DynSSZProvider is implemented by builders that expose the dynamic ssz instance configured with their spec values.
+type DynSSZProvider interface {
+	DynSSZ() *dynssz.DynSsz
+}
+
+// ProgressReporter is implemented by builders that report progress while the validators are processed.
+type ProgressReporter interface {
+	SetProgressCallback(progress beaconutils.ProgressFn)
+}
+
+// StatsProvider is implemented by builders that summarize the last built state, nil if no state was built yet.
+type StatsProvider interface {
+	Stats() *BuildStats
+}
+
+// DiagnosticsProvider is implemented by builders that record diagnostics during BuildState and Serialize.
+type DiagnosticsProvider interface {
+	Diagnostics() []Diagnostic
+}
+
+// HashTreeRooterSetter is implemented by builders whose hash tree rooter can be replaced, nil restores the default.
+type HashTreeRooterSetter interface {
+	SetHashTreeRooter(rooter HashTreeRooter)
+}
+
+// StateValidatorsProvider is implemented by builders that return the validators of the last built state in state
+// order, including filler validators.
+type StateValidatorsProvider interface {
+	StateValidators() []*validators.Validator
+}
+
 type ForkConfig struct {
 	Version      spec.DataVersion
 	EpochField   string
@@ -73,6 +120,18 @@ var ForkConfigs = []ForkConfig{
 	},
 }
 
+var (
+	// ErrUnsupportedVersion is returned for states and forks that are not supported by the builders.
+	ErrUnsupportedVersion = errors.New("unsupported version")
+	// ErrUnsupportedContentType is returned when serializing to a content type other than SSZ or JSON.
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+var (
+	customBuildersMutex sync.RWMutex
+	customBuilders      = map[string]NewBeaconGenesisBuilderFn{}
+)
+
 func init() {
 	//nolint:errcheck // ignore
 	hbls.Init(hbls.BLS12_381)
@@ -100,28 +159,412 @@ func GetForkConfig(version spec.DataVersion) *ForkConfig {
 	return nil
 }
 
+// GetStateForkConfig returns the fork object of a genesis state for the given genesis fork.
+// Both previous and current version are the genesis fork version and the epoch is the genesis epoch,
+// independent of any later forks that are scheduled in the config.
 func GetStateForkConfig(version spec.DataVersion, cfg *beaconconfig.Config) *phase0.Fork {
-	thisForkConfig := GetForkConfig(version)
+	forkVersion, _ := cfg.GetBytes(GetForkConfig(version).VersionField)
+
+	return &phase0.Fork{
+		CurrentVersion:  phase0.Version(forkVersion),
+		PreviousVersion: phase0.Version(forkVersion),
+		Epoch:           0,
+	}
+}
 
-	var prevForkConfig *ForkConfig
+// ValidateForFork checks the fork schedule of cfg before a genesis state for the given fork is built.
+// The version of the genesis fork must be set, and all configured fork versions (GENESIS_FORK_VERSION,
+// ALTAIR_FORK_VERSION, ...) must be pairwise distinct, as reused versions break the fork digests.
+// SLOTS_PER_EPOCH must not be zero.
+func ValidateForFork(version spec.DataVersion, cfg *beaconconfig.Config) error {
+	forkConfig := GetForkConfig(version)
+	if forkConfig == nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedVersion, version)
+	}
 
-	if version == spec.DataVersionPhase0 {
-		prevForkConfig = thisForkConfig
-	} else {
-		prevForkConfig = GetForkConfig(version - 1)
+	if cfg.GetUintDefault("SLOTS_PER_EPOCH", 32) == 0 {
+		return fmt.Errorf("SLOTS_PER_EPOCH must not be zero")
 	}
 
-	thisForkVersion, _ := cfg.GetBytes(thisForkConfig.VersionField)
-	prevForkVersion, _ := cfg.GetBytes(prevForkConfig.VersionField)
+	if forkVersion, ok := cfg.GetBytes(forkConfig.VersionField); !ok || len(forkVersion) != 4 {
+		return fmt.Errorf("%s must be a 4 byte fork version", forkConfig.VersionField)
+	}
 
-	return &phase0.Fork{
-		CurrentVersion:  phase0.Version(thisForkVersion),
-		PreviousVersion: phase0.Version(prevForkVersion),
-		Epoch:           0,
+	versionFields := map[phase0.Version]string{}
+	collisions := []string{}
+
+	for _, forkConfig := range ForkConfigs {
+		forkVersion, ok := cfg.GetBytes(forkConfig.VersionField)
+		if !ok || len(forkVersion) != 4 {
+			continue
+		}
+
+		if field, found := versionFields[phase0.Version(forkVersion)]; found {
+			collisions = append(collisions, fmt.Sprintf("%s and %s are both %#x", field, forkConfig.VersionField, forkVersion))
+			continue
+		}
+
+		versionFields[phase0.Version(forkVersion)] = forkConfig.VersionField
+	}
+
+	if len(collisions) > 0 {
+		return fmt.Errorf("duplicate fork versions: %s", strings.Join(collisions, ", "))
+	}
+
+	return nil
+}
+
+// appendFillerValidators appends FILLER_VALIDATORS placeholder validators to the genesis validators, to study how
+// the validator count affects SSZ size and hashing time without deriving real keys.
+func appendFillerValidators(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, vals []*validators.Validator) []*validators.Validator {
+	fillerCount := fillerValidatorCount(cfg, diagnostics)
+	if fillerCount == 0 {
+		return vals
+	}
+
+	return append(vals, validators.GenerateFillerValidators(fillerCount)...)
+}
+
+// fillerValidatorCount returns the number of FILLER_VALIDATORS and records the diagnostic that comes with them.
+func fillerValidatorCount(cfg *beaconconfig.Config, diagnostics *diagnosticCollector) uint64 {
+	fillerCount := cfg.GetUintDefault("FILLER_VALIDATORS", 0)
+	if fillerCount != 0 {
+		diagnostics.warn(DiagnosticFillerValidators, "FILLER_VALIDATORS is set, genesis carries %d placeholder validators and is not usable for a real network", fillerCount)
+	}
+
+	return fillerCount
+}
+
+// orderGenesisValidators returns the validators in the order they are placed into the genesis state.
+// Input order is preserved unless SORT_VALIDATORS_BY_PUBKEY is enabled, in which case the validators are
+// sorted by public key so the resulting genesis does not depend on the order of the inputs.
+func orderGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator) []*validators.Validator {
+	if cfg.GetBoolDefault("SORT_VALIDATORS_BY_PUBKEY", false) {
+		return validators.SortByPublicKey(vals)
+	}
+
+	return vals
+}
+
+// validateGenesisWithdrawalAddresses checks the validators against ALLOWED_WITHDRAWAL_ADDRESSES, a comma separated
+// string or yaml list of execution addresses. The check is skipped if the key is not set.
+func validateGenesisWithdrawalAddresses(cfg *beaconconfig.Config, vals []*validators.Validator) error {
+	allowedList, found := cfg.GetStringList("ALLOWED_WITHDRAWAL_ADDRESSES")
+	if !found {
+		// a single address is parsed as hex bytes by the config loader
+		addrBytes, isBytes := cfg.GetBytes("ALLOWED_WITHDRAWAL_ADDRESSES")
+		if !isBytes {
+			return nil
+		}
+
+		allowedList = []string{common.Bytes2Hex(addrBytes)}
+	}
+
+	allowed := make([]common.Address, 0, len(allowedList))
+
+	for _, addr := range allowedList {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address in ALLOWED_WITHDRAWAL_ADDRESSES: %s", addr)
+		}
+
+		allowed = append(allowed, common.HexToAddress(addr))
 	}
+
+	return validators.ValidateWithdrawalAddresses(vals, allowed)
+}
+
+// validateGenesisDepositAmounts checks the validator deposit amounts against MIN_DEPOSIT_AMOUNT and the max effective
+// balance of the genesis fork if VALIDATE_DEPOSIT_AMOUNTS is enabled. With electra active at genesis, validators with
+// 0x02 withdrawal credentials may deposit up to MAX_EFFECTIVE_BALANCE_ELECTRA.
+func validateGenesisDepositAmounts(cfg *beaconconfig.Config, vals []*validators.Validator) error {
+	if !cfg.GetBoolDefault("VALIDATE_DEPOSIT_AMOUNTS", false) {
+		return nil
+	}
+
+	minDeposit := cfg.GetUintDefault("MIN_DEPOSIT_AMOUNT", 1_000_000_000)
+	maxEffectiveBalance := cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32_000_000_000)
+	maxCompoundingBalance := maxEffectiveBalance
+
+	if electraActivationEpoch, ok := cfg.GetUint("ELECTRA_FORK_EPOCH"); ok && electraActivationEpoch == 0 {
+		maxCompoundingBalance = cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE_ELECTRA", 2_048_000_000_000)
+	}
+
+	return validators.ValidateDepositAmounts(vals, minDeposit, maxEffectiveBalance, maxCompoundingBalance)
+}
+
+// checkExpectedValidatorsRoot compares the computed genesis validators root against EXPECTED_VALIDATORS_ROOT.
+// The check is skipped if the key is not set.
+func checkExpectedValidatorsRoot(cfg *beaconconfig.Config, validatorsRoot phase0.Root) error {
+	expectedRoot, found := cfg.GetBytes("EXPECTED_VALIDATORS_ROOT")
+	if !found {
+		return nil
+	}
+
+	if len(expectedRoot) != 32 {
+		return fmt.Errorf("EXPECTED_VALIDATORS_ROOT is %d bytes, expected 32", len(expectedRoot))
+	}
+
+	if !bytes.Equal(expectedRoot, validatorsRoot[:]) {
+		return fmt.Errorf("genesis validators root mismatch: computed 0x%x, expected 0x%x", validatorsRoot, expectedRoot)
+	}
+
+	return nil
+}
+
+// isTEEHeaderFork returns true if the proposer TEE fields are applied to the genesis block header of the given fork.
+// TEE_HEADER_FORKS restricts this to a comma separated list of fork names (e.g. "electra,fulu"), all forks
+// get the TEE fields if it is not set.
+func isTEEHeaderFork(cfg *beaconconfig.Config, version spec.DataVersion) bool {
+	teeHeaderForks, found := cfg.GetString("TEE_HEADER_FORKS")
+	if !found {
+		return true
+	}
+
+	for _, fork := range strings.Split(teeHeaderForks, ",") {
+		if strings.EqualFold(strings.TrimSpace(fork), version.String()) {
+			return true
+		}
+	}
+
+	logrus.WithField("version", version.String()).Info("fork is not listed in TEE_HEADER_FORKS, skipping proposer TEE fields")
+
+	return false
+}
+
+// checkGenesisExecutionBlockHash checks the genesis execution block hash (used for the execution payload header)
+// and the eth1 data block hash against GENESIS_EXECUTION_BLOCK_HASH, the block hash the EL computed for its genesis.
+// A mismatch means the EL genesis JSON differs from the one the EL clients use.
+func checkGenesisExecutionBlockHash(cfg *beaconconfig.Config, blockHash, eth1DataBlockHash []byte) error {
+	expectedHash, found := cfg.GetBytes("GENESIS_EXECUTION_BLOCK_HASH")
+	if !found {
+		return nil
+	}
+
+	if len(expectedHash) != 32 {
+		return fmt.Errorf("GENESIS_EXECUTION_BLOCK_HASH is %d bytes, expected 32", len(expectedHash))
+	}
+
+	if !bytes.Equal(expectedHash, blockHash) {
+		return fmt.Errorf("genesis execution block hash mismatch: derived 0x%x, expected 0x%x", blockHash, expectedHash)
+	}
+
+	if !bytes.Equal(expectedHash, eth1DataBlockHash) {
+		return fmt.Errorf("genesis eth1 data block hash mismatch: derived 0x%x, expected 0x%x", eth1DataBlockHash, expectedHash)
+	}
+
+	return nil
+}
+
+// checkGenesisBlockTimestamp checks that the execution genesis block timestamp equals MIN_GENESIS_TIME
+// if CHECK_EL_GENESIS_TIMESTAMP is enabled. Some merge-at-genesis setups require both to be aligned.
+func checkGenesisBlockTimestamp(cfg *beaconconfig.Config, genesisBlock *types.Block) error {
+	if !cfg.GetBoolDefault("CHECK_EL_GENESIS_TIMESTAMP", false) {
+		return nil
+	}
+
+	minGenesisTime := cfg.GetUintDefault("MIN_GENESIS_TIME", 0)
+	if genesisBlock.Time() != minGenesisTime {
+		return fmt.Errorf("execution genesis timestamp %d does not match MIN_GENESIS_TIME %d", genesisBlock.Time(), minGenesisTime)
+	}
+
+	return nil
+}
+
+// getGenesisProposerIndex returns the proposer index of the genesis latest block header.
+// It defaults to 0, GENESIS_PROPOSER_INDEX overrides it (e.g. for shadow forks replaying a specific header).
+func getGenesisProposerIndex(cfg *beaconconfig.Config, validatorCount int) (phase0.ValidatorIndex, error) {
+	proposerIndex := cfg.GetUintDefault("GENESIS_PROPOSER_INDEX", 0)
+	if proposerIndex != 0 && proposerIndex >= uint64(validatorCount) {
+		return 0, fmt.Errorf("GENESIS_PROPOSER_INDEX %d out of range (have %d validators)", proposerIndex, validatorCount)
+	}
+
+	return phase0.ValidatorIndex(proposerIndex), nil
+}
+
+// checkValidatorRegistryLimit checks that the genesis validator count fits into VALIDATOR_REGISTRY_LIMIT (default 2^40).
+// Clients reject states with a larger validator registry.
+func checkValidatorRegistryLimit(cfg *beaconconfig.Config, validatorCount int) error {
+	limit := cfg.GetUintDefault("VALIDATOR_REGISTRY_LIMIT", 1099511627776)
+	if uint64(validatorCount) > limit {
+		return fmt.Errorf("genesis has %d validators, VALIDATOR_REGISTRY_LIMIT is %d", validatorCount, limit)
+	}
+
+	return nil
+}
+
+// validatorListLength is the length of a per-validator list in the genesis state.
+type validatorListLength struct {
+	name   string
+	length int
+}
+
+// checkValidatorListLengths checks that all per-validator lists have one entry per validator.
+// Clients reject states where these lists differ in length.
+func checkValidatorListLengths(validatorCount int, lists ...validatorListLength) error {
+	mismatches := []string{}
+
+	for _, list := range lists {
+		if list.length != validatorCount {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %d", list.name, list.length))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("per-validator list length mismatch (validators: %d, %s)", validatorCount, strings.Join(mismatches, ", "))
+	}
+
+	return nil
+}
+
+// getGenesisBlock returns the execution block the genesis state is derived from.
+// For a true genesis GENESIS_EXTRA_DATA (hex, up to 32 bytes) overrides the extra data of the execution genesis.
+// The override changes the block hash, so the execution clients must be configured with the same extra data.
+func getGenesisBlock(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, elGenesis *core.Genesis, shadowForkBlock *types.Block) (*types.Block, error) {
+	extraData, hasExtraData := cfg.GetBytes("GENESIS_EXTRA_DATA")
+
+	if shadowForkBlock != nil {
+		if hasExtraData {
+			diagnostics.warn(DiagnosticGenesisExtraData, "GENESIS_EXTRA_DATA is ignored for shadow forks")
+		}
+
+		return shadowForkBlock, nil
+	}
+
+	if !hasExtraData {
+		return elGenesis.ToBlock(), nil
+	}
+
+	if len(extraData) > 32 {
+		return nil, fmt.Errorf("GENESIS_EXTRA_DATA is %d bytes, max is %d", len(extraData), 32)
+	}
+
+	genesis := *elGenesis
+	genesis.ExtraData = extraData
+
+	return genesis.ToBlock(), nil
+}
+
+// getEth1Block returns the eth1 block and block hash for pre-merge (phase0, altair) genesis states.
+// Without an execution genesis (nil elGenesis and no shadow fork block) an empty block with timestamp 0 is used
+// and the eth1 block hash is taken from GENESIS_ETH1_BLOCK_HASH (zero hash if unset).
+func getEth1Block(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, elGenesis *core.Genesis, shadowForkBlock *types.Block) (*types.Block, common.Hash, error) {
+	if elGenesis != nil || shadowForkBlock != nil {
+		block, err := getGenesisBlock(cfg, diagnostics, elGenesis, shadowForkBlock)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+
+		return block, block.Hash(), nil
+	}
+
+	blockHash := cfg.GetBytesDefault("GENESIS_ETH1_BLOCK_HASH", make([]byte, 32))
+	if len(blockHash) != 32 {
+		return nil, common.Hash{}, fmt.Errorf("GENESIS_ETH1_BLOCK_HASH is %d bytes, expected 32", len(blockHash))
+	}
+
+	block := types.NewBlockWithHeader(&types.Header{
+		Number:     new(big.Int),
+		Difficulty: new(big.Int),
+	})
+
+	return block, common.BytesToHash(blockHash), nil
+}
+
+// getGenesisETH1Data returns the eth1 data for the genesis state. If GENESIS_ETH1_DEPOSIT_ROOT,
+// GENESIS_ETH1_DEPOSIT_COUNT and GENESIS_ETH1_BLOCK_HASH are all set, they replace the computed values.
+// Setting only some of them is an error, except for GENESIS_ETH1_BLOCK_HASH on its own when the state is
+// built without an execution genesis, where getEth1Block already uses it as the eth1 block hash.
+func getGenesisETH1Data(cfg *beaconconfig.Config, depositRoot phase0.Root, blockHash []byte, withoutELGenesis bool) (*phase0.ETH1Data, error) {
+	overrideKeys := []string{"GENESIS_ETH1_DEPOSIT_ROOT", "GENESIS_ETH1_DEPOSIT_COUNT", "GENESIS_ETH1_BLOCK_HASH"}
+	missingKeys := make([]string, 0, len(overrideKeys))
+
+	for _, key := range overrideKeys {
+		if _, ok := cfg.Get(key); !ok {
+			missingKeys = append(missingKeys, key)
+		}
+	}
+
+	switch {
+	case len(missingKeys) == len(overrideKeys),
+		withoutELGenesis && len(missingKeys) == 2 && !slices.Contains(missingKeys, "GENESIS_ETH1_BLOCK_HASH"):
+		return &phase0.ETH1Data{
+			DepositRoot: depositRoot,
+			BlockHash:   blockHash,
+		}, nil
+	case len(missingKeys) > 0:
+		return nil, fmt.Errorf("partial eth1 data override, missing %s", strings.Join(missingKeys, ", "))
+	}
+
+	overrideRoot, ok := cfg.GetBytes("GENESIS_ETH1_DEPOSIT_ROOT")
+	if !ok || len(overrideRoot) != 32 {
+		return nil, fmt.Errorf("GENESIS_ETH1_DEPOSIT_ROOT must be a 32 byte hex value")
+	}
+
+	overrideCount, ok := cfg.GetUint("GENESIS_ETH1_DEPOSIT_COUNT")
+	if !ok {
+		return nil, fmt.Errorf("GENESIS_ETH1_DEPOSIT_COUNT must be an unsigned integer")
+	}
+
+	overrideHash, ok := cfg.GetBytes("GENESIS_ETH1_BLOCK_HASH")
+	if !ok || len(overrideHash) != 32 {
+		return nil, fmt.Errorf("GENESIS_ETH1_BLOCK_HASH must be a 32 byte hex value")
+	}
+
+	return &phase0.ETH1Data{
+		DepositRoot:  phase0.Root(overrideRoot),
+		DepositCount: overrideCount,
+		BlockHash:    overrideHash,
+	}, nil
+}
+
+// GenesisTimeSource names the config path that produced the genesis time of a built state.
+type GenesisTimeSource string
+
+const (
+	// GenesisTimeSourceExplicit is an explicit GENESIS_TIME override, used as is.
+	GenesisTimeSourceExplicit GenesisTimeSource = "explicit"
+	// GenesisTimeSourceMinGenesisTime is MIN_GENESIS_TIME plus GENESIS_DELAY.
+	GenesisTimeSourceMinGenesisTime GenesisTimeSource = "min-genesis+delay"
+	// GenesisTimeSourceELBlock is the execution block time plus GENESIS_DELAY.
+	GenesisTimeSourceELBlock GenesisTimeSource = "el-block"
+	// GenesisTimeSourceNow is the current time plus GENESIS_DELAY.
+	GenesisTimeSourceNow GenesisTimeSource = "now"
+)
+
+// getGenesisTime returns the genesis time and the config path it was derived from. An explicit GENESIS_TIME is used
+// as is, otherwise it is MIN_GENESIS_TIME plus GENESIS_DELAY. If MIN_GENESIS_TIME is unset or zero,
+// MIN_GENESIS_TIME_FALLBACK selects the execution block time ("block", default) or the current time ("now").
+// A GENESIS_DELAY explicitly set to 0 is honored, only an absent value falls back to the default of one week.
+func getGenesisTime(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block) (uint64, GenesisTimeSource) {
+	if genesisTime, found := cfg.GetUint("GENESIS_TIME"); found {
+		return genesisTime, GenesisTimeSourceExplicit
+	}
+
+	genesisDelay := cfg.GetUintDefault("GENESIS_DELAY", 604800)
+
+	minGenesisTime := cfg.GetUintDefault("MIN_GENESIS_TIME", 0)
+	if minGenesisTime != 0 {
+		return minGenesisTime + genesisDelay, GenesisTimeSourceMinGenesisTime
+	}
+
+	fallback, _ := cfg.GetString("MIN_GENESIS_TIME_FALLBACK")
+
+	switch strings.ToLower(fallback) {
+	case "now":
+		return uint64(time.Now().Unix()) + genesisDelay, GenesisTimeSourceNow //nolint:gosec // no overflow
+	case "", "block":
+	default:
+		diagnostics.warn(DiagnosticGenesisTime, "unknown MIN_GENESIS_TIME_FALLBACK %q, using execution block time", fallback)
+	}
+
+	return genesisBlock.Time() + genesisDelay, GenesisTimeSourceELBlock
 }
 
 func NewGenesisBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config) BeaconGenesisBuilder {
+	return newGenesisBuilder(elGenesis, clConfig, nil)
+}
+
+func newGenesisBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config, shared *sharedGenesisValidators) BeaconGenesisBuilder {
 	forkVersion := GetGenesisForkVersion(clConfig)
 	forkConfig := GetForkConfig(forkVersion)
 
@@ -129,5 +572,113 @@ func NewGenesisBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config) B
 		return nil
 	}
 
-	return forkConfig.BuilderFn(elGenesis, clConfig)
+	builder := forkConfig.BuilderFn(elGenesis, clConfig)
+
+	if sharer, ok := builder.(interface {
+		setSharedValidators(shared *sharedGenesisValidators)
+	}); ok && shared != nil {
+		sharer.setSharedValidators(shared)
+	}
+
+	return wrapBuilder(builder, clConfig)
+}
+
+// wrapBuilder wraps a fork builder with the config dependent decorators: state validation, the reference
+// state root check and strict mode. All builder constructors return builders wrapped by it.
+func wrapBuilder(builder BeaconGenesisBuilder, cfg *beaconconfig.Config) BeaconGenesisBuilder {
+	return withStrictMode(withReferenceStateRoot(withStateValidation(builder, cfg), cfg), cfg)
+}
+
+// builderDecorator is embedded by the builder decorators. It forwards the optional builder interfaces to the
+// wrapped builder. If the wrapped builder does not implement one, the setters do nothing, the getters return nil
+// and DynSSZ returns a dynssz instance for the config.
+type builderDecorator struct {
+	BeaconGenesisBuilder
+	clConfig *beaconconfig.Config
+}
+
+func (d *builderDecorator) DynSSZ() *dynssz.DynSsz {
+	if provider, ok := d.BeaconGenesisBuilder.(DynSSZProvider); ok {
+		return provider.DynSSZ()
+	}
+
+	return beaconutils.GetDynSSZ(d.clConfig)
+}
+
+func (d *builderDecorator) SetProgressCallback(progress beaconutils.ProgressFn) {
+	if reporter, ok := d.BeaconGenesisBuilder.(ProgressReporter); ok {
+		reporter.SetProgressCallback(progress)
+	}
+}
+
+func (d *builderDecorator) Stats() *BuildStats {
+	if provider, ok := d.BeaconGenesisBuilder.(StatsProvider); ok {
+		return provider.Stats()
+	}
+
+	return nil
+}
+
+func (d *builderDecorator) Diagnostics() []Diagnostic {
+	if provider, ok := d.BeaconGenesisBuilder.(DiagnosticsProvider); ok {
+		return provider.Diagnostics()
+	}
+
+	return nil
+}
+
+func (d *builderDecorator) SetHashTreeRooter(rooter HashTreeRooter) {
+	if setter, ok := d.BeaconGenesisBuilder.(HashTreeRooterSetter); ok {
+		setter.SetHashTreeRooter(rooter)
+	}
+}
+
+func (d *builderDecorator) StateValidators() []*validators.Validator {
+	if provider, ok := d.BeaconGenesisBuilder.(StateValidatorsProvider); ok {
+		return provider.StateValidators()
+	}
+
+	return nil
+}
+
+// RegisterBuilder registers a genesis builder factory for a custom or experimental fork that is not
+// known to go-eth2-client. Registered builders can be instantiated via NewBuilderNamed.
+// It panics if the name is empty, the factory is nil or the name is already registered.
+func RegisterBuilder(name string, factory NewBeaconGenesisBuilderFn) {
+	if name == "" {
+		panic("beaconchain: RegisterBuilder called with empty name")
+	}
+
+	if factory == nil {
+		panic("beaconchain: RegisterBuilder factory is nil for " + name)
+	}
+
+	customBuildersMutex.Lock()
+	defer customBuildersMutex.Unlock()
+
+	if _, found := customBuilders[name]; found {
+		panic("beaconchain: RegisterBuilder called twice for " + name)
+	}
+
+	customBuilders[name] = factory
+}
+
+// NewBuilderNamed creates a genesis builder by name. Builders registered via RegisterBuilder take
+// precedence, otherwise the name is matched against the built-in forks (e.g. "electra").
+func NewBuilderNamed(name string, elGenesis *core.Genesis, clConfig *beaconconfig.Config) (BeaconGenesisBuilder, error) {
+	customBuildersMutex.RLock()
+	factory, found := customBuilders[name]
+	customBuildersMutex.RUnlock()
+
+	if found {
+		return wrapBuilder(factory(elGenesis, clConfig), clConfig), nil
+	}
+
+	for _, forkConfig := range ForkConfigs {
+		if forkConfig.Version.String() == name {
+			return wrapBuilder(forkConfig.BuilderFn(elGenesis, clConfig), clConfig), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown genesis builder: %s", name)
 }
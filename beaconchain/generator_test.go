@@ -0,0 +1,1186 @@
+package beaconchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	hbls "github.com/herumi/bls-eth-go-binary/bls"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// createTestConfig writes the given values to a temporary config.yaml and loads it.
+// Fork versions and epochs are populated so that the given fork is active at genesis.
+func createTestConfig(t testing.TB, preset string, genesisFork spec.DataVersion, values map[string]interface{}) *beaconconfig.Config {
+	t.Helper()
+
+	yamlValues := map[string]interface{}{
+		"PRESET_BASE": preset,
+	}
+
+	for i, forkConfig := range ForkConfigs {
+		yamlValues[forkConfig.VersionField] = fmt.Sprintf("0x%02x000000", i)
+
+		if forkConfig.EpochField == "" {
+			continue
+		}
+
+		if forkConfig.Version <= genesisFork {
+			yamlValues[forkConfig.EpochField] = "0"
+		} else {
+			yamlValues[forkConfig.EpochField] = "18446744073709551615"
+		}
+	}
+
+	for k, v := range values {
+		switch val := v.(type) {
+		case uint64:
+			yamlValues[k] = fmt.Sprintf("%d", val)
+		case []byte:
+			yamlValues[k] = fmt.Sprintf("0x%x", val)
+		case string:
+			yamlValues[k] = val
+		case []string, []map[string]interface{}:
+			yamlValues[k] = val
+		default:
+			t.Fatalf("unsupported type for config value: %T", v)
+		}
+	}
+
+	yamlData, err := yaml.Marshal(yamlValues)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, yamlData, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := beaconconfig.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	return cfg
+}
+
+// createTestELGenesis returns a post-merge execution genesis with all forks active at genesis.
+func createTestELGenesis() *core.Genesis {
+	return &core.Genesis{
+		Config:    params.MergedTestChainConfig,
+		GasLimit:  30_000_000,
+		Timestamp: 1_700_000_000,
+	}
+}
+
+// createTestValidators returns count validators with valid, deterministic BLS public keys.
+func createTestValidators(t testing.TB, count int) []*validators.Validator {
+	t.Helper()
+
+	vals := make([]*validators.Validator, count)
+
+	for i := range vals {
+		var sk hbls.SecretKey
+		if err := sk.SetDecString(fmt.Sprintf("%d", i+1)); err != nil {
+			t.Fatalf("failed to create secret key: %v", err)
+		}
+
+		vals[i] = &validators.Validator{
+			PublicKey:             phase0.BLSPubKey(sk.GetPublicKey().Serialize()),
+			WithdrawalCredentials: make([]byte, 32),
+		}
+	}
+
+	return vals
+}
+
+type stubBuilder struct {
+	validators []*validators.Validator
+}
+
+func (b *stubBuilder) SetShadowForkBlock(_ *types.Block) {}
+
+func (b *stubBuilder) AddValidators(val []*validators.Validator) {
+	b.validators = append(b.validators, val...)
+}
+
+func (b *stubBuilder) BuildState() (*spec.VersionedBeaconState, error) {
+	return &spec.VersionedBeaconState{
+		Version: spec.DataVersionPhase0,
+		Phase0: &phase0.BeaconState{
+			Validators: make([]*phase0.Validator, len(b.validators)),
+		},
+	}, nil
+}
+
+func (b *stubBuilder) Serialize(_ *spec.VersionedBeaconState, _ http.ContentType) ([]byte, error) {
+	return []byte("stub"), nil
+}
+
+func TestNewBuilderNamed_Registered(t *testing.T) {
+	RegisterBuilder("pote-stub", func(_ *core.Genesis, _ *beaconconfig.Config) BeaconGenesisBuilder {
+		return &stubBuilder{}
+	})
+
+	builder, err := NewBuilderNamed("pote-stub", createTestELGenesis(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := builder.(*stubBuilder); !ok {
+		t.Fatalf("expected stub builder, got %T", builder)
+	}
+
+	builder.AddValidators(createTestValidators(t, 3))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if len(state.Phase0.Validators) != 3 {
+		t.Fatalf("expected 3 validators, got %d", len(state.Phase0.Validators))
+	}
+
+	// the stub only implements the required methods, the optional builder interfaces are not forced on it
+	if _, ok := builder.(StatsProvider); ok {
+		t.Fatalf("expected the stub builder to not implement StatsProvider")
+	}
+}
+
+func TestNewBuilderNamed_BuiltIn(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+
+	builder, err := NewBuilderNamed("electra", createTestELGenesis(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := builder.(*electraBuilder); !ok {
+		t.Fatalf("expected electra builder, got %T", builder)
+	}
+
+	// named builders get the same decorators as NewGenesisBuilder
+	cfg = createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"REFERENCE_STATE_ROOT": "0x" + strings.Repeat("00", 32),
+	})
+
+	builder, err = NewBuilderNamed("electra", createTestELGenesis(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := builder.(*referenceRootBuilder); !ok {
+		t.Fatalf("expected reference state root builder, got %T", builder)
+	}
+
+	// decorators forward the optional builder interfaces
+	if provider, ok := builder.(DynSSZProvider); !ok || provider.DynSSZ() == nil {
+		t.Fatalf("expected the decorated builder to provide its dynssz instance")
+	}
+}
+
+func TestNewBuilderNamed_Unknown(t *testing.T) {
+	if _, err := NewBuilderNamed("unknown-fork", createTestELGenesis(), nil); err == nil {
+		t.Fatalf("expected error for unknown builder")
+	}
+}
+
+func TestRegisterBuilder_Duplicate(t *testing.T) {
+	factory := func(_ *core.Genesis, _ *beaconconfig.Config) BeaconGenesisBuilder {
+		return &stubBuilder{}
+	}
+
+	RegisterBuilder("pote-duplicate", factory)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic on duplicate registration")
+		}
+	}()
+
+	RegisterBuilder("pote-duplicate", factory)
+}
+
+func TestSortValidatorsByPubkey(t *testing.T) {
+	vals := createTestValidators(t, 16)
+	reversed := make([]*validators.Validator, len(vals))
+
+	for i, val := range vals {
+		reversed[len(vals)-1-i] = val
+	}
+
+	buildRoot := func(sortValidators string, vals []*validators.Validator) phase0.Root {
+		cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+			"SORT_VALIDATORS_BY_PUBKEY": sortValidators,
+		})
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(vals)
+
+		state, err := builder.BuildState()
+		if err != nil {
+			t.Fatalf("failed to build state: %v", err)
+		}
+
+		return state.Electra.GenesisValidatorsRoot
+	}
+
+	if buildRoot("true", vals) != buildRoot("true", reversed) {
+		t.Fatalf("expected identical validators root in sorted mode")
+	}
+
+	if buildRoot("false", vals) == buildRoot("false", reversed) {
+		t.Fatalf("expected input order to be preserved by default")
+	}
+}
+
+func TestStateValidatorsManifest(t *testing.T) {
+	vals := createTestValidators(t, 16)
+	reversed := make([]*validators.Validator, len(vals))
+
+	for i, val := range vals {
+		reversed[len(vals)-1-i] = val
+	}
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"SORT_VALIDATORS_BY_PUBKEY": "true",
+		"FILLER_VALIDATORS":         uint64(4),
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(reversed)
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	var manifest bytes.Buffer
+	if err := validators.ExportManifest(builder.(StateValidatorsProvider).StateValidators(), &manifest, "json"); err != nil {
+		t.Fatalf("failed to export manifest: %v", err)
+	}
+
+	entries := []validators.ManifestEntry{}
+	if err := json.Unmarshal(manifest.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to parse manifest: %v", err)
+	}
+
+	if len(entries) != len(state.Electra.Validators) {
+		t.Fatalf("manifest has %d rows, state has %d validators", len(entries), len(state.Electra.Validators))
+	}
+
+	for i, entry := range entries {
+		stateValidator := state.Electra.Validators[i]
+
+		if entry.Index != i || entry.PublicKey != stateValidator.PublicKey.String() {
+			t.Fatalf("manifest row %d does not match state validator %d: %s vs %s", i, i, entry.PublicKey, stateValidator.PublicKey.String())
+		}
+
+		if entry.WithdrawalCredentials != fmt.Sprintf("%#x", stateValidator.WithdrawalCredentials) {
+			t.Fatalf("manifest row %d has withdrawal credentials %s, state has %#x", i, entry.WithdrawalCredentials, stateValidator.WithdrawalCredentials)
+		}
+	}
+}
+
+func TestGenesisDelay(t *testing.T) {
+	tests := []struct {
+		name         string
+		values       map[string]interface{}
+		expectedTime uint64
+	}{
+		{
+			name: "absent delay uses default",
+			values: map[string]interface{}{
+				"MIN_GENESIS_TIME": uint64(1_800_000_000),
+			},
+			expectedTime: 1_800_000_000 + 604800,
+		},
+		{
+			name: "explicit zero delay",
+			values: map[string]interface{}{
+				"MIN_GENESIS_TIME": uint64(1_800_000_000),
+				"GENESIS_DELAY":    uint64(0),
+			},
+			expectedTime: 1_800_000_000,
+		},
+		{
+			name: "block fallback",
+			values: map[string]interface{}{
+				"GENESIS_DELAY":             uint64(60),
+				"MIN_GENESIS_TIME_FALLBACK": "block",
+			},
+			expectedTime: createTestELGenesis().Timestamp + 60,
+		},
+		{
+			name: "explicit zero delay without min genesis time",
+			values: map[string]interface{}{
+				"GENESIS_DELAY": uint64(0),
+			},
+			expectedTime: createTestELGenesis().Timestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, tt.values)
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			if state.Phase0.GenesisTime != tt.expectedTime {
+				t.Fatalf("unexpected genesis time: got %d, want %d", state.Phase0.GenesisTime, tt.expectedTime)
+			}
+		})
+	}
+}
+
+func TestExpectedValidatorsRoot(t *testing.T) {
+	vals := createTestValidators(t, 4)
+
+	builder := NewGenesisBuilder(createTestELGenesis(), createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{}))
+	builder.AddValidators(vals)
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	validatorsRoot := state.Phase0.GenesisValidatorsRoot
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"EXPECTED_VALIDATORS_ROOT": validatorsRoot[:],
+	})
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(vals)
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("unexpected error with matching root: %v", err)
+	}
+
+	mismatchingRoot := make([]byte, 32)
+	mismatchingRoot[0] = 0x01
+
+	cfg = createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"EXPECTED_VALIDATORS_ROOT": mismatchingRoot,
+	})
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(vals)
+
+	_, err = builder.BuildState()
+	if err == nil {
+		t.Fatalf("expected error with mismatching root")
+	}
+
+	if !strings.Contains(err.Error(), fmt.Sprintf("computed 0x%x", validatorsRoot)) {
+		t.Fatalf("expected error to contain the computed root, got %v", err)
+	}
+}
+
+func TestGetStateForkConfig_MultiForkSchedule(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"ELECTRA_FORK_EPOCH": uint64(10),
+		"FULU_FORK_EPOCH":    uint64(20),
+	})
+
+	if version := GetGenesisForkVersion(cfg); version != spec.DataVersionDeneb {
+		t.Fatalf("unexpected genesis fork: got %s, want %s", version, spec.DataVersionDeneb)
+	}
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	denebVersion := phase0.Version(cfg.GetBytesDefault("DENEB_FORK_VERSION", nil))
+	fork := state.Deneb.Fork
+
+	if fork.CurrentVersion != denebVersion {
+		t.Errorf("unexpected current version: got %s, want %s", fork.CurrentVersion, denebVersion)
+	}
+
+	if fork.PreviousVersion != denebVersion {
+		t.Errorf("unexpected previous version: got %s, want %s", fork.PreviousVersion, denebVersion)
+	}
+
+	if fork.Epoch != 0 {
+		t.Errorf("unexpected fork epoch: got %d, want 0", fork.Epoch)
+	}
+}
+
+func TestAddValidatorsConcurrent(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"SORT_VALIDATORS_BY_PUBKEY": "true",
+	})
+
+	vals := createTestValidators(t, 16)
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < len(vals); i += 4 {
+		wg.Add(1)
+
+		go func(batch []*validators.Validator) {
+			defer wg.Done()
+
+			builder.AddValidators(batch)
+		}(vals[i : i+4])
+	}
+
+	wg.Wait()
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if len(state.Phase0.Validators) != len(vals) {
+		t.Fatalf("expected %d validators, got %d", len(vals), len(state.Phase0.Validators))
+	}
+
+	sequentialBuilder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	sequentialBuilder.AddValidators(vals)
+
+	sequentialState, err := sequentialBuilder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Phase0.GenesisValidatorsRoot != sequentialState.Phase0.GenesisValidatorsRoot {
+		t.Fatalf("validators root differs between concurrent and sequential loading")
+	}
+}
+
+func TestBuildStats(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+
+	vals := createTestValidators(t, 8)
+	lowBalance := uint64(16_000_000_000)
+	vals[0].Balance = &lowBalance
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(vals)
+
+	if builder.(StatsProvider).Stats() != nil {
+		t.Fatalf("expected no stats before building the state")
+	}
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	stats := builder.(StatsProvider).Stats()
+	if stats == nil {
+		t.Fatalf("expected stats after building the state")
+	}
+
+	var totalBalance, totalEffectiveBalance phase0.Gwei
+
+	activeCount := uint64(0)
+
+	for idx, val := range state.Electra.Validators {
+		totalBalance += state.Electra.Balances[idx]
+		totalEffectiveBalance += val.EffectiveBalance
+
+		if val.ActivationEpoch == 0 {
+			activeCount++
+		}
+	}
+
+	if stats.ValidatorCount != uint64(len(state.Electra.Validators)) {
+		t.Errorf("unexpected validator count: got %d, want %d", stats.ValidatorCount, len(state.Electra.Validators))
+	}
+
+	if stats.ActiveValidatorCount != activeCount {
+		t.Errorf("unexpected active validator count: got %d, want %d", stats.ActiveValidatorCount, activeCount)
+	}
+
+	if stats.TotalBalance != totalBalance {
+		t.Errorf("unexpected total balance: got %d, want %d", stats.TotalBalance, totalBalance)
+	}
+
+	if stats.TotalEffectiveBalance != totalEffectiveBalance {
+		t.Errorf("unexpected total effective balance: got %d, want %d", stats.TotalEffectiveBalance, totalEffectiveBalance)
+	}
+}
+
+func TestGenesisExtraData(t *testing.T) {
+	extraData := []byte("pote-devnet")
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"GENESIS_EXTRA_DATA": extraData,
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	execHeader := state.Deneb.LatestExecutionPayloadHeader
+	if !bytes.Equal(execHeader.ExtraData, extraData) {
+		t.Errorf("unexpected extra data: got %x, want %x", execHeader.ExtraData, extraData)
+	}
+
+	expectedGenesis := createTestELGenesis()
+	expectedGenesis.ExtraData = extraData
+
+	if execHeader.BlockHash != phase0.Hash32(expectedGenesis.ToBlock().Hash()) {
+		t.Errorf("block hash does not match the execution genesis with overridden extra data")
+	}
+
+	cfg = createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"GENESIS_EXTRA_DATA": make([]byte, 33),
+	})
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected error for too long extra data")
+	}
+}
+
+func TestCheckValidatorListLengths(t *testing.T) {
+	if err := checkValidatorListLengths(4,
+		validatorListLength{"balances", 4},
+		validatorListLength{"inactivity_scores", 4},
+	); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := checkValidatorListLengths(4,
+		validatorListLength{"balances", 3},
+		validatorListLength{"inactivity_scores", 4},
+	)
+	if err == nil {
+		t.Fatalf("expected error for mismatching list lengths")
+	}
+
+	if !strings.Contains(err.Error(), "validators: 4, balances: 3") {
+		t.Fatalf("expected error to contain the mismatching lengths, got %v", err)
+	}
+}
+
+func TestTEEAllowedVendors(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"TEE_VENDOR":          uint64(2),
+		"TEE_ALLOWED_VENDORS": "sev,tdx",
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected error for disallowed TEE vendor")
+	}
+}
+
+func TestLoadValidatorsSSZRoundTrip(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	sszData := []byte{}
+
+	for _, val := range state.Deneb.Validators {
+		sszData, err = val.MarshalSSZTo(sszData)
+		if err != nil {
+			t.Fatalf("failed to serialize validator: %v", err)
+		}
+	}
+
+	validatorsPath := filepath.Join(t.TempDir(), "validators.ssz")
+	if err := os.WriteFile(validatorsPath, sszData, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write validators file: %v", err)
+	}
+
+	loadedValidators, err := validators.LoadSSZ(validatorsPath, cfg)
+	if err != nil {
+		t.Fatalf("failed to load validators: %v", err)
+	}
+
+	rebuilder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	rebuilder.AddValidators(loadedValidators)
+
+	rebuiltState, err := rebuilder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to rebuild state: %v", err)
+	}
+
+	if rebuiltState.Deneb.GenesisValidatorsRoot != state.Deneb.GenesisValidatorsRoot {
+		t.Fatalf("validators root mismatch after reload: got %x, want %x", rebuiltState.Deneb.GenesisValidatorsRoot, state.Deneb.GenesisValidatorsRoot)
+	}
+
+	if err := os.WriteFile(validatorsPath, sszData[:len(sszData)-1], 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write validators file: %v", err)
+	}
+
+	if _, err := validators.LoadSSZ(validatorsPath, cfg); err == nil {
+		t.Fatalf("expected error for truncated validator list")
+	}
+}
+
+func TestMinGenesisTimeFallbackNow(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"GENESIS_DELAY":             uint64(60),
+		"MIN_GENESIS_TIME_FALLBACK": "now",
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	before := uint64(time.Now().Unix())
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	after := uint64(time.Now().Unix())
+
+	if state.Phase0.GenesisTime < before+60 || state.Phase0.GenesisTime > after+60 {
+		t.Fatalf("unexpected genesis time: got %d, want between %d and %d", state.Phase0.GenesisTime, before+60, after+60)
+	}
+}
+
+func TestGenesisProposerIndex(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"GENESIS_PROPOSER_INDEX": uint64(3),
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Phase0.LatestBlockHeader.ProposerIndex != 3 {
+		t.Fatalf("unexpected proposer index: got %d, want 3", state.Phase0.LatestBlockHeader.ProposerIndex)
+	}
+
+	cfg = createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"GENESIS_PROPOSER_INDEX": uint64(4),
+	})
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected error for out-of-range proposer index")
+	}
+}
+
+func TestGenesisETH1DataOverride(t *testing.T) {
+	depositRoot := bytes.Repeat([]byte{0x11}, 32)
+	blockHash := bytes.Repeat([]byte{0x22}, 32)
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"GENESIS_ETH1_DEPOSIT_ROOT":  depositRoot,
+		"GENESIS_ETH1_DEPOSIT_COUNT": uint64(42),
+		"GENESIS_ETH1_BLOCK_HASH":    blockHash,
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	eth1Data := state.Deneb.ETH1Data
+	if !bytes.Equal(eth1Data.DepositRoot[:], depositRoot) || eth1Data.DepositCount != 42 || !bytes.Equal(eth1Data.BlockHash, blockHash) {
+		t.Fatalf("eth1 data not overridden: %+v", eth1Data)
+	}
+
+	partialOverrides := []map[string]interface{}{
+		{"GENESIS_ETH1_DEPOSIT_ROOT": depositRoot, "GENESIS_ETH1_DEPOSIT_COUNT": uint64(42)},
+		{"GENESIS_ETH1_DEPOSIT_COUNT": uint64(42)},
+		{"GENESIS_ETH1_BLOCK_HASH": blockHash},
+	}
+
+	for _, values := range partialOverrides {
+		builder := NewGenesisBuilder(createTestELGenesis(), createTestConfig(t, "minimal", spec.DataVersionDeneb, values))
+		builder.AddValidators(createTestValidators(t, 4))
+
+		_, err := builder.BuildState()
+		if err == nil || !strings.Contains(err.Error(), "partial eth1 data override") {
+			t.Fatalf("expected partial override error for %v, got %v", values, err)
+		}
+	}
+}
+
+func TestValidateForForkDuplicateVersions(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+	if err := ValidateForFork(spec.DataVersionDeneb, cfg); err != nil {
+		t.Fatalf("unexpected error for distinct fork versions: %v", err)
+	}
+
+	cfg = createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"CAPELLA_FORK_VERSION": []byte{0x02, 0x00, 0x00, 0x00},
+	})
+
+	err := ValidateForFork(spec.DataVersionDeneb, cfg)
+	if err == nil || !strings.Contains(err.Error(), "BELLATRIX_FORK_VERSION and CAPELLA_FORK_VERSION are both 0x02000000") {
+		t.Fatalf("expected duplicate fork version error, got %v", err)
+	}
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected build to fail with duplicate fork versions")
+	}
+}
+
+func TestValidateForForkZeroSlotsPerEpoch(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionFulu, map[string]interface{}{
+		"SLOTS_PER_EPOCH": uint64(0),
+	})
+
+	if err := ValidateForFork(spec.DataVersionFulu, cfg); err == nil || !strings.Contains(err.Error(), "SLOTS_PER_EPOCH") {
+		t.Fatalf("expected SLOTS_PER_EPOCH error, got %v", err)
+	}
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected build to fail with SLOTS_PER_EPOCH 0")
+	}
+}
+
+func TestValidatorRegistryLimit(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"VALIDATOR_REGISTRY_LIMIT": uint64(4),
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("unexpected error at the registry limit: %v", err)
+	}
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 5))
+
+	_, err := builder.BuildState()
+	if err == nil || !strings.Contains(err.Error(), "genesis has 5 validators, VALIDATOR_REGISTRY_LIMIT is 4") {
+		t.Fatalf("expected registry limit error, got %v", err)
+	}
+}
+
+func TestValidatorsRootSaltErrors(t *testing.T) {
+	inactive := createTestValidators(t, 4)
+	lowBalance := uint64(1_000_000_000)
+
+	for _, val := range inactive {
+		val.Balance = &lowBalance
+	}
+
+	tests := []struct {
+		name       string
+		salt       string
+		validators []*validators.Validator
+		expected   string
+	}{
+		{
+			name:       "salt out of range",
+			salt:       "18446744073709551615",
+			validators: createTestValidators(t, 4),
+			expected:   "must be lower than FAR_FUTURE_EPOCH",
+		},
+		{
+			name:       "no active validator",
+			salt:       "5",
+			validators: inactive,
+			expected:   "no validator is active at genesis",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, version := range []spec.DataVersion{spec.DataVersionPhase0, spec.DataVersionElectra} {
+			cfg := createTestConfig(t, "minimal", version, map[string]interface{}{
+				"VALIDATORS_ROOT_SALT": tt.salt,
+			})
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(tt.validators)
+
+			_, err := builder.BuildState()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("%s (%s): expected %q error, got %v", tt.name, version.String(), tt.expected, err)
+			}
+		}
+	}
+}
+
+func TestGenesisBlockTimestampCheck(t *testing.T) {
+	elGenesis := createTestELGenesis()
+
+	tests := []struct {
+		name           string
+		minGenesisTime uint64
+		expectError    bool
+	}{
+		{name: "aligned", minGenesisTime: elGenesis.Timestamp},
+		{name: "misaligned", minGenesisTime: elGenesis.Timestamp + 12, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+				"MIN_GENESIS_TIME":           tt.minGenesisTime,
+				"CHECK_EL_GENESIS_TIMESTAMP": "true",
+			})
+
+			builder := NewGenesisBuilder(elGenesis, cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			_, err := builder.BuildState()
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := fmt.Sprintf("execution genesis timestamp %d does not match MIN_GENESIS_TIME %d", elGenesis.Timestamp, tt.minGenesisTime)
+			if tt.expectError && (err == nil || !strings.Contains(err.Error(), expected)) {
+				t.Fatalf("expected timestamp mismatch error, got %v", err)
+			}
+		})
+	}
+
+	// the check is off by default
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"MIN_GENESIS_TIME": elGenesis.Timestamp + 12,
+	})
+
+	if err := checkGenesisBlockTimestamp(cfg, elGenesis.ToBlock()); err != nil {
+		t.Fatalf("unexpected error with the check disabled: %v", err)
+	}
+}
+
+func TestValidateDepositAmountsFlag(t *testing.T) {
+	vals := createTestValidators(t, 4)
+	lowBalance := uint64(500_000_000)
+	vals[2].Balance = &lowBalance
+
+	for _, enabled := range []bool{false, true} {
+		values := map[string]interface{}{}
+		if enabled {
+			values["VALIDATE_DEPOSIT_AMOUNTS"] = "true"
+		}
+
+		cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(vals)
+
+		_, err := builder.BuildState()
+
+		switch {
+		case enabled && (err == nil || !strings.Contains(err.Error(), "deposit amount of validator 2")):
+			t.Fatalf("expected deposit amount error for validator 2, got %v", err)
+		case !enabled && err != nil:
+			t.Fatalf("unexpected error with the check disabled: %v", err)
+		}
+	}
+}
+
+func TestAllowedWithdrawalAddresses(t *testing.T) {
+	allowedA := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	allowedB := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	rejected := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	withdrawalCredentials := func(addr common.Address) []byte {
+		creds := make([]byte, 32)
+		creds[0] = 0x01
+		copy(creds[12:], addr.Bytes())
+
+		return creds
+	}
+
+	for name, allowed := range map[string]interface{}{
+		"comma separated": allowedA.Hex() + "," + allowedB.Hex(),
+		"yaml list":       []string{allowedA.Hex(), allowedB.Hex()},
+	} {
+		cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+			"ALLOWED_WITHDRAWAL_ADDRESSES": allowed,
+		})
+
+		vals := createTestValidators(t, 3)
+		vals[0].WithdrawalCredentials = withdrawalCredentials(allowedA)
+		vals[1].WithdrawalCredentials = withdrawalCredentials(allowedB)
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(vals)
+
+		if _, err := builder.BuildState(); err != nil {
+			t.Fatalf("%s: unexpected error for allowed addresses: %v", name, err)
+		}
+
+		vals[2].WithdrawalCredentials = withdrawalCredentials(rejected)
+
+		builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(vals)
+
+		if _, err := builder.BuildState(); err == nil || !strings.Contains(err.Error(), "disallowed withdrawal address") {
+			t.Fatalf("%s: expected disallowed withdrawal address error, got %v", name, err)
+		}
+	}
+}
+
+func TestFillerValidators(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"FILLER_VALIDATORS": uint64(100),
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if len(state.Deneb.Validators) != 104 {
+		t.Fatalf("expected 104 validators, got %d", len(state.Deneb.Validators))
+	}
+
+	if stats := builder.(StatsProvider).Stats(); stats.ActiveValidatorCount != 4 {
+		t.Fatalf("expected filler validators to be inactive, got %d active validators", stats.ActiveValidatorCount)
+	}
+
+	pubkeys := map[phase0.BLSPubKey]bool{}
+	for _, validator := range state.Deneb.Validators {
+		pubkeys[validator.PublicKey] = true
+	}
+
+	if len(pubkeys) != 104 {
+		t.Fatalf("expected unique pubkeys, got %d distinct", len(pubkeys))
+	}
+}
+
+func BenchmarkBuildStateFillerValidators(b *testing.B) {
+	for _, count := range []uint64{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			cfg := createTestConfig(b, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+				"FILLER_VALIDATORS": count,
+			})
+			vals := createTestValidators(b, 4)
+
+			for i := 0; i < b.N; i++ {
+				builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+				builder.AddValidators(vals)
+
+				if _, err := builder.BuildState(); err != nil {
+					b.Fatalf("failed to build state: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenesisTimeSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		values         map[string]interface{}
+		expectedSource GenesisTimeSource
+		expectedTime   uint64
+	}{
+		{
+			name: "explicit",
+			values: map[string]interface{}{
+				"GENESIS_TIME":     uint64(1_900_000_000),
+				"MIN_GENESIS_TIME": uint64(1_800_000_000),
+			},
+			expectedSource: GenesisTimeSourceExplicit,
+			expectedTime:   1_900_000_000,
+		},
+		{
+			name: "min genesis time",
+			values: map[string]interface{}{
+				"MIN_GENESIS_TIME": uint64(1_800_000_000),
+				"GENESIS_DELAY":    uint64(60),
+			},
+			expectedSource: GenesisTimeSourceMinGenesisTime,
+			expectedTime:   1_800_000_060,
+		},
+		{
+			name: "el block",
+			values: map[string]interface{}{
+				"GENESIS_DELAY": uint64(60),
+			},
+			expectedSource: GenesisTimeSourceELBlock,
+			expectedTime:   createTestELGenesis().Timestamp + 60,
+		},
+		{
+			name: "now",
+			values: map[string]interface{}{
+				"MIN_GENESIS_TIME_FALLBACK": "now",
+			},
+			expectedSource: GenesisTimeSourceNow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, tt.values)
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			if source := builder.(StatsProvider).Stats().GenesisTimeSource; source != tt.expectedSource {
+				t.Fatalf("unexpected genesis time source: got %s, want %s", source, tt.expectedSource)
+			}
+
+			if tt.expectedTime != 0 && state.Phase0.GenesisTime != tt.expectedTime {
+				t.Fatalf("unexpected genesis time: got %d, want %d", state.Phase0.GenesisTime, tt.expectedTime)
+			}
+		})
+	}
+}
+
+func TestTEEHeaderForks(t *testing.T) {
+	buildHeader := func(version spec.DataVersion, values map[string]interface{}) *phase0.BeaconBlockHeader {
+		builder := NewGenesisBuilder(createTestELGenesis(), createTestConfig(t, "minimal", version, values))
+		builder.AddValidators(createTestValidators(t, 4))
+
+		state, err := builder.BuildState()
+		if err != nil {
+			t.Fatalf("failed to build state: %v", err)
+		}
+
+		header, err := getLatestBlockHeader(state)
+		if err != nil {
+			t.Fatalf("failed to get latest block header: %v", err)
+		}
+
+		return header
+	}
+
+	emptyQuote := [8192]byte{}
+
+	if header := buildHeader(spec.DataVersionDeneb, map[string]interface{}{}); header.ProposerTEEQuote == emptyQuote {
+		t.Fatalf("expected TEE fields without TEE_HEADER_FORKS")
+	}
+
+	values := map[string]interface{}{"TEE_HEADER_FORKS": "electra, fulu"}
+
+	header := buildHeader(spec.DataVersionDeneb, values)
+	if header.ProposerTEEType != 0 || header.ProposerTEEQuote != emptyQuote {
+		t.Fatalf("expected TEE step to be skipped for deneb, got type %d", header.ProposerTEEType)
+	}
+
+	if header := buildHeader(spec.DataVersionElectra, values); header.ProposerTEEQuote == emptyQuote {
+		t.Fatalf("expected TEE fields for listed fork electra")
+	}
+
+	// the TEE checks of a fork that is not listed do not run, an unreachable quote URL does not fail its build
+	unreachableValues := map[string]interface{}{
+		"TEE_HEADER_FORKS": "electra",
+		"TEE_QUOTE_URL":    "http://127.0.0.1:1/quote",
+		"HTTP_TIMEOUT":     uint64(1),
+		"HTTP_RETRIES":     uint64(0),
+	}
+
+	if header := buildHeader(spec.DataVersionDeneb, unreachableValues); header.ProposerTEEQuote != emptyQuote {
+		t.Fatalf("expected TEE step to be skipped for deneb")
+	}
+
+	builder := NewGenesisBuilder(createTestELGenesis(), createTestConfig(t, "minimal", spec.DataVersionElectra, unreachableValues))
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil || !strings.Contains(err.Error(), "failed to fetch TEE quote") {
+		t.Fatalf("expected unreachable TEE_QUOTE_URL to fail the electra build, got %v", err)
+	}
+}
+
+func TestGenesisExecutionBlockHash(t *testing.T) {
+	blockHash := createTestELGenesis().ToBlock().Hash()
+
+	mismatchingHash := make([]byte, 32)
+	mismatchingHash[0] = 0x01
+
+	tests := []struct {
+		name        string
+		hash        []byte
+		expectError string
+	}{
+		{name: "matching", hash: blockHash[:]},
+		{name: "mismatching", hash: mismatchingHash, expectError: fmt.Sprintf("derived 0x%x, expected 0x01", blockHash)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+				"GENESIS_EXECUTION_BLOCK_HASH": tt.hash,
+			})
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			state, err := builder.BuildState()
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error with matching hash: %v", err)
+			}
+
+			if state.Deneb.LatestExecutionPayloadHeader.BlockHash != phase0.Hash32(blockHash) {
+				t.Fatalf("unexpected execution payload header block hash: %x", state.Deneb.LatestExecutionPayloadHeader.BlockHash)
+			}
+		})
+	}
+}
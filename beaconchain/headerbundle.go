@@ -0,0 +1,131 @@
+package beaconchain
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+// HeaderBundle is a reduced genesis for light clients: the genesis block header and the current sync committee
+// with its proof against the state root, similar to a LightClientBootstrap without the full state.
+type HeaderBundle struct {
+	Version spec.DataVersion `json:"version"`
+	// Header is the latest block header of the genesis state with the state root filled in.
+	Header    *phase0.BeaconBlockHeader `json:"header"`
+	StateRoot phase0.Root               `json:"state_root"`
+	// CurrentSyncCommitteeBranch proves CurrentSyncCommittee against StateRoot, ordered from the leaf up.
+	CurrentSyncCommittee       *altair.SyncCommittee `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch []phase0.Root         `json:"current_sync_committee_branch"`
+}
+
+// BuildHeaderBundle returns the light client header bundle of the genesis state, hashing the state with mainnet
+// preset sizes. Use BuildHeaderBundleWithDynSSZ for states of other presets.
+func BuildHeaderBundle(state *spec.VersionedBeaconState) (*HeaderBundle, error) {
+	return BuildHeaderBundleWithDynSSZ(dynssz.NewDynSsz(nil), state)
+}
+
+// BuildHeaderBundleWithDynSSZ returns the light client header bundle of the genesis state, using the given dynssz
+// instance to build the state tree. The state root and the sync committee branch are taken from the same tree,
+// the header and sync committee are the ones stored in the state. Sync committees exist from Altair on.
+func BuildHeaderBundleWithDynSSZ(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) (*HeaderBundle, error) {
+	latestHeader, err := getLatestBlockHeader(state)
+	if err != nil {
+		return nil, err
+	}
+
+	syncCommittee, err := getCurrentSyncCommittee(state)
+	if err != nil {
+		return nil, err
+	}
+
+	stateObj, err := versionedStateObject(state)
+	if err != nil {
+		return nil, err
+	}
+
+	gindex, err := stateFieldGeneralizedIndex(ds, stateObj, "CurrentSyncCommittee")
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := ds.GetTree(stateObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build state tree: %w", err)
+	}
+
+	proof, err := tree.Prove(gindex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove current sync committee: %w", err)
+	}
+
+	branch := make([]phase0.Root, len(proof.Hashes))
+	for i, hash := range proof.Hashes {
+		branch[i] = phase0.Root(hash)
+	}
+
+	header := *latestHeader
+	header.StateRoot = phase0.Root(tree.Hash())
+
+	return &HeaderBundle{
+		Version:                    state.Version,
+		Header:                     &header,
+		StateRoot:                  header.StateRoot,
+		CurrentSyncCommittee:       syncCommittee,
+		CurrentSyncCommitteeBranch: branch,
+	}, nil
+}
+
+// stateFieldGeneralizedIndex returns the generalized index of a top level field of the state container.
+func stateFieldGeneralizedIndex(ds *dynssz.DynSsz, stateObj any, name string) (int, error) {
+	stateDesc, err := ds.GetTypeCache().GetTypeDescriptor(reflect.TypeOf(stateObj), nil, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state type descriptor: %w", err)
+	}
+
+	if stateDesc.ContainerDesc == nil {
+		return 0, fmt.Errorf("state type %T is not a container", stateObj)
+	}
+
+	fieldCount := len(stateDesc.ContainerDesc.Fields)
+	depth := bits.Len(uint(fieldCount - 1))
+
+	for i, field := range stateDesc.ContainerDesc.Fields {
+		if field.Name == name {
+			return 1<<depth + i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("state type %T has no field %s", stateObj, name)
+}
+
+func getCurrentSyncCommittee(state *spec.VersionedBeaconState) (*altair.SyncCommittee, error) {
+	var syncCommittee *altair.SyncCommittee
+
+	switch state.Version {
+	case spec.DataVersionAltair:
+		syncCommittee = state.Altair.CurrentSyncCommittee
+	case spec.DataVersionBellatrix:
+		syncCommittee = state.Bellatrix.CurrentSyncCommittee
+	case spec.DataVersionCapella:
+		syncCommittee = state.Capella.CurrentSyncCommittee
+	case spec.DataVersionDeneb:
+		syncCommittee = state.Deneb.CurrentSyncCommittee
+	case spec.DataVersionElectra:
+		syncCommittee = state.Electra.CurrentSyncCommittee
+	case spec.DataVersionFulu:
+		syncCommittee = state.Fulu.CurrentSyncCommittee
+	default:
+		return nil, fmt.Errorf("%w: %s has no sync committee", ErrUnsupportedVersion, state.Version)
+	}
+
+	if syncCommittee == nil {
+		return nil, fmt.Errorf("state has no current sync committee")
+	}
+
+	return syncCommittee, nil
+}
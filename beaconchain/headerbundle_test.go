@@ -0,0 +1,80 @@
+package beaconchain
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+func TestBuildHeaderBundle(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+	ds := beaconutils.GetDynSSZ(cfg)
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	bundle, err := BuildHeaderBundleWithDynSSZ(ds, state)
+	if err != nil {
+		t.Fatalf("failed to build header bundle: %v", err)
+	}
+
+	if bundle.Header.BodyRoot != state.Electra.LatestBlockHeader.BodyRoot {
+		t.Fatalf("header body root %s does not match state body root %s", bundle.Header.BodyRoot.String(), state.Electra.LatestBlockHeader.BodyRoot.String())
+	}
+
+	stateRoot, err := ComputeStateRoot(ds, state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	if bundle.StateRoot != stateRoot || bundle.Header.StateRoot != stateRoot {
+		t.Fatalf("bundle state root %s does not match state root %s", bundle.StateRoot.String(), stateRoot.String())
+	}
+
+	// walk the branch from the sync committee root up to the state root, electra uses generalized index 86
+	node, err := ds.HashTreeRoot(bundle.CurrentSyncCommittee)
+	if err != nil {
+		t.Fatalf("failed to hash sync committee: %v", err)
+	}
+
+	gindex := 86
+	if len(bundle.CurrentSyncCommitteeBranch) != 6 {
+		t.Fatalf("expected a branch of depth 6, got %d", len(bundle.CurrentSyncCommitteeBranch))
+	}
+
+	for _, sibling := range bundle.CurrentSyncCommitteeBranch {
+		if gindex%2 == 0 {
+			node = sha256.Sum256(append(node[:], sibling[:]...))
+		} else {
+			node = sha256.Sum256(append(sibling[:], node[:]...))
+		}
+
+		gindex /= 2
+	}
+
+	if node != stateRoot {
+		t.Fatalf("sync committee branch does not prove against the state root")
+	}
+
+	phase0Cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{})
+	phase0Builder := NewGenesisBuilder(createTestELGenesis(), phase0Cfg)
+	phase0Builder.AddValidators(createTestValidators(t, 4))
+
+	phase0State, err := phase0Builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build phase0 state: %v", err)
+	}
+
+	if _, err := BuildHeaderBundleWithDynSSZ(beaconutils.GetDynSSZ(phase0Cfg), phase0State); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected unsupported version error for phase0, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package beaconchain
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// logBuiltState logs the version, genesis time and roots of a built state. Like all build and serialize messages it
+// is logged with fields, the log format is set once by the caller, see the --log-format flag of the state generator.
+func logBuiltState(version spec.DataVersion, genesisTime uint64, validatorsRoot, blockRoot phase0.Root) {
+	logrus.WithFields(logrus.Fields{
+		"version":         version.String(),
+		"genesis_time":    genesisTime,
+		"validators_root": validatorsRoot.String(),
+		"block_root":      blockRoot.String(),
+	}).Info("built genesis state")
+}
+
+// LogForkConfig logs the fork object that GetStateForkConfig embeds into a genesis state of the given version.
+func LogForkConfig(version spec.DataVersion, cfg *beaconconfig.Config) {
+	fork := GetStateForkConfig(version, cfg)
+
+	logrus.WithFields(logrus.Fields{
+		"version":          version.String(),
+		"previous_version": fmt.Sprintf("%#x", fork.PreviousVersion),
+		"current_version":  fmt.Sprintf("%#x", fork.CurrentVersion),
+		"epoch":            uint64(fork.Epoch),
+	}).Info("genesis fork")
+}
+
+func logBuildStats(stats *BuildStats) {
+	logrus.WithFields(logrus.Fields{
+		"validators":              stats.ValidatorCount,
+		"active_validators":       stats.ActiveValidatorCount,
+		"total_balance":           uint64(stats.TotalBalance),
+		"total_effective_balance": uint64(stats.TotalEffectiveBalance),
+		"genesis_time_source":     string(stats.GenesisTimeSource),
+	}).Info("genesis validator stats")
+}
+
+// logValidatorsFingerprint logs the fingerprint of the genesis validator set, to identify which set a genesis was built from.
+func logValidatorsFingerprint(fingerprint string) {
+	logrus.WithField("validators_fingerprint", fingerprint).Info("genesis validator set")
+}
+
+// logTEEApplied logs that the proposer TEE fields were set on the genesis block header. A header without the
+// fields is reported as warning by beaconutils.ApplyProposerTEEToHeader.
+func logTEEApplied(version spec.DataVersion, applied bool) {
+	if !applied {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"version":     version.String(),
+		"tee_applied": applied,
+	}).Info("applied proposer TEE fields to genesis block header")
+}
+
+// logSerializedState logs the size of a serialized state. At debug level the SSZ size of each
+// top level state field is logged too, to see where the encoding diverges from expectations.
+func logSerializedState(ds *dynssz.DynSsz, state *spec.VersionedBeaconState, contentType http.ContentType, size int) {
+	if contentType == http.ContentTypeSSZ && logrus.IsLevelEnabled(logrus.DebugLevel) {
+		logStateFieldSizes(ds, state)
+	}
+
+	fields := logrus.Fields{
+		"content_type": contentType.String(),
+	}
+
+	if contentType == http.ContentTypeSSZ {
+		fields["ssz_size"] = size
+	} else {
+		fields["size"] = size
+	}
+
+	logrus.WithFields(fields).Info("serialized genesis state")
+}
+
+func logStateFieldSizes(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) {
+	fieldSizes, err := DumpStateSSZ(ds, state)
+	if err != nil {
+		logrus.WithError(err).Debug("failed to compute state field sizes")
+		return
+	}
+
+	for _, field := range fieldSizes {
+		logrus.WithFields(logrus.Fields{
+			"field":   field.Name,
+			"size":    field.Size,
+			"dynamic": field.Dynamic,
+		}).Debug("genesis state field size")
+	}
+}
@@ -0,0 +1,107 @@
+package beaconchain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+)
+
+func TestJSONLogFormat(t *testing.T) {
+	var logBuffer bytes.Buffer
+
+	logrus.SetOutput(&logBuffer)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, nil)
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if _, err := builder.Serialize(state, http.ContentTypeSSZ); err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+
+	foundBuildFields := false
+	foundSizeField := false
+	scanner := bufio.NewScanner(&logBuffer)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			t.Fatalf("log line is not valid json: %s", line)
+		}
+
+		if entry["genesis_time"] != nil && entry["validators_root"] != nil {
+			foundBuildFields = true
+		}
+
+		if entry["ssz_size"] != nil {
+			foundSizeField = true
+		}
+	}
+
+	if !foundBuildFields {
+		t.Errorf("expected a log entry with genesis_time and validators_root fields")
+	}
+
+	if !foundSizeField {
+		t.Errorf("expected a log entry with ssz_size field")
+	}
+}
+
+func TestLogForkConfig(t *testing.T) {
+	var logBuffer bytes.Buffer
+
+	logrus.SetOutput(&logBuffer)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"FULU_FORK_EPOCH": uint64(10),
+	})
+
+	electraVersion := phase0.Version(cfg.GetBytesDefault("ELECTRA_FORK_VERSION", nil))
+
+	fork := GetStateForkConfig(spec.DataVersionElectra, cfg)
+	if fork.CurrentVersion != electraVersion || fork.PreviousVersion != electraVersion || fork.Epoch != 0 {
+		t.Fatalf("unexpected electra genesis fork: %v", fork)
+	}
+
+	LogForkConfig(spec.DataVersionElectra, cfg)
+
+	entry := map[string]interface{}{}
+	if err := json.Unmarshal(logBuffer.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not valid json: %s", logBuffer.String())
+	}
+
+	if entry["current_version"] != fmt.Sprintf("%#x", electraVersion) || entry["previous_version"] != fmt.Sprintf("%#x", electraVersion) {
+		t.Errorf("unexpected fork versions in log entry: %v", entry)
+	}
+}
@@ -0,0 +1,118 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+// GenesisMeta describes a generated genesis for traceability.
+// It is written as a sidecar file next to the genesis state and is not part of the state itself.
+type GenesisMeta struct {
+	NetworkName      string      `json:"network_name"`
+	GeneratorVersion string      `json:"generator_version"`
+	GeneratedAt      time.Time   `json:"generated_at"`
+	GenesisTime      uint64      `json:"genesis_time"`
+	ValidatorsRoot   phase0.Root `json:"genesis_validators_root"`
+	StateRoot        phase0.Root `json:"state_root"`
+}
+
+// WriteGenesisMeta writes the genesis metadata as indented JSON to path (e.g. genesis-meta.json).
+func WriteGenesisMeta(path string, meta GenesisMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal genesis meta: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil { //nolint:gosec // no strict permissions needed
+		return fmt.Errorf("failed to write genesis meta: %w", err)
+	}
+
+	return nil
+}
+
+// NewGenesisMeta collects the genesis metadata of the state.
+// GeneratedAt is set to the current time, the state root is computed with the given dynssz instance.
+func NewGenesisMeta(ds *dynssz.DynSsz, state *spec.VersionedBeaconState, networkName, generatorVersion string) (GenesisMeta, error) {
+	meta := GenesisMeta{
+		NetworkName:      networkName,
+		GeneratorVersion: generatorVersion,
+		GeneratedAt:      time.Now().UTC(),
+	}
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Phase0.GenesisTime, state.Phase0.GenesisValidatorsRoot
+	case spec.DataVersionAltair:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Altair.GenesisTime, state.Altair.GenesisValidatorsRoot
+	case spec.DataVersionBellatrix:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Bellatrix.GenesisTime, state.Bellatrix.GenesisValidatorsRoot
+	case spec.DataVersionCapella:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Capella.GenesisTime, state.Capella.GenesisValidatorsRoot
+	case spec.DataVersionDeneb:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Deneb.GenesisTime, state.Deneb.GenesisValidatorsRoot
+	case spec.DataVersionElectra:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Electra.GenesisTime, state.Electra.GenesisValidatorsRoot
+	case spec.DataVersionFulu:
+		meta.GenesisTime, meta.ValidatorsRoot = state.Fulu.GenesisTime, state.Fulu.GenesisValidatorsRoot
+	default:
+		return meta, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+
+	stateRoot, err := ComputeStateRoot(ds, state)
+	if err != nil {
+		return meta, err
+	}
+
+	meta.StateRoot = stateRoot
+
+	return meta, nil
+}
+
+// ComputeStateRoot returns the hash tree root of the state using the given dynssz instance,
+// so it works for non-mainnet presets too.
+func ComputeStateRoot(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) (phase0.Root, error) {
+	return computeStateRoot(ds, state)
+}
+
+// computeStateRoot works like ComputeStateRoot, with the root computed by rooter.
+func computeStateRoot(rooter HashTreeRooter, state *spec.VersionedBeaconState) (phase0.Root, error) {
+	stateObj, err := versionedStateObject(state)
+	if err != nil {
+		return phase0.Root{}, err
+	}
+
+	root, err := rooter.HashTreeRoot(stateObj)
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to compute state root: %w", err)
+	}
+
+	return root, nil
+}
+
+// versionedStateObject returns the fork specific state object of a versioned state.
+func versionedStateObject(state *spec.VersionedBeaconState) (any, error) {
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		return state.Phase0, nil
+	case spec.DataVersionAltair:
+		return state.Altair, nil
+	case spec.DataVersionBellatrix:
+		return state.Bellatrix, nil
+	case spec.DataVersionCapella:
+		return state.Capella, nil
+	case spec.DataVersionDeneb:
+		return state.Deneb, nil
+	case spec.DataVersionElectra:
+		return state.Electra, nil
+	case spec.DataVersionFulu:
+		return state.Fulu, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+}
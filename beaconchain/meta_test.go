@@ -0,0 +1,66 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestWriteGenesisMeta(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	meta, err := NewGenesisMeta(builder.(DynSSZProvider).DynSSZ(), state, "testnet", "v1.2.3")
+	if err != nil {
+		t.Fatalf("failed to collect genesis meta: %v", err)
+	}
+
+	meta.GeneratedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	stateRoot, err := builder.(DynSSZProvider).DynSSZ().HashTreeRoot(state.Deneb)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	metaPath := filepath.Join(t.TempDir(), "genesis-meta.json")
+	if err := WriteGenesisMeta(metaPath, meta); err != nil {
+		t.Fatalf("failed to write genesis meta: %v", err)
+	}
+
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatalf("failed to read genesis meta: %v", err)
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("failed to parse genesis meta: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"network_name":            "testnet",
+		"generator_version":       "v1.2.3",
+		"generated_at":            "2024-01-02T03:04:05Z",
+		"genesis_time":            float64(state.Deneb.GenesisTime),
+		"genesis_validators_root": fmt.Sprintf("%#x", state.Deneb.GenesisValidatorsRoot),
+		"state_root":              fmt.Sprintf("%#x", stateRoot),
+	}
+
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("unexpected %s: got %v, want %v", key, fields[key], value)
+		}
+	}
+}
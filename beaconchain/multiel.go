@@ -0,0 +1,95 @@
+package beaconchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/core"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// sharedGenesisValidators computes the genesis validator records and validators root once and hands out copies
+// to every builder sharing it. The validators root does not depend on the EL genesis. The warnings of the
+// conversion are passed to every builder, so each of them records them.
+type sharedGenesisValidators struct {
+	once           sync.Once
+	validators     []*phase0.Validator
+	validatorsRoot phase0.Root
+	warnings       []sharedWarning
+	err            error
+}
+
+// sharedWarning is a warning of the shared validator conversion.
+type sharedWarning struct {
+	code    string
+	message string
+}
+
+func (s *sharedGenesisValidators) get(cfg *beaconconfig.Config, vals []*validators.Validator, progress beaconutils.ProgressFn, warn beaconutils.WarnFn) ([]*phase0.Validator, phase0.Root, error) {
+	s.once.Do(func() {
+		s.validators, s.validatorsRoot, s.err = beaconutils.GetGenesisValidatorsWithOptions(cfg, vals, beaconutils.GenesisValidatorsOptions{
+			Progress: progress,
+			Warn: func(code, message string) {
+				s.warnings = append(s.warnings, sharedWarning{code: code, message: message})
+			},
+		})
+	})
+
+	for _, warning := range s.warnings {
+		warn(warning.code, warning.message)
+	}
+
+	if s.err != nil {
+		return nil, phase0.Root{}, s.err
+	}
+
+	// each state gets its own records, so modifying one state does not affect the others
+	clValidators := make([]*phase0.Validator, len(s.validators))
+
+	for i, validator := range s.validators {
+		record := *validator
+		clValidators[i] = &record
+	}
+
+	return clValidators, s.validatorsRoot, nil
+}
+
+// BuildStatesForELGeneses builds one genesis state per EL genesis variant for the same consensus config and
+// validator set, e.g. to test several chain IDs or allocs against one validator set. The genesis validators are
+// converted once and shared by all variants. The states are keyed by the EL chain ID, which must be unique.
+func BuildStatesForELGeneses(elGeneses []*core.Genesis, clConfig *beaconconfig.Config, vals []*validators.Validator) (map[uint64]*spec.VersionedBeaconState, error) {
+	shared := &sharedGenesisValidators{}
+	states := make(map[uint64]*spec.VersionedBeaconState, len(elGeneses))
+
+	for i, elGenesis := range elGeneses {
+		if elGenesis == nil || elGenesis.Config == nil || elGenesis.Config.ChainID == nil {
+			return nil, fmt.Errorf("EL genesis %d has no chain ID", i)
+		}
+
+		chainID := elGenesis.Config.ChainID.Uint64()
+		if _, found := states[chainID]; found {
+			return nil, fmt.Errorf("duplicate EL genesis chain ID %d", chainID)
+		}
+
+		builder := newGenesisBuilder(elGenesis, clConfig, shared)
+		if builder == nil {
+			return nil, fmt.Errorf("%w: no builder for genesis fork", ErrUnsupportedVersion)
+		}
+
+		builder.AddValidators(vals)
+
+		state, err := builder.BuildState()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build genesis state for chain ID %d: %w", chainID, err)
+		}
+
+		states[chainID] = state
+	}
+
+	return states, nil
+}
@@ -0,0 +1,59 @@
+package beaconchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBuildStatesForELGeneses(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+
+	elGenesisA := createTestELGenesis()
+
+	chainConfigB := *elGenesisA.Config
+	chainConfigB.ChainID = big.NewInt(0x5eed)
+
+	elGenesisB := createTestELGenesis()
+	elGenesisB.Config = &chainConfigB
+	elGenesisB.Alloc = types.GenesisAlloc{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"): {Balance: big.NewInt(1_000_000_000)},
+	}
+
+	states, err := BuildStatesForELGeneses([]*core.Genesis{elGenesisA, elGenesisB}, cfg, createTestValidators(t, 8))
+	if err != nil {
+		t.Fatalf("failed to build states: %v", err)
+	}
+
+	if len(states) != 2 {
+		t.Fatalf("expected 2 states, got %d", len(states))
+	}
+
+	stateA := states[elGenesisA.Config.ChainID.Uint64()]
+	stateB := states[0x5eed]
+
+	if stateA == nil || stateB == nil {
+		t.Fatalf("expected states keyed by chain ID, got %v", states)
+	}
+
+	if stateA.Electra.GenesisValidatorsRoot != stateB.Electra.GenesisValidatorsRoot {
+		t.Fatalf("validators root differs between EL variants")
+	}
+
+	if stateA.Electra.Validators[0] == stateB.Electra.Validators[0] {
+		t.Fatalf("expected each state to carry its own validator records")
+	}
+
+	headerA, headerB := stateA.Electra.LatestExecutionPayloadHeader, stateB.Electra.LatestExecutionPayloadHeader
+	if headerA.BlockHash == headerB.BlockHash || headerA.StateRoot == headerB.StateRoot {
+		t.Fatalf("expected distinct execution headers for distinct EL variants")
+	}
+
+	if _, err := BuildStatesForELGeneses([]*core.Genesis{elGenesisA, createTestELGenesis()}, cfg, createTestValidators(t, 8)); err == nil {
+		t.Fatalf("expected error for duplicate chain IDs")
+	}
+}
@@ -0,0 +1,211 @@
+package beaconchain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+func TestPhase0BuilderDynSSZ(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	dynSsz := builder.(DynSSZProvider).DynSSZ()
+	if dynSsz == nil {
+		t.Fatalf("expected dynssz instance")
+	}
+
+	bodyRoot, err := dynSsz.HashTreeRoot(&phase0.BeaconBlockBody{
+		ETH1Data: &phase0.ETH1Data{
+			BlockHash: make([]byte, 32),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to hash block body: %v", err)
+	}
+
+	if phase0.Root(bodyRoot) != state.Phase0.LatestBlockHeader.BodyRoot {
+		t.Fatalf("body root mismatch: got %x, want %x", bodyRoot, state.Phase0.LatestBlockHeader.BodyRoot)
+	}
+
+	headerRoot, err := dynSsz.HashTreeRoot(state.Phase0.LatestBlockHeader)
+	if err != nil {
+		t.Fatalf("failed to hash header: %v", err)
+	}
+
+	expectedHeaderRoot, err := beaconutils.GetDynSSZ(cfg).HashTreeRoot(state.Phase0.LatestBlockHeader)
+	if err != nil {
+		t.Fatalf("failed to hash header: %v", err)
+	}
+
+	if headerRoot != expectedHeaderRoot {
+		t.Fatalf("header root mismatch: got %x, want %x", headerRoot, expectedHeaderRoot)
+	}
+}
+
+func TestPhase0BuilderWithoutExecutionGenesis(t *testing.T) {
+	eth1BlockHash := bytes.Repeat([]byte{0x12}, 32)
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"MIN_GENESIS_TIME":        uint64(1_800_000_000),
+		"GENESIS_DELAY":           uint64(300),
+		"GENESIS_ETH1_BLOCK_HASH": eth1BlockHash,
+	})
+
+	builder := NewPhase0Builder(nil, cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if state.Phase0.GenesisTime != 1_800_000_300 {
+		t.Errorf("unexpected genesis time: got %d, want %d", state.Phase0.GenesisTime, 1_800_000_300)
+	}
+
+	if !bytes.Equal(state.Phase0.ETH1Data.BlockHash, eth1BlockHash) {
+		t.Errorf("unexpected eth1 block hash: got %x, want %x", state.Phase0.ETH1Data.BlockHash, eth1BlockHash)
+	}
+
+	if len(state.Phase0.Validators) != 8 {
+		t.Errorf("expected 8 validators, got %d", len(state.Phase0.Validators))
+	}
+}
+
+type countingRooter struct {
+	calls   int
+	sources []interface{}
+	root    [32]byte
+}
+
+func (r *countingRooter) HashTreeRoot(source interface{}) ([32]byte, error) {
+	r.calls++
+	r.sources = append(r.sources, source)
+
+	return r.root, nil
+}
+
+func TestPhase0BuilderHashTreeRooter(t *testing.T) {
+	rootValue := [32]byte{0x01, 0x02, 0x03}
+
+	for _, wrapper := range []string{"", "STRICT", "REFERENCE_STATE_ROOT"} {
+		values := map[string]interface{}{}
+
+		expectedCalls := 4
+
+		switch wrapper {
+		case "STRICT":
+			values["STRICT"] = "true"
+		case "REFERENCE_STATE_ROOT":
+			// the reference check hashes the state once more, with the same rooter
+			values["REFERENCE_STATE_ROOT"] = rootValue[:]
+			expectedCalls = 5
+		}
+
+		cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, values)
+
+		rooter := &countingRooter{root: rootValue}
+
+		// the wrapped builder forwards the rooter to the fork builder
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.(HashTreeRooterSetter).SetHashTreeRooter(rooter)
+		builder.AddValidators(createTestValidators(t, 8))
+
+		state, err := builder.BuildState()
+		if err != nil {
+			t.Fatalf("failed to build state: %v", err)
+		}
+
+		if rooter.calls != expectedCalls {
+			t.Fatalf("%s: expected %d hash tree root calls, got %d", wrapper, expectedCalls, rooter.calls)
+		}
+
+		body, ok := rooter.sources[0].(*phase0.BeaconBlockBody)
+		if !ok {
+			t.Fatalf("expected block body to be hashed, got %T", rooter.sources[0])
+		}
+
+		if !bytes.Equal(body.ETH1Data.BlockHash, make([]byte, 32)) {
+			t.Fatalf("unexpected eth1 block hash in hashed body: %x", body.ETH1Data.BlockHash)
+		}
+
+		if registry, ok := rooter.sources[1].(*validatorRegistry); !ok || len(registry.Validators) != 8 {
+			t.Fatalf("expected validator registry to be hashed, got %T", rooter.sources[1])
+		}
+
+		if _, ok := rooter.sources[2].(*phase0.BeaconState); !ok {
+			t.Fatalf("expected state to be hashed, got %T", rooter.sources[2])
+		}
+
+		if _, ok := rooter.sources[3].(*genesisBlockContainer); !ok {
+			t.Fatalf("expected genesis block to be hashed, got %T", rooter.sources[3])
+		}
+
+		if state.Phase0.LatestBlockHeader.BodyRoot != phase0.Root(rooter.root) {
+			t.Fatalf("body root not taken from rooter: got %x", state.Phase0.LatestBlockHeader.BodyRoot)
+		}
+
+		if state.Phase0.GenesisValidatorsRoot != phase0.Root(rooter.root) {
+			t.Fatalf("validators root not taken from rooter: got %x", state.Phase0.GenesisValidatorsRoot)
+		}
+	}
+}
+
+func TestPhase0BuilderCachedValidatorsRoot(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := createTestConfig(t, "minimal", spec.DataVersionPhase0, map[string]interface{}{
+		"VALIDATORS_CACHE_DIR": cacheDir,
+	})
+	vals := createTestValidators(t, 8)
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(vals)
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.ssz"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one validators cache entry, got %v (%v)", entries, err)
+	}
+
+	// the cache entry starts with the validators root, the default rooter takes it as is
+	data, err := os.ReadFile(entries[0])
+	if err != nil {
+		t.Fatalf("failed to read cache entry: %v", err)
+	}
+
+	marker := phase0.Root{0xca, 0xfe}
+	copy(data, marker[:])
+
+	if err := os.WriteFile(entries[0], data, 0o600); err != nil {
+		t.Fatalf("failed to write cache entry: %v", err)
+	}
+
+	builder = NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(vals)
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state from cache: %v", err)
+	}
+
+	if state.Phase0.GenesisValidatorsRoot != marker {
+		t.Fatalf("expected the cached validators root, got %x", state.Phase0.GenesisValidatorsRoot)
+	}
+}
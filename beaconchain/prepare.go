@@ -0,0 +1,205 @@
+package beaconchain
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// genesisInputs holds the fork independent inputs of a genesis state, prepared and checked by prepareGenesis.
+type genesisInputs struct {
+	// validators is the genesis validator set in state order, including filler validators. It is nil for staged
+	// builds, which convert the validators batch by batch and do not keep them.
+	validators []*validators.Validator
+	balances   []phase0.Gwei
+	// teeValidators are the validators the proposer TEE vendor is resolved from.
+	teeValidators []*validators.Validator
+	block         *types.Block
+	blockHash     common.Hash
+	// executionHeader is the execution payload header of the fork, nil before Bellatrix.
+	executionHeader any
+	eth1Data        *phase0.ETH1Data
+	// teeType and teeQuote are the proposer TEE fields of the genesis block header, resolved once per build.
+	// They are only set for forks in TEE_HEADER_FORKS.
+	teeType  beaconutils.TEEType
+	teeQuote []byte
+}
+
+// prepareGenesis collects the genesis validators and the execution genesis block for the given fork and runs
+// the config, block and validator checks that apply to it. All fork builders start their build with it.
+func (b *builderBase) prepareGenesis(version spec.DataVersion) (*genesisInputs, error) {
+	if err := ValidateForFork(version, b.clConfig); err != nil {
+		return nil, err
+	}
+
+	genesis := &genesisInputs{}
+	validatorCount := 0
+
+	if b.validatorStream != nil {
+		// the streamed validators were checked batch by batch and include the filler validators, see StagedGenesisBuilder
+		fillerValidatorCount(b.clConfig, b.diagnostics)
+
+		genesis.balances = b.validatorStream.Balances()
+		genesis.teeValidators = b.validatorStream.VendorValidators()
+		validatorCount = int(b.validatorStream.Count()) //nolint:gosec // bounded by the registry limit
+	} else {
+		genesis.validators = orderGenesisValidators(b.clConfig, appendFillerValidators(b.clConfig, b.diagnostics, b.getValidators()))
+		genesis.balances = beaconutils.GetGenesisBalances(b.clConfig, genesis.validators)
+		genesis.teeValidators = genesis.validators
+		validatorCount = len(genesis.validators)
+	}
+
+	if err := checkValidatorRegistryLimit(b.clConfig, validatorCount); err != nil {
+		return nil, err
+	}
+
+	b.stateValidators = genesis.validators
+
+	isShadowFork := b.shadowForkBlock != nil
+
+	if version >= spec.DataVersionBellatrix {
+		block, err := getGenesisBlock(b.clConfig, b.diagnostics, b.elGenesis, b.shadowForkBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		genesis.block, genesis.blockHash = block, block.Hash()
+	} else {
+		block, blockHash, err := getEth1Block(b.clConfig, b.diagnostics, b.elGenesis, b.shadowForkBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		genesis.block, genesis.blockHash = block, blockHash
+	}
+
+	extra := genesis.block.Extra()
+	if len(extra) > 32 {
+		return nil, fmt.Errorf("extra data is %d bytes, max is %d", len(extra), 32)
+	}
+
+	if err := b.checkGenesisBlock(version, genesis.block, isShadowFork); err != nil {
+		return nil, err
+	}
+
+	// forks without TEE header fields skip the TEE step entirely, including loading the quote
+	if isTEEHeaderFork(b.clConfig, version) {
+		teeType, teeQuote, err := beaconutils.GetGenesisProposerTEEFields(b.clConfig, genesis.teeValidators, b.diagnostics.helperWarn())
+		if err != nil {
+			if beaconutils.RequireProposerTEEFields(b.clConfig) {
+				return nil, fmt.Errorf("failed to resolve proposer TEE fields: %w", err)
+			}
+
+			b.diagnostics.warn(DiagnosticTEE, "failed to resolve proposer TEE fields, using defaults: %v", err)
+		}
+
+		if err := beaconutils.ValidateTEEVendorExtraData(b.clConfig, teeType, extra); err != nil {
+			return nil, fmt.Errorf("failed to validate TEE vendor: %w", err)
+		}
+
+		genesis.teeType, genesis.teeQuote = teeType, teeQuote
+	}
+
+	if err := validateGenesisWithdrawalAddresses(b.clConfig, genesis.validators); err != nil {
+		return nil, fmt.Errorf("failed to validate withdrawal addresses: %w", err)
+	}
+
+	if err := validateGenesisDepositAmounts(b.clConfig, genesis.validators); err != nil {
+		return nil, fmt.Errorf("failed to validate deposit amounts: %w", err)
+	}
+
+	if err := checkDepositContractDeployed(b.clConfig, b.diagnostics, b.elGenesis, validatorCount, isShadowFork); err != nil {
+		return nil, err
+	}
+
+	if version >= spec.DataVersionBellatrix {
+		header, err := buildExecutionPayloadHeader(version, b.clConfig, genesis.block, genesis.blockHash, extra)
+		if err != nil {
+			return nil, err
+		}
+
+		genesis.executionHeader = header
+	}
+
+	depositRoot, err := beaconutils.ComputeDepositRoot(b.clConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute deposit root: %w", err)
+	}
+
+	genesis.eth1Data, err = getGenesisETH1Data(b.clConfig, depositRoot, genesis.blockHash[:], b.elGenesis == nil && !isShadowFork)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkGenesisExecutionBlockHash(b.clConfig, genesis.blockHash[:], genesis.eth1Data.BlockHash); err != nil {
+		return nil, err
+	}
+
+	return genesis, nil
+}
+
+// checkGenesisBlock runs the sanity checks on the execution genesis block that apply to the given fork.
+func (b *builderBase) checkGenesisBlock(version spec.DataVersion, block *types.Block, isShadowFork bool) error {
+	if err := checkGenesisBlockNumber(b.clConfig, b.diagnostics, block, isShadowFork); err != nil {
+		return err
+	}
+
+	if version < spec.DataVersionBellatrix {
+		return nil
+	}
+
+	if err := checkGasLimit(b.clConfig, b.diagnostics, block.GasLimit()); err != nil {
+		return err
+	}
+
+	if err := checkGenesisStateRoot(b.clConfig, b.diagnostics, block); err != nil {
+		return err
+	}
+
+	if err := checkGenesisTransactions(b.clConfig, b.diagnostics, block, isShadowFork); err != nil {
+		return err
+	}
+
+	if version >= spec.DataVersionElectra {
+		if err := checkGenesisBlobSchedule(b.clConfig, b.diagnostics, block); err != nil {
+			return err
+		}
+	}
+
+	if err := checkGenesisBlockTimestamp(b.clConfig, block); err != nil {
+		return err
+	}
+
+	if version >= spec.DataVersionCapella {
+		if err := checkGenesisWithdrawals(b.clConfig, b.diagnostics, block, isShadowFork); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyGenesisTEE sets the proposer TEE fields on the genesis block header if TEE_HEADER_FORKS includes the fork.
+// It uses the proposer TEE fields resolved by prepareGenesis.
+func (b *builderBase) applyGenesisTEE(version spec.DataVersion, header *phase0.BeaconBlockHeader, genesis *genesisInputs, validatorsRoot phase0.Root) error {
+	if !isTEEHeaderFork(b.clConfig, version) {
+		return nil
+	}
+
+	beaconutils.LogTEEResolution(b.clConfig, genesis.teeValidators)
+
+	teeApplied := beaconutils.ApplyProposerTEEToHeader(header, b.clConfig, genesis.teeType, genesis.teeQuote, validatorsRoot, b.diagnostics.helperWarn())
+	logTEEApplied(version, teeApplied)
+
+	if err := beaconutils.ValidateHeaderTEEQuote(b.clConfig, header); err != nil {
+		return fmt.Errorf("failed to validate TEE quote: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/fulu"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+// RebuildOverrides holds the non-validator fields to change on an existing genesis state.
+// Nil fields are left untouched.
+type RebuildOverrides struct {
+	GenesisTime *uint64
+	// ProposerTEEType and ProposerTEEQuote override the proposer TEE fields of the latest block header
+	// independently, the quote is kept if only the type is set.
+	ProposerTEEType  *beaconutils.TEEType
+	ProposerTEEQuote []byte
+}
+
+// LoadState loads a serialized genesis state of the given version from path.
+// Files with a .json extension are decoded as JSON, everything else as SSZ with mainnet preset sizes.
+// Use LoadStateWithDynSSZ to load SSZ states of other presets.
+func LoadState(path string, version spec.DataVersion) (*spec.VersionedBeaconState, error) {
+	return LoadStateWithDynSSZ(path, version, dynssz.NewDynSsz(nil))
+}
+
+// LoadStateWithDynSSZ loads a serialized genesis state like LoadState, but decodes SSZ with the given dynssz instance
+// (e.g. the instance returned by the builder's DynSSZ method).
+func LoadStateWithDynSSZ(path string, version spec.DataVersion, ds *dynssz.DynSsz) (*spec.VersionedBeaconState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state, stateObj, err := newVersionedState(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, stateObj)
+	} else {
+		err = ds.UnmarshalSSZ(stateObj, data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode state: %w", err)
+	}
+
+	return state, nil
+}
+
+// Rebuild applies the overrides to an existing genesis state in place and returns it.
+// Validators and balances are not touched, so the genesis validators root is preserved.
+func Rebuild(state *spec.VersionedBeaconState, overrides RebuildOverrides) (*spec.VersionedBeaconState, error) {
+	var (
+		genesisTime *uint64
+		header      *phase0.BeaconBlockHeader
+	)
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		genesisTime, header = &state.Phase0.GenesisTime, state.Phase0.LatestBlockHeader
+	case spec.DataVersionAltair:
+		genesisTime, header = &state.Altair.GenesisTime, state.Altair.LatestBlockHeader
+	case spec.DataVersionBellatrix:
+		genesisTime, header = &state.Bellatrix.GenesisTime, state.Bellatrix.LatestBlockHeader
+	case spec.DataVersionCapella:
+		genesisTime, header = &state.Capella.GenesisTime, state.Capella.LatestBlockHeader
+	case spec.DataVersionDeneb:
+		genesisTime, header = &state.Deneb.GenesisTime, state.Deneb.LatestBlockHeader
+	case spec.DataVersionElectra:
+		genesisTime, header = &state.Electra.GenesisTime, state.Electra.LatestBlockHeader
+	case spec.DataVersionFulu:
+		genesisTime, header = &state.Fulu.GenesisTime, state.Fulu.LatestBlockHeader
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+
+	if overrides.GenesisTime != nil {
+		*genesisTime = *overrides.GenesisTime
+	}
+
+	if overrides.ProposerTEEType != nil || overrides.ProposerTEEQuote != nil {
+		if header == nil {
+			return nil, fmt.Errorf("state has no latest block header")
+		}
+
+		if err := beaconutils.OverrideHeaderTEEFields(header, overrides.ProposerTEEType, overrides.ProposerTEEQuote); err != nil {
+			return nil, fmt.Errorf("invalid proposer TEE override: %w", err)
+		}
+	}
+
+	return state, nil
+}
+
+// newVersionedState returns an empty versioned state and a pointer to its fork specific state object.
+func newVersionedState(version spec.DataVersion) (*spec.VersionedBeaconState, any, error) {
+	state := &spec.VersionedBeaconState{
+		Version: version,
+	}
+
+	switch version {
+	case spec.DataVersionPhase0:
+		state.Phase0 = &phase0.BeaconState{}
+		return state, state.Phase0, nil
+	case spec.DataVersionAltair:
+		state.Altair = &altair.BeaconState{}
+		return state, state.Altair, nil
+	case spec.DataVersionBellatrix:
+		state.Bellatrix = &bellatrix.BeaconState{}
+		return state, state.Bellatrix, nil
+	case spec.DataVersionCapella:
+		state.Capella = &capella.BeaconState{}
+		return state, state.Capella, nil
+	case spec.DataVersionDeneb:
+		state.Deneb = &deneb.BeaconState{}
+		return state, state.Deneb, nil
+	case spec.DataVersionElectra:
+		state.Electra = &electra.BeaconState{}
+		return state, state.Electra, nil
+	case spec.DataVersionFulu:
+		state.Fulu = &fulu.BeaconState{}
+		return state, state.Fulu, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, version)
+	}
+}
@@ -0,0 +1,130 @@
+package beaconchain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+func TestRebuild(t *testing.T) {
+	// a derived quote differs from the hardcoded one, so a type override must not replace it
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"TEE_QUOTE_MODE": "derived",
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	sszData, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+
+	statePath := filepath.Join(t.TempDir(), "genesis.ssz")
+	if err := os.WriteFile(statePath, sszData, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	loadedState, err := LoadStateWithDynSSZ(statePath, spec.DataVersionDeneb, builder.(DynSSZProvider).DynSSZ())
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+
+	genesisTime := state.Deneb.GenesisTime + 100
+	teeType := beaconutils.TEETypeTDX
+
+	rebuiltState, err := Rebuild(loadedState, RebuildOverrides{
+		GenesisTime:     &genesisTime,
+		ProposerTEEType: &teeType,
+	})
+	if err != nil {
+		t.Fatalf("failed to rebuild state: %v", err)
+	}
+
+	if rebuiltState.Deneb.GenesisTime != genesisTime {
+		t.Errorf("unexpected genesis time: got %d, want %d", rebuiltState.Deneb.GenesisTime, genesisTime)
+	}
+
+	if rebuiltState.Deneb.GenesisValidatorsRoot != state.Deneb.GenesisValidatorsRoot {
+		t.Errorf("validators root changed on rebuild")
+	}
+
+	if rebuiltState.Deneb.LatestBlockHeader.ProposerTEEType != uint8(teeType) {
+		t.Errorf("unexpected proposer TEE type: got %d, want %d", rebuiltState.Deneb.LatestBlockHeader.ProposerTEEType, teeType)
+	}
+
+	if rebuiltState.Deneb.LatestBlockHeader.ProposerTEEQuote != state.Deneb.LatestBlockHeader.ProposerTEEQuote {
+		t.Errorf("proposer TEE quote changed on a type override")
+	}
+
+	rebuiltData, err := builder.Serialize(rebuiltState, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize rebuilt state: %v", err)
+	}
+
+	if len(rebuiltData) != len(sszData) {
+		t.Errorf("unexpected rebuilt state size: got %d, want %d", len(rebuiltData), len(sszData))
+	}
+
+	jsonData, err := builder.Serialize(rebuiltState, http.ContentTypeJSON)
+	if err != nil {
+		t.Fatalf("failed to serialize rebuilt state to json: %v", err)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "genesis.json")
+	if err := os.WriteFile(jsonPath, jsonData, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write state file: %v", err)
+	}
+
+	jsonState, err := LoadState(jsonPath, spec.DataVersionDeneb)
+	if err != nil {
+		t.Fatalf("failed to load json state: %v", err)
+	}
+
+	if jsonState.Deneb.GenesisTime != genesisTime {
+		t.Errorf("unexpected genesis time in json state: got %d, want %d", jsonState.Deneb.GenesisTime, genesisTime)
+	}
+}
+
+func TestRebuildProposerTEEQuote(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	teeType := beaconutils.TEETypeTDX
+	teeQuote := bytes.Repeat([]byte{0xab}, 700)
+
+	if _, err := Rebuild(state, RebuildOverrides{ProposerTEEType: &teeType, ProposerTEEQuote: teeQuote[:100]}); err == nil {
+		t.Fatalf("expected an error for a quote below the TDX quote length")
+	}
+
+	if _, err := Rebuild(state, RebuildOverrides{ProposerTEEType: &teeType, ProposerTEEQuote: teeQuote}); err != nil {
+		t.Fatalf("failed to rebuild state: %v", err)
+	}
+
+	header := state.Deneb.LatestBlockHeader
+	if header.ProposerTEEType != uint8(teeType) {
+		t.Errorf("unexpected proposer TEE type: got %d, want %d", header.ProposerTEEType, teeType)
+	}
+
+	if !bytes.Equal(header.ProposerTEEQuote[:len(teeQuote)], teeQuote) || !bytes.Equal(header.ProposerTEEQuote[len(teeQuote):], make([]byte, len(header.ProposerTEEQuote)-len(teeQuote))) {
+		t.Errorf("unexpected proposer TEE quote in header")
+	}
+}
@@ -0,0 +1,67 @@
+package beaconchain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/core"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// defaultSeedGenesisTimeWindow is the default SEED_GENESIS_TIME_WINDOW, one day in seconds.
+const defaultSeedGenesisTimeWindow = 86400
+
+// BuildFromSeed builds a genesis state with everything derived from a single seed, for fully reproducible
+// test networks. The count validators are generated from a mnemonic derived from the seed, the genesis time
+// is MIN_GENESIS_TIME plus a seed derived offset within SEED_GENESIS_TIME_WINDOW seconds (default one day),
+// and the TEE quote is derived from the genesis validators root (TEE_QUOTE_MODE=derived).
+// Builds with the same seed, config and EL genesis serialize to identical bytes. cfg is not modified.
+func BuildFromSeed(seed []byte, count uint64, cfg *beaconconfig.Config, elGenesis *core.Genesis) (*spec.VersionedBeaconState, error) {
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("seed is empty")
+	}
+
+	mnemonic, err := validators.MnemonicFromSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive mnemonic from seed: %w", err)
+	}
+
+	vals, err := validators.GenerateValidatorsFromMnemonics([]validators.MnemonicSrc{{
+		Mnemonic: mnemonic,
+		Count:    count,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate validators from seed: %w", err)
+	}
+
+	seedCfg := cfg.Clone()
+	seedCfg.SetUint("GENESIS_TIME", seedGenesisTime(seed, seedCfg))
+	seedCfg.SetString("TEE_QUOTE_MODE", "derived")
+
+	builder := NewGenesisBuilder(elGenesis, seedCfg)
+	if builder == nil {
+		return nil, fmt.Errorf("no genesis builder for the configured fork")
+	}
+
+	builder.AddValidators(vals)
+
+	return builder.BuildState()
+}
+
+// seedGenesisTime derives the genesis time from the seed, within SEED_GENESIS_TIME_WINDOW seconds after MIN_GENESIS_TIME.
+func seedGenesisTime(seed []byte, cfg *beaconconfig.Config) uint64 {
+	window := cfg.GetUintDefault("SEED_GENESIS_TIME_WINDOW", defaultSeedGenesisTimeWindow)
+	minGenesisTime := cfg.GetUintDefault("MIN_GENESIS_TIME", 0)
+
+	if window == 0 {
+		return minGenesisTime
+	}
+
+	hash := sha256.Sum256(append([]byte("genesis-time"), seed...))
+
+	return minGenesisTime + binary.BigEndian.Uint64(hash[:8])%window
+}
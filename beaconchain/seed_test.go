@@ -0,0 +1,49 @@
+package beaconchain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestBuildFromSeedDeterminism(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"MIN_GENESIS_TIME": uint64(1_700_000_000),
+	})
+
+	serialize := func(seed []byte) ([]byte, uint64) {
+		state, err := BuildFromSeed(seed, 4, cfg, createTestELGenesis())
+		if err != nil {
+			t.Fatalf("failed to build state from seed: %v", err)
+		}
+
+		data, err := NewGenesisBuilder(createTestELGenesis(), cfg).Serialize(state, http.ContentTypeSSZ)
+		if err != nil {
+			t.Fatalf("failed to serialize state: %v", err)
+		}
+
+		return data, state.Deneb.GenesisTime
+	}
+
+	first, genesisTime := serialize([]byte("seed-a"))
+	second, _ := serialize([]byte("seed-a"))
+
+	if !bytes.Equal(first, second) {
+		t.Fatalf("builds with the same seed differ")
+	}
+
+	if genesisTime < 1_700_000_000 || genesisTime >= 1_700_000_000+defaultSeedGenesisTimeWindow {
+		t.Fatalf("genesis time %d outside of the seed window", genesisTime)
+	}
+
+	other, _ := serialize([]byte("seed-b"))
+	if bytes.Equal(first, other) {
+		t.Fatalf("builds with different seeds are identical")
+	}
+
+	if _, found := cfg.Get("GENESIS_TIME"); found {
+		t.Fatalf("BuildFromSeed modified the passed config")
+	}
+}
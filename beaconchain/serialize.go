@@ -0,0 +1,224 @@
+package beaconchain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/golang/snappy"
+	dynssz "github.com/pk910/dynamic-ssz"
+	"golang.org/x/sync/errgroup"
+)
+
+// SerializeBoth serializes the state to SSZ and JSON in parallel.
+// Both encodings reuse the builder's dynssz instance, so the ssz schema is only built once.
+func SerializeBoth(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState) (sszData, jsonData []byte, err error) {
+	var g errgroup.Group
+
+	g.Go(func() error {
+		data, err := builder.Serialize(state, http.ContentTypeSSZ)
+		if err != nil {
+			return err
+		}
+
+		sszData = data
+
+		return nil
+	})
+
+	g.Go(func() error {
+		data, err := builder.Serialize(state, http.ContentTypeJSON)
+		if err != nil {
+			return err
+		}
+
+		jsonData = data
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return sszData, jsonData, nil
+}
+
+// SerializeToWriter serializes the state with the given content type and writes it to w.
+func SerializeToWriter(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState, contentType http.ContentType, w io.Writer) error {
+	data, err := builder.Serialize(state, contentType)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s state: %w", contentType.String(), err)
+	}
+
+	return nil
+}
+
+// SerializeSnappy serializes the state to SSZ and snappy block compresses it, the way SSZ payloads
+// are compressed on libp2p gossip topics. Useful to test gossip decoding against a known state.
+func SerializeSnappy(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState) ([]byte, error) {
+	data, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		return nil, err
+	}
+
+	return snappy.Encode(nil, data), nil
+}
+
+// DecompressSnappy decompresses the output of SerializeSnappy back to the raw SSZ encoded state.
+func DecompressSnappy(data []byte) ([]byte, error) {
+	decoded, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snappy state: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// framedHeaderSize is the size of the header SerializeFramed prepends to the SSZ state:
+// 1 byte spec.DataVersion and 4 bytes little-endian SSZ length.
+const framedHeaderSize = 5
+
+// SerializeFramed serializes the state to SSZ and prepends a small frame header for tooling that expects one:
+// the state's spec.DataVersion as 1 byte and the SSZ length as 4 byte little-endian integer.
+func SerializeFramed(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState) ([]byte, error) {
+	data, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(data)) > math.MaxUint32 {
+		return nil, fmt.Errorf("ssz state of %d bytes does not fit the frame length", len(data))
+	}
+
+	framed := make([]byte, framedHeaderSize, framedHeaderSize+len(data))
+	framed[0] = byte(state.Version)
+	binary.LittleEndian.PutUint32(framed[1:], uint32(len(data))) //nolint:gosec // checked above
+
+	return append(framed, data...), nil
+}
+
+// ParseFramedState decodes the output of SerializeFramed with the given dynssz instance. The frame length
+// must match the payload exactly.
+func ParseFramedState(ds *dynssz.DynSsz, data []byte) (*spec.VersionedBeaconState, error) {
+	if len(data) < framedHeaderSize {
+		return nil, fmt.Errorf("framed state is too short: %d bytes", len(data))
+	}
+
+	version := spec.DataVersion(data[0])
+	length := binary.LittleEndian.Uint32(data[1:framedHeaderSize])
+
+	if uint64(len(data)-framedHeaderSize) != uint64(length) {
+		return nil, fmt.Errorf("framed state length mismatch: header says %d bytes, payload has %d", length, len(data)-framedHeaderSize)
+	}
+
+	state, stateObj, err := newVersionedState(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ds.UnmarshalSSZ(stateObj, data[framedHeaderSize:]); err != nil {
+		return nil, fmt.Errorf("failed to decode framed %s state: %w", version.String(), err)
+	}
+
+	return state, nil
+}
+
+// DefaultOutputFileTemplate is the output file name template used by WriteAll.
+const DefaultOutputFileTemplate = "genesis"
+
+// WriteAll writes the state in each of the given formats to dir, reusing the builder's dynssz instance.
+// The file names derive from the content type (genesis.ssz, genesis.json).
+func WriteAll(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState, dir string, formats []http.ContentType) error {
+	return WriteAllWithTemplate(builder, state, dir, formats, DefaultOutputFileTemplate)
+}
+
+// WriteAllWithTemplate is WriteAll with a custom file name template. The template is the file name without
+// extension and may contain the placeholders {state_root} and {genesis_time}, e.g. "genesis-{state_root}"
+// writes genesis-0xabcd....ssz and genesis-0xabcd....json.
+func WriteAllWithTemplate(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState, dir string, formats []http.ContentType, nameTemplate string) error {
+	baseName, err := expandOutputFileTemplate(builder, state, nameTemplate)
+	if err != nil {
+		return err
+	}
+
+	for _, format := range formats {
+		fileName, err := outputFileName(baseName, format)
+		if err != nil {
+			return err
+		}
+
+		if err := writeStateFile(builder, state, format, filepath.Join(dir, fileName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func expandOutputFileTemplate(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState, nameTemplate string) (string, error) {
+	if nameTemplate == "" {
+		nameTemplate = DefaultOutputFileTemplate
+	}
+
+	if !strings.Contains(nameTemplate, "{state_root}") && !strings.Contains(nameTemplate, "{genesis_time}") {
+		return nameTemplate, nil
+	}
+
+	provider, ok := builder.(DynSSZProvider)
+	if !ok {
+		return "", fmt.Errorf("output file template %q needs a builder with a dynssz instance", nameTemplate)
+	}
+
+	meta, err := NewGenesisMeta(provider.DynSSZ(), state, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	replacer := strings.NewReplacer(
+		"{state_root}", meta.StateRoot.String(),
+		"{genesis_time}", strconv.FormatUint(meta.GenesisTime, 10),
+	)
+
+	return replacer.Replace(nameTemplate), nil
+}
+
+func outputFileName(baseName string, contentType http.ContentType) (string, error) {
+	switch contentType {
+	case http.ContentTypeSSZ:
+		return baseName + ".ssz", nil
+	case http.ContentTypeJSON:
+		return baseName + ".json", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
+
+func writeStateFile(builder BeaconGenesisBuilder, state *spec.VersionedBeaconState, contentType http.ContentType, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := SerializeToWriter(builder, state, contentType, file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+
+	return nil
+}
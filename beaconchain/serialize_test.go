@@ -0,0 +1,269 @@
+package beaconchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+)
+
+func TestSerializeBoth(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	sszData, jsonData, err := SerializeBoth(builder, state)
+	if err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+
+	expectedSSZ, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state to ssz: %v", err)
+	}
+
+	if !bytes.Equal(sszData, expectedSSZ) {
+		t.Fatalf("ssz output differs from Serialize")
+	}
+
+	sszState := &deneb.BeaconState{}
+	if err := builder.(DynSSZProvider).DynSSZ().UnmarshalSSZ(sszState, sszData); err != nil {
+		t.Fatalf("failed to decode ssz state: %v", err)
+	}
+
+	jsonState := &deneb.BeaconState{}
+	if err := jsonState.UnmarshalJSON(jsonData); err != nil {
+		t.Fatalf("failed to decode json state: %v", err)
+	}
+
+	sszRoot, err := builder.(DynSSZProvider).DynSSZ().HashTreeRoot(sszState)
+	if err != nil {
+		t.Fatalf("failed to hash ssz state: %v", err)
+	}
+
+	jsonRoot, err := builder.(DynSSZProvider).DynSSZ().HashTreeRoot(jsonState)
+	if err != nil {
+		t.Fatalf("failed to hash json state: %v", err)
+	}
+
+	if sszRoot != jsonRoot {
+		t.Fatalf("ssz and json outputs are inconsistent: %x != %x", sszRoot, jsonRoot)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	outputDir := t.TempDir()
+
+	if err := WriteAll(builder, state, outputDir, []http.ContentType{http.ContentTypeSSZ, http.ContentTypeJSON}); err != nil {
+		t.Fatalf("failed to write outputs: %v", err)
+	}
+
+	expectedRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	for _, fileName := range []string{"genesis.ssz", "genesis.json"} {
+		loadedState, err := LoadStateWithDynSSZ(filepath.Join(outputDir, fileName), spec.DataVersionDeneb, builder.(DynSSZProvider).DynSSZ())
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", fileName, err)
+		}
+
+		root, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), loadedState)
+		if err != nil {
+			t.Fatalf("failed to compute state root of %s: %v", fileName, err)
+		}
+
+		if root != expectedRoot {
+			t.Errorf("state root mismatch for %s: got %x, want %x", fileName, root, expectedRoot)
+		}
+	}
+}
+
+func TestSerializeUnsupportedErrors(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if _, err := builder.Serialize(state, http.ContentType(99)); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Fatalf("expected ErrUnsupportedContentType, got %v", err)
+	}
+
+	unknownState := &spec.VersionedBeaconState{Version: spec.DataVersion(99)}
+	if _, err := builder.Serialize(unknownState, http.ContentTypeSSZ); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestSerializeSnappyRoundTrip(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	compressed, err := SerializeSnappy(builder, state)
+	if err != nil {
+		t.Fatalf("failed to serialize snappy state: %v", err)
+	}
+
+	sszData, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state to ssz: %v", err)
+	}
+
+	if len(compressed) >= len(sszData) {
+		t.Fatalf("expected compressed state to be smaller than raw ssz (%d >= %d)", len(compressed), len(sszData))
+	}
+
+	decompressed, err := DecompressSnappy(compressed)
+	if err != nil {
+		t.Fatalf("failed to decompress state: %v", err)
+	}
+
+	if !bytes.Equal(decompressed, sszData) {
+		t.Fatalf("decompressed state differs from raw ssz")
+	}
+
+	if _, err := DecompressSnappy([]byte{0xff, 0xff}); err == nil {
+		t.Fatalf("expected error for invalid snappy data")
+	}
+}
+
+func TestSerializeFramedRoundTrip(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	framed, err := SerializeFramed(builder, state)
+	if err != nil {
+		t.Fatalf("failed to serialize framed state: %v", err)
+	}
+
+	sszData, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state to ssz: %v", err)
+	}
+
+	if framed[0] != byte(spec.DataVersionDeneb) || binary.LittleEndian.Uint32(framed[1:5]) != uint32(len(sszData)) {
+		t.Fatalf("unexpected frame header: %x", framed[:5])
+	}
+
+	if !bytes.Equal(framed[5:], sszData) {
+		t.Fatalf("framed payload differs from raw ssz")
+	}
+
+	parsed, err := ParseFramedState(builder.(DynSSZProvider).DynSSZ(), framed)
+	if err != nil {
+		t.Fatalf("failed to parse framed state: %v", err)
+	}
+
+	if parsed.Version != spec.DataVersionDeneb {
+		t.Fatalf("unexpected parsed version: %s", parsed.Version)
+	}
+
+	parsedRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), parsed)
+	if err != nil {
+		t.Fatalf("failed to compute parsed state root: %v", err)
+	}
+
+	stateRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	if parsedRoot != stateRoot {
+		t.Fatalf("parsed state root %x differs from built state root %x", parsedRoot, stateRoot)
+	}
+
+	if _, err := ParseFramedState(builder.(DynSSZProvider).DynSSZ(), framed[:len(framed)-1]); err == nil {
+		t.Fatalf("expected error for truncated framed state")
+	}
+}
+
+func TestWriteAllWithTemplate(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	meta, err := NewGenesisMeta(builder.(DynSSZProvider).DynSSZ(), state, "", "")
+	if err != nil {
+		t.Fatalf("failed to collect genesis metadata: %v", err)
+	}
+
+	outputDir := t.TempDir()
+
+	if err := WriteAllWithTemplate(builder, state, outputDir, []http.ContentType{http.ContentTypeSSZ}, "genesis-{state_root}-{genesis_time}"); err != nil {
+		t.Fatalf("failed to write outputs: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(outputDir, "genesis-*.ssz"))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("expected one templated output file, got %v (%v)", matches, err)
+	}
+
+	fileName := filepath.Base(matches[0])
+	if !strings.HasPrefix(fileName, "genesis-"+meta.StateRoot.String()[:10]) {
+		t.Fatalf("file name %s does not contain the state root prefix %s", fileName, meta.StateRoot.String()[:10])
+	}
+
+	expected := fmt.Sprintf("genesis-%s-%d.ssz", meta.StateRoot.String(), meta.GenesisTime)
+	if fileName != expected {
+		t.Fatalf("unexpected file name: got %s, want %s", fileName, expected)
+	}
+
+	// an empty template falls back to genesis.ssz
+	if err := WriteAllWithTemplate(builder, state, outputDir, []http.ContentType{http.ContentTypeSSZ}, ""); err != nil {
+		t.Fatalf("failed to write outputs: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "genesis.ssz")); err != nil {
+		t.Fatalf("expected default output file: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package beaconchain
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+// sszOffsetSize is the size of the offset that a container stores for each variable size field.
+const sszOffsetSize = 4
+
+// StateFieldSize is the SSZ size of a top level field of a beacon state.
+type StateFieldSize struct {
+	Name string
+	// Size is the encoded size of the field, without the offset stored for variable size fields.
+	Size int
+	// Dynamic is set for variable size fields, which take an additional 4 byte offset in the state.
+	Dynamic bool
+}
+
+// DumpStateSSZ returns the SSZ size of each top level field of the state in encoding order.
+// The sizes plus 4 bytes per dynamic field add up to the size of the encoded state.
+func DumpStateSSZ(ds *dynssz.DynSsz, state *spec.VersionedBeaconState) ([]StateFieldSize, error) {
+	stateObj, err := versionedStateObject(state)
+	if err != nil {
+		return nil, err
+	}
+
+	stateValue := reflect.ValueOf(stateObj)
+	if stateValue.IsNil() {
+		return nil, fmt.Errorf("%s state is nil", state.Version)
+	}
+
+	stateDesc, err := ds.GetTypeCache().GetTypeDescriptor(stateValue.Type(), nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state type descriptor: %w", err)
+	}
+
+	if stateDesc.ContainerDesc == nil {
+		return nil, fmt.Errorf("state type %s is not a container", stateValue.Type())
+	}
+
+	fields := make([]StateFieldSize, 0, len(stateDesc.ContainerDesc.Fields))
+	stateValue = stateValue.Elem()
+
+	for _, field := range stateDesc.ContainerDesc.Fields {
+		fieldSize := StateFieldSize{
+			Name:    field.Name,
+			Size:    int(field.Type.Size),
+			Dynamic: field.Type.SszTypeFlags&dynssz.SszTypeFlagIsDynamic != 0,
+		}
+
+		if fieldSize.Dynamic {
+			size, err := ds.SizeSSZ(stateValue.FieldByName(field.Name).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute size of %s: %w", field.Name, err)
+			}
+
+			fieldSize.Size = size
+		}
+
+		fields = append(fields, fieldSize)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,55 @@
+package beaconchain
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestDumpStateSSZ(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	sszData, err := builder.Serialize(state, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize state: %v", err)
+	}
+
+	fieldSizes, err := DumpStateSSZ(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to dump state field sizes: %v", err)
+	}
+
+	totalSize := 0
+	offsetsSize := 0
+	sizeByName := map[string]int{}
+
+	for _, field := range fieldSizes {
+		totalSize += field.Size
+		sizeByName[field.Name] = field.Size
+
+		if field.Dynamic {
+			offsetsSize += sszOffsetSize
+		}
+	}
+
+	if totalSize != len(sszData)-offsetsSize {
+		t.Fatalf("field sizes add up to %d bytes, expected %d (%d bytes minus %d bytes of offsets)", totalSize, len(sszData)-offsetsSize, len(sszData), offsetsSize)
+	}
+
+	if sizeByName["LatestBlockHeader"] != state.Deneb.LatestBlockHeader.SizeSSZ() {
+		t.Errorf("unexpected header size: got %d, want %d", sizeByName["LatestBlockHeader"], state.Deneb.LatestBlockHeader.SizeSSZ())
+	}
+
+	if sizeByName["Validators"] != 8*121 {
+		t.Errorf("unexpected validators size: got %d, want %d", sizeByName["Validators"], 8*121)
+	}
+}
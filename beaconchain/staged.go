@@ -0,0 +1,195 @@
+package beaconchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+var (
+	// ErrStagedBuildNotStarted is returned by the staged builder if Begin was not called.
+	ErrStagedBuildNotStarted = errors.New("staged build not started")
+	// ErrStagedBuildFinalized is returned by the staged builder after Finalize.
+	ErrStagedBuildFinalized = errors.New("staged build already finalized")
+	// ErrStagedBuildFailed is returned by the staged builder after a validator batch could not be added.
+	ErrStagedBuildFailed = errors.New("staged build failed")
+)
+
+// StagedGenesisBuilder builds a genesis state in passes: Begin sets up the fork builder and checks the
+// non-validator config, AddValidatorsStreaming consumes validator batches as a loader produces them, and
+// Finalize computes the remaining state fields.
+// Each batch is checked and converted to validator records, balances and leaves of the validators root as it
+// arrives, the input validators are not kept. Peak memory is the validator records and balances of the state plus
+// the batch in flight, instead of the input validators, their records and a full registry hash at the end.
+// The finalized state is identical to the one built by NewGenesisBuilder. SORT_VALIDATORS_BY_PUBKEY needs the full
+// validator set and is rejected, the validators cache is not used and StateValidators of the builder returns nil.
+type StagedGenesisBuilder struct {
+	elGenesis       *core.Genesis
+	clConfig        *beaconconfig.Config
+	shadowForkBlock *types.Block
+
+	builder      BeaconGenesisBuilder
+	stream       *beaconutils.GenesisValidatorStream
+	fillersAdded bool
+	// failed is the error of a batch that was only partially added to the stream.
+	failed    error
+	finalized bool
+}
+
+// NewStagedGenesisBuilder creates a staged builder for the genesis fork of the config.
+func NewStagedGenesisBuilder(elGenesis *core.Genesis, clConfig *beaconconfig.Config) *StagedGenesisBuilder {
+	return &StagedGenesisBuilder{
+		elGenesis: elGenesis,
+		clConfig:  clConfig,
+	}
+}
+
+// SetShadowForkBlock sets the execution block to create a shadow fork from. It must be called before Begin.
+func (s *StagedGenesisBuilder) SetShadowForkBlock(block *types.Block) {
+	s.shadowForkBlock = block
+}
+
+// Begin prepares the build: it selects the fork builder, checks the config of the genesis fork and sets up the
+// validator stream.
+func (s *StagedGenesisBuilder) Begin() error {
+	if s.finalized {
+		return ErrStagedBuildFinalized
+	}
+
+	if s.builder != nil {
+		return fmt.Errorf("staged build already started")
+	}
+
+	version := GetGenesisForkVersion(s.clConfig)
+	if err := ValidateForFork(version, s.clConfig); err != nil {
+		return err
+	}
+
+	if s.clConfig.GetBoolDefault("SORT_VALIDATORS_BY_PUBKEY", false) {
+		return fmt.Errorf("SORT_VALIDATORS_BY_PUBKEY is not supported by staged builds")
+	}
+
+	forkConfig := GetForkConfig(version)
+	if forkConfig == nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedVersion, version.String())
+	}
+
+	builder := forkConfig.BuilderFn(s.elGenesis, s.clConfig)
+
+	streamer, ok := builder.(interface {
+		setValidatorStream(stream *beaconutils.GenesisValidatorStream)
+	})
+	if !ok {
+		return fmt.Errorf("%w: %s builder does not support staged builds", ErrUnsupportedVersion, version.String())
+	}
+
+	s.stream = beaconutils.NewGenesisValidatorStream(s.clConfig)
+	streamer.setValidatorStream(s.stream)
+
+	if s.shadowForkBlock != nil {
+		builder.SetShadowForkBlock(s.shadowForkBlock)
+	}
+
+	s.builder = wrapBuilder(builder, s.clConfig)
+
+	return nil
+}
+
+// AddValidatorsStreaming checks and converts the validator batches received from batches until the channel is
+// closed. Batches are added in the order they are received. Returns the number of validators added. If a batch
+// fails, the remaining batches are drained so the producer does not block, and the build cannot be finalized.
+func (s *StagedGenesisBuilder) AddValidatorsStreaming(batches <-chan []*validators.Validator) (uint64, error) {
+	if err := s.checkStarted(); err != nil {
+		return 0, err
+	}
+
+	added := uint64(0)
+
+	for batch := range batches {
+		if err := s.addBatch(batch); err != nil {
+			s.failed = err
+
+			// drain the remaining batches so the producer does not block
+			for range batches {
+			}
+
+			return added, err
+		}
+
+		added += uint64(len(batch))
+	}
+
+	return added, nil
+}
+
+func (s *StagedGenesisBuilder) addBatch(batch []*validators.Validator) error {
+	if err := validateGenesisWithdrawalAddresses(s.clConfig, batch); err != nil {
+		return fmt.Errorf("failed to validate withdrawal addresses: %w", err)
+	}
+
+	if err := validateGenesisDepositAmounts(s.clConfig, batch); err != nil {
+		return fmt.Errorf("failed to validate deposit amounts: %w", err)
+	}
+
+	return s.stream.Add(batch)
+}
+
+// Finalize appends the FILLER_VALIDATORS and builds the genesis state from the streamed validators. The build is
+// only marked as finalized if the state was built successfully.
+func (s *StagedGenesisBuilder) Finalize() (*spec.VersionedBeaconState, error) {
+	if err := s.checkStarted(); err != nil {
+		return nil, err
+	}
+
+	if s.stream.Count() == 0 {
+		return nil, fmt.Errorf("no validators were added to the staged build")
+	}
+
+	if !s.fillersAdded {
+		if fillerCount := s.clConfig.GetUintDefault("FILLER_VALIDATORS", 0); fillerCount > 0 {
+			if err := s.addBatch(validators.GenerateFillerValidators(fillerCount)); err != nil {
+				s.failed = err
+				return nil, fmt.Errorf("failed to add filler validators: %w", err)
+			}
+		}
+
+		s.fillersAdded = true
+	}
+
+	state, err := s.builder.BuildState()
+	if err != nil {
+		return nil, err
+	}
+
+	s.finalized = true
+
+	return state, nil
+}
+
+// Builder returns the underlying genesis builder, e.g. to serialize the finalized state. It is nil before Begin.
+func (s *StagedGenesisBuilder) Builder() BeaconGenesisBuilder {
+	return s.builder
+}
+
+func (s *StagedGenesisBuilder) checkStarted() error {
+	if s.finalized {
+		return ErrStagedBuildFinalized
+	}
+
+	if s.builder == nil {
+		return ErrStagedBuildNotStarted
+	}
+
+	if s.failed != nil {
+		return fmt.Errorf("%w: %w", ErrStagedBuildFailed, s.failed)
+	}
+
+	return nil
+}
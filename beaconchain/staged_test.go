@@ -0,0 +1,205 @@
+package beaconchain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+func TestStagedBuilderMatchesOneShot(t *testing.T) {
+	const validatorCount = 50_000
+
+	const batchSize = 4096
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{})
+	vals := createTestValidators(t, validatorCount)
+
+	oneShot := NewGenesisBuilder(createTestELGenesis(), cfg)
+	oneShot.AddValidators(vals)
+
+	expected, err := oneShot.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build one-shot state: %v", err)
+	}
+
+	staged := NewStagedGenesisBuilder(createTestELGenesis(), cfg)
+
+	if _, err := staged.AddValidatorsStreaming(nil); !errors.Is(err, ErrStagedBuildNotStarted) {
+		t.Fatalf("expected not started error, got %v", err)
+	}
+
+	if err := staged.Begin(); err != nil {
+		t.Fatalf("failed to begin staged build: %v", err)
+	}
+
+	batches := make(chan []*validators.Validator)
+
+	go func() {
+		defer close(batches)
+
+		for start := 0; start < len(vals); start += batchSize {
+			batches <- vals[start:min(start+batchSize, len(vals))]
+		}
+	}()
+
+	added, err := staged.AddValidatorsStreaming(batches)
+	if err != nil {
+		t.Fatalf("failed to stream validators: %v", err)
+	}
+
+	if added != validatorCount {
+		t.Fatalf("expected %d streamed validators, got %d", validatorCount, added)
+	}
+
+	actual, err := staged.Finalize()
+	if err != nil {
+		t.Fatalf("failed to finalize staged build: %v", err)
+	}
+
+	ds := beaconutils.GetDynSSZ(cfg)
+
+	expectedRoot, err := ComputeStateRoot(ds, expected)
+	if err != nil {
+		t.Fatalf("failed to compute one-shot state root: %v", err)
+	}
+
+	actualRoot, err := ComputeStateRoot(ds, actual)
+	if err != nil {
+		t.Fatalf("failed to compute staged state root: %v", err)
+	}
+
+	if expectedRoot != actualRoot {
+		t.Fatalf("staged state root %s differs from one-shot root %s", actualRoot.String(), expectedRoot.String())
+	}
+
+	if _, err := staged.Finalize(); !errors.Is(err, ErrStagedBuildFinalized) {
+		t.Fatalf("expected finalized error, got %v", err)
+	}
+}
+
+func TestStagedBuilderMatchesOneShotWithSaltAndFillers(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"VALIDATORS_ROOT_SALT": uint64(5),
+		"FILLER_VALIDATORS":    uint64(3),
+	})
+	vals := createTestValidators(t, 64)
+
+	oneShot := NewGenesisBuilder(createTestELGenesis(), cfg)
+	oneShot.AddValidators(vals)
+
+	expected, err := oneShot.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build one-shot state: %v", err)
+	}
+
+	staged := NewStagedGenesisBuilder(createTestELGenesis(), cfg)
+	if err := staged.Begin(); err != nil {
+		t.Fatalf("failed to begin staged build: %v", err)
+	}
+
+	batches := make(chan []*validators.Validator, 2)
+	batches <- vals[:20]
+	batches <- vals[20:]
+	close(batches)
+
+	if _, err := staged.AddValidatorsStreaming(batches); err != nil {
+		t.Fatalf("failed to stream validators: %v", err)
+	}
+
+	actual, err := staged.Finalize()
+	if err != nil {
+		t.Fatalf("failed to finalize staged build: %v", err)
+	}
+
+	ds := beaconutils.GetDynSSZ(cfg)
+
+	expectedRoot, err := ComputeStateRoot(ds, expected)
+	if err != nil {
+		t.Fatalf("failed to compute one-shot state root: %v", err)
+	}
+
+	actualRoot, err := ComputeStateRoot(ds, actual)
+	if err != nil {
+		t.Fatalf("failed to compute staged state root: %v", err)
+	}
+
+	if expectedRoot != actualRoot {
+		t.Fatalf("staged state root %s differs from one-shot root %s", actualRoot.String(), expectedRoot.String())
+	}
+
+	if staged.Builder().(StateValidatorsProvider).StateValidators() != nil {
+		t.Fatalf("expected staged builds to not keep the input validators")
+	}
+}
+
+func TestStagedBuilderStreamingError(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"VALIDATOR_REGISTRY_LIMIT": uint64(2),
+	})
+
+	staged := NewStagedGenesisBuilder(createTestELGenesis(), cfg)
+	if err := staged.Begin(); err != nil {
+		t.Fatalf("failed to begin staged build: %v", err)
+	}
+
+	batches := make(chan []*validators.Validator, 3)
+	batches <- createTestValidators(t, 4)
+	batches <- createTestValidators(t, 1)
+	batches <- createTestValidators(t, 1)
+	close(batches)
+
+	if _, err := staged.AddValidatorsStreaming(batches); err == nil || !strings.Contains(err.Error(), "VALIDATOR_REGISTRY_LIMIT") {
+		t.Fatalf("expected validator registry limit error, got %v", err)
+	}
+
+	if len(batches) != 0 {
+		t.Fatalf("expected the remaining batches to be drained")
+	}
+
+	if _, err := staged.Finalize(); !errors.Is(err, ErrStagedBuildFailed) {
+		t.Fatalf("expected failed build error, got %v", err)
+	}
+}
+
+func TestStagedBuilderRejectsSortedValidators(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"SORT_VALIDATORS_BY_PUBKEY": "true",
+	})
+
+	staged := NewStagedGenesisBuilder(createTestELGenesis(), cfg)
+	if err := staged.Begin(); err == nil || !strings.Contains(err.Error(), "SORT_VALIDATORS_BY_PUBKEY") {
+		t.Fatalf("expected SORT_VALIDATORS_BY_PUBKEY error, got %v", err)
+	}
+}
+
+func TestStagedBuilderFinalizeError(t *testing.T) {
+	// the salt must be lower than FAR_FUTURE_EPOCH, which only fails when the validators root is computed
+	cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, map[string]interface{}{
+		"VALIDATORS_ROOT_SALT": uint64(18446744073709551615),
+	})
+
+	staged := NewStagedGenesisBuilder(createTestELGenesis(), cfg)
+	if err := staged.Begin(); err != nil {
+		t.Fatalf("failed to begin staged build: %v", err)
+	}
+
+	batches := make(chan []*validators.Validator, 1)
+	batches <- createTestValidators(t, 4)
+	close(batches)
+
+	if _, err := staged.AddValidatorsStreaming(batches); err != nil {
+		t.Fatalf("failed to stream validators: %v", err)
+	}
+
+	for range 2 {
+		_, err := staged.Finalize()
+		if err == nil || errors.Is(err, ErrStagedBuildFinalized) || !strings.Contains(err.Error(), "VALIDATORS_ROOT_SALT") {
+			t.Fatalf("expected validators root salt error, got %v", err)
+		}
+	}
+}
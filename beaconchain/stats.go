@@ -0,0 +1,54 @@
+package beaconchain
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+// BuildStats summarizes the validator set and genesis time source of a built genesis state.
+type BuildStats struct {
+	ValidatorCount        uint64
+	ActiveValidatorCount  uint64
+	TotalBalance          phase0.Gwei
+	TotalEffectiveBalance phase0.Gwei
+	// GenesisTimeSource names the config path that produced the genesis time.
+	GenesisTimeSource GenesisTimeSource
+	// Diagnostics holds the warnings and errors logged while the state was built.
+	Diagnostics []Diagnostic
+}
+
+// newBuildStats summarizes the validators and balances of a built state. The totals are summed with
+// overflow checks, an error is returned instead of a wrapped total.
+func newBuildStats(vals []*phase0.Validator, balances []phase0.Gwei) (*BuildStats, error) {
+	stats := &BuildStats{
+		ValidatorCount: uint64(len(vals)),
+	}
+
+	effectiveBalances := make([]phase0.Gwei, len(vals))
+
+	for i, val := range vals {
+		if val.ActivationEpoch == 0 {
+			stats.ActiveValidatorCount++
+		}
+
+		effectiveBalances[i] = val.EffectiveBalance
+	}
+
+	totalEffectiveBalance, err := beaconutils.SumGwei(effectiveBalances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute total effective balance: %w", err)
+	}
+
+	totalBalance, err := beaconutils.SumGwei(balances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute total balance: %w", err)
+	}
+
+	stats.TotalEffectiveBalance = totalEffectiveBalance
+	stats.TotalBalance = totalBalance
+
+	return stats, nil
+}
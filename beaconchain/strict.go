@@ -0,0 +1,233 @@
+package beaconchain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// isStrictMode returns true if STRICT is enabled, which turns sanity check warnings into errors.
+func isStrictMode(cfg *beaconconfig.Config) bool {
+	return cfg != nil && cfg.GetBoolDefault("STRICT", false)
+}
+
+// warnOrError logs and records a warning with the given diagnostic code. In strict mode it records an error
+// diagnostic instead and returns it as error.
+func warnOrError(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, code DiagnosticCode, format string, args ...any) error {
+	if isStrictMode(cfg) {
+		err := fmt.Errorf(format, args...)
+		diagnostics.fail(code, err.Error())
+
+		return err
+	}
+
+	diagnostics.warn(code, format, args...)
+
+	return nil
+}
+
+// checkGenesisBlockNumber checks that the execution block of a true genesis is block 0.
+// Shadow forks start from an arbitrary block, so the check is skipped for them.
+func checkGenesisBlockNumber(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block, isShadowFork bool) error {
+	if isShadowFork || genesisBlock.NumberU64() == 0 {
+		return nil
+	}
+
+	return warnOrError(cfg, diagnostics, DiagnosticGenesisBlockNumber, "execution genesis block number is %d, expected 0 for a non shadow fork genesis", genesisBlock.NumberU64())
+}
+
+// checkGenesisWithdrawals checks that the execution block of a true genesis has no withdrawals.
+// Shadow forks start from an arbitrary block that may carry withdrawals, so the check is skipped for them.
+func checkGenesisWithdrawals(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block, isShadowFork bool) error {
+	if isShadowFork || len(genesisBlock.Withdrawals()) == 0 {
+		return nil
+	}
+
+	return warnOrError(cfg, diagnostics, DiagnosticGenesisWithdrawals, "execution genesis block has %d withdrawals, expected none for a non shadow fork genesis", len(genesisBlock.Withdrawals()))
+}
+
+// checkGenesisTransactions checks that the execution block of a true genesis has no transactions, so the
+// transactions root is the empty list root. Shadow forks start from an arbitrary block, so the check is skipped for them.
+func checkGenesisTransactions(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block, isShadowFork bool) error {
+	if isShadowFork || len(genesisBlock.Transactions()) == 0 {
+		return nil
+	}
+
+	return warnOrError(cfg, diagnostics, DiagnosticGenesisTransactions, "execution genesis block has %d transactions, expected none for a non shadow fork genesis", len(genesisBlock.Transactions()))
+}
+
+// checkDepositContractDeployed checks that the execution genesis alloc has code at DEPOSIT_CONTRACT_ADDRESS if the
+// genesis has validators, clients validating the eth1 data expect the deposit contract to exist. Shadow forks and
+// builds without an execution genesis are skipped, their deposit contract is deployed on the original chain.
+func checkDepositContractDeployed(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, elGenesis *core.Genesis, validatorCount int, isShadowFork bool) error {
+	if isShadowFork || elGenesis == nil || validatorCount == 0 {
+		return nil
+	}
+
+	address, found := cfg.GetBytes("DEPOSIT_CONTRACT_ADDRESS")
+	if !found {
+		return nil
+	}
+
+	if len(address) != common.AddressLength {
+		return fmt.Errorf("DEPOSIT_CONTRACT_ADDRESS must be a 20 byte hex value")
+	}
+
+	if account, ok := elGenesis.Alloc[common.BytesToAddress(address)]; ok && len(account.Code) > 0 {
+		return nil
+	}
+
+	return warnOrError(cfg, diagnostics, DiagnosticDepositContract, "execution genesis alloc has no deposit contract code at %s, but the genesis has %d validators", common.BytesToAddress(address).Hex(), validatorCount)
+}
+
+// checkGenesisStateRoot checks that the execution genesis block has a non-zero state root.
+// An all-zero state root points to a broken EL genesis, e.g. a bug in the alloc.
+func checkGenesisStateRoot(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block) error {
+	if genesisBlock.Root() != (common.Hash{}) {
+		return nil
+	}
+
+	return warnOrError(cfg, diagnostics, DiagnosticGenesisStateRoot, "execution genesis state root is all zero, check the EL genesis alloc")
+}
+
+// checkGenesisBlobSchedule checks the blob gas fields of the execution genesis block against the BLOB_SCHEDULE
+// entry active at genesis: the blob gas used must be a whole number of blobs within the entry's MAX_BLOBS_PER_BLOCK.
+// The excess blob gas carries over from the blocks before a shadow fork and is not bound by the entry.
+// Configs without a blob schedule are not checked.
+func checkGenesisBlobSchedule(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, genesisBlock *types.Block) error {
+	entry, found, err := cfg.GetBlobScheduleEntryAt(0)
+	if err != nil {
+		return err
+	}
+
+	if !found || genesisBlock.BlobGasUsed() == nil {
+		return nil
+	}
+
+	blobGasUsed := *genesisBlock.BlobGasUsed()
+	maxBlobGas := entry.MaxBlobsPerBlock * params.BlobTxBlobGasPerBlob
+
+	if blobGasUsed%params.BlobTxBlobGasPerBlob != 0 || blobGasUsed > maxBlobGas {
+		return warnOrError(cfg, diagnostics, DiagnosticGenesisBlobSchedule, "execution genesis blob gas used %d does not match the blob schedule entry at epoch %d (max %d blobs per block)",
+			blobGasUsed, entry.Epoch, entry.MaxBlobsPerBlock)
+	}
+
+	return nil
+}
+
+// genesisCheckpointNames lists the checkpoints of a state in container order.
+var genesisCheckpointNames = []string{"previous_justified_checkpoint", "current_justified_checkpoint", "finalized_checkpoint"}
+
+// checkGenesisCheckpoints checks that the justified and finalized checkpoints of a genesis state are zero.
+// A non-zero checkpoint at genesis points to accidentally seeded state, all of them are listed in the error.
+func checkGenesisCheckpoints(state *spec.VersionedBeaconState) error {
+	fields, err := getStateInvariantFields(state)
+	if err != nil {
+		return err
+	}
+
+	nonZero := []string{}
+
+	for _, name := range genesisCheckpointNames {
+		checkpoint := fields.checkpoints[name]
+		if checkpoint != nil && (checkpoint.Epoch != 0 || checkpoint.Root != (phase0.Root{})) {
+			nonZero = append(nonZero, fmt.Sprintf("%s (epoch %d, root %#x)", name, checkpoint.Epoch, checkpoint.Root))
+		}
+	}
+
+	if len(nonZero) > 0 {
+		return fmt.Errorf("genesis state has non-zero checkpoints: %s", strings.Join(nonZero, ", "))
+	}
+
+	return nil
+}
+
+// checkGasLimit checks that the execution genesis gas limit is within [MIN_GAS_LIMIT, MAX_GAS_LIMIT].
+func checkGasLimit(cfg *beaconconfig.Config, diagnostics *diagnosticCollector, gasLimit uint64) error {
+	minGasLimit := cfg.GetUintDefault("MIN_GAS_LIMIT", 5000)
+	maxGasLimit := cfg.GetUintDefault("MAX_GAS_LIMIT", 1_000_000_000)
+
+	if gasLimit < minGasLimit || gasLimit > maxGasLimit {
+		return warnOrError(cfg, diagnostics, DiagnosticGasLimit, "execution genesis gas limit %d is outside of [%d, %d]", gasLimit, minGasLimit, maxGasLimit)
+	}
+
+	return nil
+}
+
+// strictBuilder wraps a genesis builder in STRICT mode and fails BuildState and Serialize if they recorded a
+// warning diagnostic. All warnings are returned as one aggregated error.
+type strictBuilder struct {
+	builderDecorator
+}
+
+// withStrictMode wraps the builder with strictBuilder if STRICT is enabled.
+func withStrictMode(builder BeaconGenesisBuilder, cfg *beaconconfig.Config) BeaconGenesisBuilder {
+	if builder == nil || !isStrictMode(cfg) {
+		return builder
+	}
+
+	return &strictBuilder{
+		builderDecorator: builderDecorator{BeaconGenesisBuilder: builder, clConfig: cfg},
+	}
+}
+
+func (b *strictBuilder) BuildState() (*spec.VersionedBeaconState, error) {
+	state, err := b.BeaconGenesisBuilder.BuildState()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := failOnWarnings(b.Diagnostics()); err != nil {
+		return nil, err
+	}
+
+	if err := checkGenesisCheckpoints(state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (b *strictBuilder) Serialize(state *spec.VersionedBeaconState, contentType http.ContentType) ([]byte, error) {
+	recorded := len(b.Diagnostics())
+
+	data, err := b.BeaconGenesisBuilder.Serialize(state, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the diagnostics recorded by this call, earlier ones were checked by BuildState
+	diagnostics := b.Diagnostics()
+	if err := failOnWarnings(diagnostics[min(recorded, len(diagnostics)):]); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// failOnWarnings returns the diagnostics with warning severity as aggregated error.
+func failOnWarnings(diagnostics []Diagnostic) error {
+	warnings := []error{}
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == DiagnosticSeverityWarning {
+			warnings = append(warnings, errors.New(diagnostic.Message))
+		}
+	}
+
+	if len(warnings) > 0 {
+		return fmt.Errorf("strict mode: %d warning(s): %w", len(warnings), errors.Join(warnings...))
+	}
+
+	return nil
+}
@@ -0,0 +1,529 @@
+package beaconchain
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGenesisBlockNumberCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		strict      bool
+		shadowFork  bool
+		expectError bool
+	}{
+		{name: "warning only", strict: false, shadowFork: false, expectError: false},
+		{name: "strict mode", strict: true, shadowFork: false, expectError: true},
+		{name: "strict mode shadow fork", strict: true, shadowFork: true, expectError: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{}
+			if tt.strict {
+				values["STRICT"] = "true"
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+			elGenesis := createTestELGenesis()
+			elGenesis.Number = 5
+
+			builder := NewGenesisBuilder(elGenesis, cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			if tt.shadowFork {
+				builder.SetShadowForkBlock(elGenesis.ToBlock())
+			}
+
+			_, err := builder.BuildState()
+			if tt.expectError && err == nil {
+				t.Fatalf("expected error for non-zero genesis block number")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGasLimitCheck(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		values := map[string]interface{}{}
+		if strict {
+			values["STRICT"] = "true"
+		}
+
+		cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+		header := createTestELGenesis().ToBlock().Header()
+		header.GasLimit = 0
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(createTestValidators(t, 4))
+		builder.SetShadowForkBlock(types.NewBlockWithHeader(header))
+
+		_, err := builder.BuildState()
+
+		switch {
+		case strict && err == nil:
+			t.Fatalf("expected error for zero gas limit in strict mode")
+		case strict && !strings.Contains(err.Error(), "gas limit 0"):
+			t.Fatalf("expected error to contain the gas limit, got %v", err)
+		case !strict && err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestGenesisWithdrawalsCheck(t *testing.T) {
+	withdrawalsBlock := createTestELGenesis().ToBlock().WithBody(types.Body{
+		Withdrawals: []*types.Withdrawal{{Index: 0, Validator: 1, Amount: 1000}},
+	})
+
+	tests := []struct {
+		name        string
+		strict      bool
+		shadowFork  bool
+		block       *types.Block
+		expectError bool
+	}{
+		{name: "no withdrawals", strict: true, block: createTestELGenesis().ToBlock()},
+		{name: "warning only", strict: false, block: withdrawalsBlock},
+		{name: "strict mode", strict: true, block: withdrawalsBlock, expectError: true},
+		{name: "strict mode shadow fork", strict: true, shadowFork: true, block: withdrawalsBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{}
+			if tt.strict {
+				values["STRICT"] = "true"
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+			err := checkGenesisWithdrawals(cfg, nil, tt.block, tt.shadowFork)
+			if tt.expectError && err == nil {
+				t.Fatalf("expected error for genesis block with withdrawals")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// shadow fork blocks with withdrawals build fine in strict mode
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{"STRICT": "true"})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+	builder.SetShadowForkBlock(withdrawalsBlock)
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("unexpected error for shadow fork with withdrawals: %v", err)
+	}
+}
+
+func TestStrictModeWarnings(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		values := map[string]interface{}{
+			"MIN_GENESIS_TIME_FALLBACK": "bogus",
+		}
+		if strict {
+			values["STRICT"] = "true"
+		}
+
+		cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(createTestValidators(t, 4))
+
+		_, err := builder.BuildState()
+
+		switch {
+		case strict && err == nil:
+			t.Fatalf("expected error for warning in strict mode")
+		case strict && !strings.Contains(err.Error(), "MIN_GENESIS_TIME_FALLBACK"):
+			t.Fatalf("expected error to contain the warning, got %v", err)
+		case !strict && err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// warnings are recorded by the builder, strict mode neither depends on nor changes the log level
+	prevLevel := logrus.GetLevel()
+	defer logrus.SetLevel(prevLevel)
+
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"MIN_GENESIS_TIME_FALLBACK": "bogus",
+		"STRICT":                    "true",
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected error for warning in strict mode with warnings disabled on the logger")
+	}
+
+	if logrus.GetLevel() != logrus.ErrorLevel {
+		t.Fatalf("strict mode changed the log level to %s", logrus.GetLevel())
+	}
+}
+
+func TestStrictModeHelperWarnings(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		code  DiagnosticCode
+	}{
+		{key: "SKIP_SYNC_COMMITTEE", value: "true", code: DiagnosticSyncCommittee},
+		{key: "TEE_QUOTE_MODE", value: "bogus", code: DiagnosticTEE},
+		{key: "VALIDATORS_ROOT_SALT", value: "5", code: DiagnosticValidatorsRootSalt},
+	}
+
+	for _, tt := range tests {
+		for _, strict := range []bool{false, true} {
+			values := map[string]interface{}{
+				tt.key: tt.value,
+			}
+			if strict {
+				values["STRICT"] = "true"
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 4))
+
+			_, err := builder.BuildState()
+
+			if strict {
+				if err == nil || !strings.Contains(err.Error(), tt.key) {
+					t.Fatalf("%s: expected strict mode error, got %v", tt.key, err)
+				}
+
+				continue
+			}
+
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.key, err)
+			}
+
+			found := false
+
+			for _, diagnostic := range builder.(DiagnosticsProvider).Diagnostics() {
+				found = found || diagnostic.Code == tt.code
+			}
+
+			if !found {
+				t.Fatalf("%s: expected %s diagnostic, got %+v", tt.key, tt.code, builder.(DiagnosticsProvider).Diagnostics())
+			}
+		}
+	}
+}
+
+func TestGenesisStateRootCheck(t *testing.T) {
+	header := createTestELGenesis().ToBlock().Header()
+	header.Root = common.Hash{}
+	zeroRootBlock := types.NewBlockWithHeader(header)
+
+	for _, strict := range []bool{false, true} {
+		values := map[string]interface{}{}
+		if strict {
+			values["STRICT"] = "true"
+		}
+
+		cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+		if err := checkGenesisStateRoot(cfg, nil, createTestELGenesis().ToBlock()); err != nil {
+			t.Fatalf("unexpected error for non-zero state root: %v", err)
+		}
+
+		builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+		builder.AddValidators(createTestValidators(t, 4))
+		builder.SetShadowForkBlock(zeroRootBlock)
+
+		_, err := builder.BuildState()
+
+		switch {
+		case strict && err == nil:
+			t.Fatalf("expected error for zero state root in strict mode")
+		case strict && !strings.Contains(err.Error(), "check the EL genesis alloc"):
+			t.Fatalf("expected error to mention the EL genesis alloc, got %v", err)
+		case !strict && err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestGenesisTransactionsCheck(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1)})
+	transactionsBlock := createTestELGenesis().ToBlock().WithBody(types.Body{
+		Transactions: []*types.Transaction{tx},
+	})
+
+	tests := []struct {
+		name        string
+		strict      bool
+		shadowFork  bool
+		block       *types.Block
+		expectError bool
+	}{
+		{name: "no transactions", strict: true, block: createTestELGenesis().ToBlock()},
+		{name: "warning only", strict: false, block: transactionsBlock},
+		{name: "strict mode", strict: true, block: transactionsBlock, expectError: true},
+		{name: "strict mode shadow fork", strict: true, shadowFork: true, block: transactionsBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{}
+			if tt.strict {
+				values["STRICT"] = "true"
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+			err := checkGenesisTransactions(cfg, nil, tt.block, tt.shadowFork)
+			if tt.expectError && (err == nil || !strings.Contains(err.Error(), "1 transactions")) {
+				t.Fatalf("expected error for genesis block with transactions, got %v", err)
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// shadow fork blocks with transactions build fine in strict mode
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{"STRICT": "true"})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+	builder.SetShadowForkBlock(transactionsBlock)
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("unexpected error for shadow fork with transactions: %v", err)
+	}
+}
+
+func TestGenesisCheckpointsCheck(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{"STRICT": "true"})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	if err := checkGenesisCheckpoints(state); err != nil {
+		t.Fatalf("unexpected error for built state: %v", err)
+	}
+
+	// accidentally seeded checkpoints
+	state.Deneb.CurrentJustifiedCheckpoint = &phase0.Checkpoint{Epoch: 1}
+	state.Deneb.FinalizedCheckpoint = &phase0.Checkpoint{Root: phase0.Root{0x01}}
+
+	err = checkGenesisCheckpoints(state)
+	if err == nil {
+		t.Fatalf("expected error for seeded checkpoints")
+	}
+
+	for _, name := range []string{"current_justified_checkpoint (epoch 1", "finalized_checkpoint (epoch 0, root 0x01"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to list %q, got %v", name, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), "previous_justified_checkpoint") {
+		t.Fatalf("zero checkpoint listed in error: %v", err)
+	}
+}
+
+func TestGenesisBlobScheduleCheck(t *testing.T) {
+	schedule := []map[string]interface{}{
+		{"EPOCH": 0, "MAX_BLOBS_PER_BLOCK": 9},
+		{"EPOCH": 100, "MAX_BLOBS_PER_BLOCK": 15},
+	}
+
+	blockWithBlobGas := func(blobs uint64) *types.Block {
+		elGenesis := createTestELGenesis()
+		blobGasUsed := blobs * params.BlobTxBlobGasPerBlob
+		elGenesis.BlobGasUsed = &blobGasUsed
+
+		return elGenesis.ToBlock()
+	}
+
+	tests := []struct {
+		name        string
+		schedule    []map[string]interface{}
+		block       *types.Block
+		expectError bool
+	}{
+		{name: "no schedule", block: blockWithBlobGas(12)},
+		{name: "matching", schedule: schedule, block: blockWithBlobGas(9)},
+		{name: "above genesis entry", schedule: schedule, block: blockWithBlobGas(12), expectError: true},
+		{name: "partial blob", schedule: schedule, block: func() *types.Block {
+			elGenesis := createTestELGenesis()
+			blobGasUsed := uint64(1000)
+			elGenesis.BlobGasUsed = &blobGasUsed
+
+			return elGenesis.ToBlock()
+		}(), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{"STRICT": "true"}
+			if tt.schedule != nil {
+				values["BLOB_SCHEDULE"] = tt.schedule
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionElectra, values)
+
+			err := checkGenesisBlobSchedule(cfg, nil, tt.block)
+			if tt.expectError && (err == nil || !strings.Contains(err.Error(), "blob schedule entry at epoch 0")) {
+				t.Fatalf("expected blob schedule error, got %v", err)
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildDiagnostics(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	elGenesis := createTestELGenesis()
+	elGenesis.Number = 1
+	elGenesis.GasLimit = 1000
+
+	builder := NewGenesisBuilder(elGenesis, cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	diagnostics := map[DiagnosticCode]Diagnostic{}
+	for _, diagnostic := range builder.(StatsProvider).Stats().Diagnostics {
+		diagnostics[diagnostic.Code] = diagnostic
+	}
+
+	for _, code := range []DiagnosticCode{DiagnosticGenesisBlockNumber, DiagnosticGasLimit} {
+		diagnostic, found := diagnostics[code]
+		if !found {
+			t.Fatalf("expected %s diagnostic, got %+v", code, builder.(StatsProvider).Stats().Diagnostics)
+		}
+
+		if diagnostic.Severity != DiagnosticSeverityWarning || diagnostic.Message == "" {
+			t.Fatalf("unexpected %s diagnostic: %+v", code, diagnostic)
+		}
+	}
+
+	if !strings.Contains(diagnostics[DiagnosticGasLimit].Message, "gas limit 1000") {
+		t.Fatalf("unexpected gas limit diagnostic message: %q", diagnostics[DiagnosticGasLimit].Message)
+	}
+
+	// in strict mode the failing check is recorded as error
+	strictCfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"STRICT": "true",
+	})
+
+	builder = NewGenesisBuilder(elGenesis, strictCfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil {
+		t.Fatalf("expected strict mode error")
+	}
+
+	errorDiagnostics := builder.(DiagnosticsProvider).Diagnostics()
+	if len(errorDiagnostics) != 1 || errorDiagnostics[0].Code != DiagnosticGenesisBlockNumber || errorDiagnostics[0].Severity != DiagnosticSeverityError {
+		t.Fatalf("expected one genesis block number error diagnostic, got %+v", errorDiagnostics)
+	}
+}
+
+func TestDepositContractDeployedCheck(t *testing.T) {
+	depositContract := common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+
+	withContract := createTestELGenesis()
+	withContract.Alloc = types.GenesisAlloc{
+		depositContract: {Code: []byte{0x60, 0x80}, Balance: big.NewInt(0)},
+	}
+
+	tests := []struct {
+		name        string
+		strict      bool
+		shadowFork  bool
+		genesis     *core.Genesis
+		validators  int
+		expectError bool
+	}{
+		{name: "contract deployed", strict: true, genesis: withContract, validators: 4},
+		{name: "warning only", strict: false, genesis: createTestELGenesis(), validators: 4},
+		{name: "strict mode", strict: true, genesis: createTestELGenesis(), validators: 4, expectError: true},
+		{name: "strict mode without validators", strict: true, genesis: createTestELGenesis()},
+		{name: "strict mode shadow fork", strict: true, shadowFork: true, genesis: createTestELGenesis(), validators: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{
+				"DEPOSIT_CONTRACT_ADDRESS": depositContract.Bytes(),
+			}
+			if tt.strict {
+				values["STRICT"] = "true"
+			}
+
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, values)
+
+			err := checkDepositContractDeployed(cfg, nil, tt.genesis, tt.validators, tt.shadowFork)
+			if tt.expectError && (err == nil || !strings.Contains(err.Error(), "no deposit contract code")) {
+				t.Fatalf("expected error for missing deposit contract, got %v", err)
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// the builders run the check in strict mode
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+		"DEPOSIT_CONTRACT_ADDRESS": depositContract.Bytes(),
+		"STRICT":                   "true",
+	})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err == nil || !strings.Contains(err.Error(), "no deposit contract code") {
+		t.Fatalf("expected build to fail without deposit contract, got %v", err)
+	}
+
+	builder = NewGenesisBuilder(withContract, cfg)
+	builder.AddValidators(createTestValidators(t, 4))
+
+	if _, err := builder.BuildState(); err != nil {
+		t.Fatalf("failed to build state with deposit contract: %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package beaconchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// stateInvariantFields holds the fork independent parts of a state that are checked by ValidateState.
+// Fields that do not exist in the state's fork are left nil / unset.
+type stateInvariantFields struct {
+	validatorCount int
+	hasAltair      bool
+	hasExecution   bool
+	hasLookahead   bool
+
+	fork                 *phase0.Fork
+	latestBlockHeader    *phase0.BeaconBlockHeader
+	eth1Data             *phase0.ETH1Data
+	checkpoints          map[string]*phase0.Checkpoint
+	justificationBits    int
+	blockRoots           int
+	stateRoots           int
+	randaoMixes          int
+	slashings            int
+	currentSyncCommittee *altair.SyncCommittee
+	nextSyncCommittee    *altair.SyncCommittee
+	executionHeaderIsNil bool
+	proposerLookahead    int
+	validatorLists       []validatorListLength
+}
+
+// ValidateState checks the structural invariants of a built genesis state: the fixed vectors have the
+// configured lengths, checkpoints, eth1 data and other containers are present and all per validator lists
+// match the validator count. All violations are aggregated into one error.
+// This is much cheaper than a state transition and catches builder bugs before the state is serialized.
+func ValidateState(cfg *beaconconfig.Config, state *spec.VersionedBeaconState) error {
+	fields, err := getStateInvariantFields(state)
+	if err != nil {
+		return err
+	}
+
+	violations := []error{}
+	addViolation := func(format string, args ...any) {
+		violations = append(violations, fmt.Errorf(format, args...))
+	}
+
+	checkLength := func(name string, length int, expected uint64) {
+		if uint64(length) != expected { //nolint:gosec // no overflow
+			addViolation("%s has %d entries, expected %d", name, length, expected)
+		}
+	}
+
+	if fields.fork == nil {
+		addViolation("fork is nil")
+	}
+
+	if fields.latestBlockHeader == nil {
+		addViolation("latest_block_header is nil")
+	}
+
+	if fields.eth1Data == nil {
+		addViolation("eth1_data is nil")
+	} else if len(fields.eth1Data.BlockHash) != 32 {
+		addViolation("eth1_data.block_hash has %d bytes, expected 32", len(fields.eth1Data.BlockHash))
+	}
+
+	for _, name := range genesisCheckpointNames {
+		if fields.checkpoints[name] == nil {
+			addViolation("%s is nil", name)
+		}
+	}
+
+	checkLength("justification_bits", fields.justificationBits, 1)
+	checkLength("block_roots", fields.blockRoots, cfg.GetUintDefault("SLOTS_PER_HISTORICAL_ROOT", 8192))
+	checkLength("state_roots", fields.stateRoots, cfg.GetUintDefault("SLOTS_PER_HISTORICAL_ROOT", 8192))
+	checkLength("randao_mixes", fields.randaoMixes, cfg.GetUintDefault("EPOCHS_PER_HISTORICAL_VECTOR", 65536))
+	checkLength("slashings", fields.slashings, cfg.GetUintDefault("EPOCHS_PER_SLASHINGS_VECTOR", 8192))
+
+	if fields.hasAltair {
+		syncCommitteeSize := cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)
+
+		checkSyncCommittee := func(name string, committee *altair.SyncCommittee) {
+			if committee == nil {
+				addViolation("%s is nil", name)
+				return
+			}
+
+			checkLength(name+".pubkeys", len(committee.Pubkeys), syncCommitteeSize)
+		}
+
+		checkSyncCommittee("current_sync_committee", fields.currentSyncCommittee)
+		checkSyncCommittee("next_sync_committee", fields.nextSyncCommittee)
+	}
+
+	if fields.hasExecution && fields.executionHeaderIsNil {
+		addViolation("latest_execution_payload_header is nil")
+	}
+
+	if fields.hasLookahead {
+		slotsPerEpoch := cfg.GetUintDefault("SLOTS_PER_EPOCH", 32)
+		minSeedLookahead := cfg.GetUintDefault("MIN_SEED_LOOKAHEAD", 1)
+
+		checkLength("proposer_lookahead", fields.proposerLookahead, (minSeedLookahead+1)*slotsPerEpoch)
+	}
+
+	if err := checkValidatorListLengths(fields.validatorCount, fields.validatorLists...); err != nil {
+		violations = append(violations, err)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("invalid %s genesis state: %d violation(s): %w", state.Version.String(), len(violations), errors.Join(violations...))
+	}
+
+	return nil
+}
+
+//nolint:gocyclo // one case per fork
+func getStateInvariantFields(state *spec.VersionedBeaconState) (*stateInvariantFields, error) {
+	fields := &stateInvariantFields{}
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		s := state.Phase0
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.validatorLists = []validatorListLength{{"balances", len(s.Balances)}}
+	case spec.DataVersionAltair:
+		s := state.Altair
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+	case spec.DataVersionBellatrix:
+		s := state.Bellatrix
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+		fields.hasExecution, fields.executionHeaderIsNil = true, s.LatestExecutionPayloadHeader == nil
+	case spec.DataVersionCapella:
+		s := state.Capella
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+		fields.hasExecution, fields.executionHeaderIsNil = true, s.LatestExecutionPayloadHeader == nil
+	case spec.DataVersionDeneb:
+		s := state.Deneb
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+		fields.hasExecution, fields.executionHeaderIsNil = true, s.LatestExecutionPayloadHeader == nil
+	case spec.DataVersionElectra:
+		s := state.Electra
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+		fields.hasExecution, fields.executionHeaderIsNil = true, s.LatestExecutionPayloadHeader == nil
+	case spec.DataVersionFulu:
+		s := state.Fulu
+		fields.setBase(len(s.Validators), s.Fork, s.LatestBlockHeader, s.ETH1Data, s.JustificationBits,
+			s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint,
+			len(s.BlockRoots), len(s.StateRoots), len(s.RANDAOMixes), len(s.Slashings))
+		fields.setAltair(s.CurrentSyncCommittee, s.NextSyncCommittee, len(s.Balances),
+			len(s.PreviousEpochParticipation), len(s.CurrentEpochParticipation), len(s.InactivityScores))
+		fields.hasExecution, fields.executionHeaderIsNil = true, s.LatestExecutionPayloadHeader == nil
+		fields.hasLookahead, fields.proposerLookahead = true, len(s.ProposerLookahead)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, state.Version)
+	}
+
+	return fields, nil
+}
+
+func (f *stateInvariantFields) setBase(validatorCount int, fork *phase0.Fork, header *phase0.BeaconBlockHeader, eth1Data *phase0.ETH1Data,
+	justificationBits []byte, previousJustified, currentJustified, finalized *phase0.Checkpoint,
+	blockRoots, stateRoots, randaoMixes, slashings int) {
+	f.validatorCount = validatorCount
+	f.fork = fork
+	f.latestBlockHeader = header
+	f.eth1Data = eth1Data
+	f.justificationBits = len(justificationBits)
+	f.checkpoints = map[string]*phase0.Checkpoint{
+		"previous_justified_checkpoint": previousJustified,
+		"current_justified_checkpoint":  currentJustified,
+		"finalized_checkpoint":          finalized,
+	}
+	f.blockRoots = blockRoots
+	f.stateRoots = stateRoots
+	f.randaoMixes = randaoMixes
+	f.slashings = slashings
+}
+
+func (f *stateInvariantFields) setAltair(current, next *altair.SyncCommittee, balances, previousParticipation, currentParticipation, inactivityScores int) {
+	f.hasAltair = true
+	f.currentSyncCommittee = current
+	f.nextSyncCommittee = next
+	f.validatorLists = []validatorListLength{
+		{"balances", balances},
+		{"previous_epoch_participation", previousParticipation},
+		{"current_epoch_participation", currentParticipation},
+		{"inactivity_scores", inactivityScores},
+	}
+}
+
+// validatingBuilder wraps a genesis builder and runs ValidateState on every built state.
+type validatingBuilder struct {
+	builderDecorator
+}
+
+// withStateValidation wraps the builder with validatingBuilder if VALIDATE_STATE is enabled.
+func withStateValidation(builder BeaconGenesisBuilder, cfg *beaconconfig.Config) BeaconGenesisBuilder {
+	if builder == nil || cfg == nil || !cfg.GetBoolDefault("VALIDATE_STATE", false) {
+		return builder
+	}
+
+	return &validatingBuilder{
+		builderDecorator: builderDecorator{BeaconGenesisBuilder: builder, clConfig: cfg},
+	}
+}
+
+func (b *validatingBuilder) BuildState() (*spec.VersionedBeaconState, error) {
+	state, err := b.BeaconGenesisBuilder.BuildState()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateState(b.clConfig, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// referenceRootBuilder wraps a genesis builder and compares the root of every built state with REFERENCE_STATE_ROOT.
+// The state root is computed with the hash tree rooter set on the builder, or its dynssz instance by default.
+type referenceRootBuilder struct {
+	builderDecorator
+	rooter HashTreeRooter
+}
+
+// withReferenceStateRoot wraps the builder with referenceRootBuilder if REFERENCE_STATE_ROOT is set.
+func withReferenceStateRoot(builder BeaconGenesisBuilder, cfg *beaconconfig.Config) BeaconGenesisBuilder {
+	if builder == nil || cfg == nil {
+		return builder
+	}
+
+	if _, found := cfg.Get("REFERENCE_STATE_ROOT"); !found {
+		return builder
+	}
+
+	return &referenceRootBuilder{
+		builderDecorator: builderDecorator{BeaconGenesisBuilder: builder, clConfig: cfg},
+	}
+}
+
+func (b *referenceRootBuilder) SetHashTreeRooter(rooter HashTreeRooter) {
+	b.rooter = rooter
+	b.builderDecorator.SetHashTreeRooter(rooter)
+}
+
+func (b *referenceRootBuilder) BuildState() (*spec.VersionedBeaconState, error) {
+	state, err := b.BeaconGenesisBuilder.BuildState()
+	if err != nil {
+		return nil, err
+	}
+
+	rooter := b.rooter
+	if rooter == nil {
+		rooter = b.DynSSZ()
+	}
+
+	if err := checkReferenceStateRoot(b.clConfig, rooter, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// checkReferenceStateRoot checks that the state root equals REFERENCE_STATE_ROOT, the root another
+// generator or client computed for the same genesis.
+func checkReferenceStateRoot(cfg *beaconconfig.Config, rooter HashTreeRooter, state *spec.VersionedBeaconState) error {
+	referenceRoot, found := cfg.GetBytes("REFERENCE_STATE_ROOT")
+	if !found || len(referenceRoot) != 32 {
+		return fmt.Errorf("REFERENCE_STATE_ROOT must be a 32 byte hex value")
+	}
+
+	stateRoot, err := computeStateRoot(rooter, state)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(referenceRoot, stateRoot[:]) {
+		return fmt.Errorf("genesis state root mismatch: computed 0x%x, reference 0x%x", stateRoot, referenceRoot)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package beaconchain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+func TestValidateState(t *testing.T) {
+	for _, version := range []spec.DataVersion{spec.DataVersionPhase0, spec.DataVersionDeneb, spec.DataVersionFulu} {
+		t.Run(version.String(), func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", version, map[string]interface{}{
+				"VALIDATE_STATE": "true",
+			})
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(createTestValidators(t, 8))
+
+			state, err := builder.BuildState()
+			if err != nil {
+				t.Fatalf("failed to build state: %v", err)
+			}
+
+			if err := ValidateState(cfg, state); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateStateViolations(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	state.Deneb.BlockRoots = state.Deneb.BlockRoots[:10]
+	state.Deneb.FinalizedCheckpoint = nil
+	state.Deneb.InactivityScores = state.Deneb.InactivityScores[:7]
+
+	err = ValidateState(cfg, state)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	for _, expected := range []string{
+		"3 violation(s)",
+		"block_roots has 10 entries, expected 64",
+		"finalized_checkpoint is nil",
+		"inactivity_scores: 7",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q, got: %v", expected, err)
+		}
+	}
+}
+
+func TestReferenceStateRoot(t *testing.T) {
+	vals := createTestValidators(t, 4)
+
+	builder := NewGenesisBuilder(createTestELGenesis(), createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{}))
+	builder.AddValidators(vals)
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	stateRoot, err := ComputeStateRoot(builder.(DynSSZProvider).DynSSZ(), state)
+	if err != nil {
+		t.Fatalf("failed to compute state root: %v", err)
+	}
+
+	mismatchingRoot := make([]byte, 32)
+	mismatchingRoot[0] = 0x01
+
+	tests := []struct {
+		name          string
+		referenceRoot []byte
+		expectError   string
+	}{
+		{name: "matching", referenceRoot: stateRoot[:]},
+		{name: "mismatching", referenceRoot: mismatchingRoot, expectError: "reference 0x01"},
+		{name: "invalid length", referenceRoot: []byte{0x01}, expectError: "32 byte"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{
+				"REFERENCE_STATE_ROOT": tt.referenceRoot,
+			})
+
+			builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+			builder.AddValidators(vals)
+
+			_, err := builder.BuildState()
+			if tt.expectError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error with matching reference root: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+			}
+
+			if tt.name == "mismatching" && !strings.Contains(err.Error(), fmt.Sprintf("computed 0x%x", stateRoot)) {
+				t.Fatalf("expected error to contain the computed root, got %v", err)
+			}
+		})
+	}
+}
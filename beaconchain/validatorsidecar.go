@@ -0,0 +1,72 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	dynssz "github.com/pk910/dynamic-ssz"
+)
+
+// GenesisValidators is the validator list of a genesis state, written as a sidecar file for tooling
+// that does not need the full state. Balances is empty unless requested.
+type GenesisValidators struct {
+	Validators []*phase0.Validator `ssz-max:"1099511627776" dynssz-max:"VALIDATOR_REGISTRY_LIMIT"`
+	Balances   []phase0.Gwei       `ssz-max:"1099511627776" dynssz-max:"VALIDATOR_REGISTRY_LIMIT"`
+}
+
+// genesisValidatorsJSON is the JSON representation of GenesisValidators, balances are encoded as
+// decimal strings like in the beacon API.
+type genesisValidatorsJSON struct {
+	Validators []*phase0.Validator `json:"validators"`
+	Balances   []string            `json:"balances,omitempty"`
+}
+
+// NewGenesisValidators collects the validators (and balances if includeBalances is set) of a genesis state.
+// The validator objects are shared with the state, not copied.
+func NewGenesisValidators(state *spec.VersionedBeaconState, includeBalances bool) (*GenesisValidators, error) {
+	vals, err := state.Validators()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state validators: %w", err)
+	}
+
+	genesisValidators := &GenesisValidators{
+		Validators: vals,
+		Balances:   []phase0.Gwei{},
+	}
+
+	if !includeBalances {
+		return genesisValidators, nil
+	}
+
+	genesisValidators.Balances, err = state.ValidatorBalances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state balances: %w", err)
+	}
+
+	return genesisValidators, nil
+}
+
+// SerializeGenesisValidators encodes the genesis validators as SSZ or JSON.
+// The SSZ encoding uses the given dynssz instance, so it follows the VALIDATOR_REGISTRY_LIMIT of the preset.
+func SerializeGenesisValidators(ds *dynssz.DynSsz, genesisValidators *GenesisValidators, contentType http.ContentType) ([]byte, error) {
+	switch contentType {
+	case http.ContentTypeSSZ:
+		return ds.MarshalSSZ(genesisValidators)
+	case http.ContentTypeJSON:
+		jsonValidators := genesisValidatorsJSON{
+			Validators: genesisValidators.Validators,
+		}
+
+		for _, balance := range genesisValidators.Balances {
+			jsonValidators.Balances = append(jsonValidators.Balances, strconv.FormatUint(uint64(balance), 10))
+		}
+
+		return json.Marshal(jsonValidators)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
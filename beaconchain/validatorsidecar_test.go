@@ -0,0 +1,86 @@
+package beaconchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
+)
+
+func TestGenesisValidatorsSidecar(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", spec.DataVersionDeneb, map[string]interface{}{})
+
+	builder := NewGenesisBuilder(createTestELGenesis(), cfg)
+	builder.AddValidators(createTestValidators(t, 8))
+
+	state, err := builder.BuildState()
+	if err != nil {
+		t.Fatalf("failed to build state: %v", err)
+	}
+
+	genesisValidators, err := NewGenesisValidators(state, true)
+	if err != nil {
+		t.Fatalf("failed to collect genesis validators: %v", err)
+	}
+
+	sszData, err := SerializeGenesisValidators(builder.(DynSSZProvider).DynSSZ(), genesisValidators, http.ContentTypeSSZ)
+	if err != nil {
+		t.Fatalf("failed to serialize genesis validators: %v", err)
+	}
+
+	decoded := &GenesisValidators{}
+	if err := builder.(DynSSZProvider).DynSSZ().UnmarshalSSZ(decoded, sszData); err != nil {
+		t.Fatalf("failed to decode genesis validators: %v", err)
+	}
+
+	if len(decoded.Balances) != len(state.Deneb.Balances) {
+		t.Fatalf("unexpected balance count: got %d, want %d", len(decoded.Balances), len(state.Deneb.Balances))
+	}
+
+	validatorsRoot, err := beaconutils.HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
+		for _, validator := range decoded.Validators {
+			if err := validator.HashTreeRootWith(hh); err != nil {
+				return err
+			}
+		}
+
+		hh.MerkleizeWithMixin(0, uint64(len(decoded.Validators)), cfg.GetUintDefault("VALIDATOR_REGISTRY_LIMIT", 1099511627776))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to hash decoded validators: %v", err)
+	}
+
+	if phase0.Root(validatorsRoot) != state.Deneb.GenesisValidatorsRoot {
+		t.Fatalf("validators root mismatch: got %x, want %x", validatorsRoot, state.Deneb.GenesisValidatorsRoot)
+	}
+
+	jsonData, err := SerializeGenesisValidators(builder.(DynSSZProvider).DynSSZ(), genesisValidators, http.ContentTypeJSON)
+	if err != nil {
+		t.Fatalf("failed to serialize genesis validators as json: %v", err)
+	}
+
+	decodedJSON := genesisValidatorsJSON{}
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("failed to decode genesis validators json: %v", err)
+	}
+
+	if len(decodedJSON.Validators) != 8 || len(decodedJSON.Balances) != 8 {
+		t.Fatalf("unexpected json sidecar sizes: %d validators, %d balances", len(decodedJSON.Validators), len(decodedJSON.Balances))
+	}
+
+	withoutBalances, err := NewGenesisValidators(state, false)
+	if err != nil {
+		t.Fatalf("failed to collect genesis validators: %v", err)
+	}
+
+	if len(withoutBalances.Balances) != 0 {
+		t.Errorf("expected no balances without includeBalances, got %d", len(withoutBalances.Balances))
+	}
+}
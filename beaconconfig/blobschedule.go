@@ -0,0 +1,98 @@
+package beaconconfig
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// BlobScheduleEntry is one entry of the BLOB_SCHEDULE config list.
+type BlobScheduleEntry struct {
+	Epoch            uint64
+	MaxBlobsPerBlock uint64
+}
+
+// GetBlobSchedule decodes the BLOB_SCHEDULE list, sorted by epoch. It returns nil without error if the config
+// has no blob schedule.
+func (c *Config) GetBlobSchedule() ([]BlobScheduleEntry, error) {
+	value, found := c.Get("BLOB_SCHEDULE")
+	if !found {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("BLOB_SCHEDULE is not a list")
+	}
+
+	schedule := make([]BlobScheduleEntry, 0, len(list))
+
+	for i, item := range list {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("BLOB_SCHEDULE entry %d is not a map", i)
+		}
+
+		epoch, err := blobScheduleUint(fields, "EPOCH")
+		if err != nil {
+			return nil, fmt.Errorf("BLOB_SCHEDULE entry %d: %w", i, err)
+		}
+
+		maxBlobs, err := blobScheduleUint(fields, "MAX_BLOBS_PER_BLOCK")
+		if err != nil {
+			return nil, fmt.Errorf("BLOB_SCHEDULE entry %d: %w", i, err)
+		}
+
+		schedule = append(schedule, BlobScheduleEntry{Epoch: epoch, MaxBlobsPerBlock: maxBlobs})
+	}
+
+	sort.SliceStable(schedule, func(i, j int) bool {
+		return schedule[i].Epoch < schedule[j].Epoch
+	})
+
+	return schedule, nil
+}
+
+// GetBlobScheduleEntryAt returns the blob schedule entry active at epoch, the last entry with an epoch
+// not after it. The bool is false if the config has no blob schedule or no entry is active yet.
+func (c *Config) GetBlobScheduleEntryAt(epoch uint64) (BlobScheduleEntry, bool, error) {
+	schedule, err := c.GetBlobSchedule()
+	if err != nil {
+		return BlobScheduleEntry{}, false, err
+	}
+
+	var (
+		active BlobScheduleEntry
+		found  bool
+	)
+
+	for _, entry := range schedule {
+		if entry.Epoch > epoch {
+			break
+		}
+
+		active, found = entry, true
+	}
+
+	return active, found, nil
+}
+
+func blobScheduleUint(fields map[string]interface{}, key string) (uint64, error) {
+	switch value := fields[key].(type) {
+	case int:
+		return uint64(value), nil //nolint:gosec // ignore overflow
+	case uint64:
+		return value, nil
+	case string:
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+
+		return parsed, nil
+	case nil:
+		return 0, fmt.Errorf("missing %s", key)
+	default:
+		return 0, fmt.Errorf("invalid %s type %T", key, value)
+	}
+}
@@ -19,17 +19,22 @@ type Config struct {
 }
 
 func LoadConfig(path string) (*Config, error) {
-	config := &Config{
-		values: make(map[string]interface{}),
-		preset: make(map[string]interface{}),
-	}
-
 	// load config from yaml
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading config file: %w", err)
 	}
 
+	return parseConfig(data)
+}
+
+// parseConfig parses a config.yaml and loads the preset referenced by PRESET_BASE.
+func parseConfig(data []byte) (*Config, error) {
+	config := &Config{
+		values: make(map[string]interface{}),
+		preset: make(map[string]interface{}),
+	}
+
 	values := make(map[string]interface{})
 	if err := yaml.Unmarshal(data, &values); err != nil {
 		return nil, fmt.Errorf("parsing yaml: %w", err)
@@ -48,8 +53,10 @@ func LoadConfig(path string) (*Config, error) {
 			}
 		case uint64:
 			config.values[key] = value
+		case bool:
+			config.values[key] = value
 		case string:
-			if strings.HasPrefix(value, "0x") {
+			if strings.HasPrefix(value, "0x") && !strings.Contains(value, ",") {
 				bytes, err := hex.DecodeString(strings.ReplaceAll(value, "0x", ""))
 				if err != nil {
 					return nil, fmt.Errorf("decoding hex: %w", err)
@@ -59,8 +66,12 @@ func LoadConfig(path string) (*Config, error) {
 			} else if val, err := strconv.ParseUint(value, 10, 64); err == nil {
 				config.values[key] = val
 			} else {
+				// comma separated lists are kept as strings and split by GetStringList
 				config.values[key] = value
 			}
+		case []interface{}:
+			// lists like BLOB_SCHEDULE are kept as parsed and decoded by their getters
+			config.values[key] = value
 		}
 	}
 
@@ -70,6 +81,18 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("preset not found")
 	}
 
+	preset, err := loadPreset(presetName)
+	if err != nil {
+		return nil, err
+	}
+
+	config.preset = preset
+
+	return config, nil
+}
+
+// loadPreset loads an embedded preset. Hex values are decoded to bytes and numeric values to uint64.
+func loadPreset(presetName string) (map[string]interface{}, error) {
 	presetData, err := presets.PresetsFS.ReadFile(presetName + ".yaml")
 	if err != nil {
 		return nil, fmt.Errorf("preset '%v' not found: %w", presetName, err)
@@ -80,6 +103,8 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse preset yaml: %w", err)
 	}
 
+	preset := make(map[string]interface{}, len(presetMap))
+
 	for key, value := range presetMap {
 		if strings.HasPrefix(value, "0x") {
 			bytes, err := hex.DecodeString(strings.ReplaceAll(value, "0x", ""))
@@ -87,15 +112,15 @@ func LoadConfig(path string) (*Config, error) {
 				return nil, fmt.Errorf("decoding hex: %w", err)
 			}
 
-			config.preset[key] = bytes
+			preset[key] = bytes
 		} else if val, err := strconv.ParseUint(value, 10, 64); err == nil {
-			config.preset[key] = val
+			preset[key] = val
 		} else {
-			config.preset[key] = value
+			preset[key] = value
 		}
 	}
 
-	return config, nil
+	return preset, nil
 }
 
 func (c *Config) Get(key string) (interface{}, bool) {
@@ -121,6 +146,43 @@ func (c *Config) GetString(key string) (string, bool) {
 	return "", false
 }
 
+// GetStringList returns the value for key as a list of strings. The value may either be a comma separated
+// string or a yaml list of strings. Entries are trimmed and empty entries are skipped.
+func (c *Config) GetStringList(key string) ([]string, bool) {
+	value, ok := c.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var entries []string
+
+	switch val := value.(type) {
+	case string:
+		entries = strings.Split(val, ",")
+	case []interface{}:
+		for _, entry := range val {
+			str, isString := entry.(string)
+			if !isString {
+				return nil, false
+			}
+
+			entries = append(entries, str)
+		}
+	default:
+		return nil, false
+	}
+
+	list := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+
+	return list, true
+}
+
 func (c *Config) GetUint(key string) (uint64, bool) {
 	value, ok := c.Get(key)
 	if !ok {
@@ -134,6 +196,8 @@ func (c *Config) GetUint(key string) (uint64, bool) {
 	return 0, false
 }
 
+// GetUintDefault returns the value for key, or defaultVal if the key is absent.
+// A key that is present with a value of 0 returns 0, not defaultVal.
 func (c *Config) GetUintDefault(key string, defaultVal uint64) uint64 {
 	value, ok := c.GetUint(key)
 	if !ok {
@@ -143,6 +207,33 @@ func (c *Config) GetUintDefault(key string, defaultVal uint64) uint64 {
 	return value
 }
 
+func (c *Config) GetBool(key string) (bool, bool) {
+	value, ok := c.Get(key)
+	if !ok {
+		return false, false
+	}
+
+	switch val := value.(type) {
+	case bool:
+		return val, true
+	case string:
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b, true
+		}
+	}
+
+	return false, false
+}
+
+func (c *Config) GetBoolDefault(key string, defaultVal bool) bool {
+	value, ok := c.GetBool(key)
+	if !ok {
+		return defaultVal
+	}
+
+	return value
+}
+
 func (c *Config) GetBytes(key string) ([]byte, bool) {
 	value, ok := c.Get(key)
 	if !ok {
@@ -169,6 +260,24 @@ func (c *Config) SetString(key string, value string) {
 	c.values[key] = value
 }
 
+func (c *Config) SetUint(key string, value uint64) {
+	c.values[key] = value
+}
+
+// Clone returns a copy of the config, so values can be set without changing the original.
+func (c *Config) Clone() *Config {
+	clone := &Config{
+		values: make(map[string]interface{}, len(c.values)),
+		preset: c.preset,
+	}
+
+	for k, v := range c.values {
+		clone.values[k] = v
+	}
+
+	return clone
+}
+
 func (c *Config) GetSpecs() map[string]interface{} {
 	specs := make(map[string]interface{})
 
@@ -182,3 +291,36 @@ func (c *Config) GetSpecs() map[string]interface{} {
 
 	return specs
 }
+
+// DiffFromPreset returns the preset keys whose value in this config differs from the embedded preset baseline
+// (e.g. "mainnet"), mapped to the value of this config. Bytes are formatted as 0x prefixed hex.
+// Returns nil if the preset does not exist.
+func (c *Config) DiffFromPreset(preset string) map[string]string {
+	baseline, err := loadPreset(preset)
+	if err != nil {
+		return nil
+	}
+
+	diff := make(map[string]string)
+
+	for key, baseValue := range baseline {
+		value, found := c.Get(key)
+		if !found {
+			continue
+		}
+
+		if formatted := formatConfigValue(value); formatted != formatConfigValue(baseValue) {
+			diff[key] = formatted
+		}
+	}
+
+	return diff
+}
+
+func formatConfigValue(value interface{}) string {
+	if bytes, ok := value.([]byte); ok {
+		return "0x" + hex.EncodeToString(bytes)
+	}
+
+	return fmt.Sprint(value)
+}
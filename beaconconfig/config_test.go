@@ -0,0 +1,78 @@
+package beaconconfig
+
+import (
+	"testing"
+)
+
+func TestDiffFromPreset(t *testing.T) {
+	cfg, err := parseConfig([]byte(`
+PRESET_BASE: mainnet
+CONFIG_NAME: tweaked
+MAX_EFFECTIVE_BALANCE: 64000000000
+SHUFFLE_ROUND_COUNT: 90
+`))
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	diff := cfg.DiffFromPreset("mainnet")
+
+	if len(diff) != 1 || diff["MAX_EFFECTIVE_BALANCE"] != "64000000000" {
+		t.Fatalf("expected only MAX_EFFECTIVE_BALANCE to differ, got %v", diff)
+	}
+
+	// a minimal preset config differs from mainnet in its preset values
+	minimalCfg, err := parseConfig([]byte("PRESET_BASE: minimal\n"))
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	if slotsPerEpoch := minimalCfg.DiffFromPreset("mainnet")["SLOTS_PER_EPOCH"]; slotsPerEpoch != "8" {
+		t.Fatalf("expected SLOTS_PER_EPOCH 8 in diff, got %q", slotsPerEpoch)
+	}
+
+	if diff := minimalCfg.DiffFromPreset("minimal"); len(diff) != 0 {
+		t.Fatalf("expected no diff against the own preset, got %v", diff)
+	}
+
+	if diff := cfg.DiffFromPreset("unknown"); diff != nil {
+		t.Fatalf("expected nil diff for unknown preset, got %v", diff)
+	}
+}
+
+func TestGetBlobSchedule(t *testing.T) {
+	cfg, err := parseConfig([]byte(`
+PRESET_BASE: mainnet
+BLOB_SCHEDULE:
+  - EPOCH: 412672
+    MAX_BLOBS_PER_BLOCK: 15
+  - EPOCH: 0
+    MAX_BLOBS_PER_BLOCK: 9
+`))
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	schedule, err := cfg.GetBlobSchedule()
+	if err != nil {
+		t.Fatalf("failed to get blob schedule: %v", err)
+	}
+
+	if len(schedule) != 2 || schedule[0].Epoch != 0 || schedule[1].MaxBlobsPerBlock != 15 {
+		t.Fatalf("unexpected blob schedule: %+v", schedule)
+	}
+
+	entry, found, err := cfg.GetBlobScheduleEntryAt(412671)
+	if err != nil || !found || entry.MaxBlobsPerBlock != 9 {
+		t.Fatalf("unexpected entry before the second schedule epoch: %+v, %v, %v", entry, found, err)
+	}
+
+	noSchedule, err := parseConfig([]byte("PRESET_BASE: mainnet\n"))
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	if _, found, err := noSchedule.GetBlobScheduleEntryAt(0); found || err != nil {
+		t.Fatalf("expected no blob schedule entry, got %v, %v", found, err)
+	}
+}
@@ -0,0 +1,16 @@
+package beaconconfig
+
+import (
+	_ "embed"
+)
+
+// defaultConfig is a mainnet preset based config with all forks up to Electra active at genesis.
+//
+//go:embed defaults/config.yaml
+var defaultConfig []byte
+
+// Default returns the embedded default consensus config. It is meant for demos and tests, not for real networks.
+// Every call returns a fresh copy, so callers may modify it.
+func Default() (*Config, error) {
+	return parseConfig(defaultConfig)
+}
@@ -0,0 +1,58 @@
+package beaconutils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// depositContractTreeDepth is the merkle tree depth of the deposit contract, which is fixed in the contract code.
+const depositContractTreeDepth = 32
+
+// Storage layout of the deposit contract: branch[32] at slots 0-31, deposit_count at slot 32 and zero_hashes[32]
+// at slots 33-64.
+const (
+	depositContractBranchSlot     = 0
+	depositContractCountSlot      = 32
+	depositContractZeroHashesSlot = 33
+)
+
+// DepositContractStorage returns the storage of the deposit contract for the execution genesis alloc, so that the
+// contract's get_deposit_root matches the deposit root of the genesis state (see ComputeDepositRoot).
+// Genesis validators are part of the state and not deposits in the contract, so the deposit tree is empty: the
+// branch and deposit count are zero and only the zero hashes the contract constructor would compute are set.
+// Zero slots are omitted. The config must use the contract's tree depth and must not override the eth1 data.
+// Unlike the originally proposed DepositContractStorage(cfg, vals), it takes no validators: the storage of the
+// empty deposit tree does not depend on the validator set, so a vals parameter would be unused.
+func DepositContractStorage(cfg *beaconconfig.Config) (map[common.Hash]common.Hash, error) {
+	if depth := cfg.GetUintDefault("DEPOSIT_CONTRACT_TREE_DEPTH", depositContractTreeDepth); depth != depositContractTreeDepth {
+		return nil, fmt.Errorf("DEPOSIT_CONTRACT_TREE_DEPTH %d does not match the deposit contract tree depth %d", depth, depositContractTreeDepth)
+	}
+
+	if maxDeposits, found := cfg.GetUint("MAX_DEPOSITS_PER_PAYLOAD"); found && maxDeposits != 1<<depositContractTreeDepth {
+		return nil, fmt.Errorf("MAX_DEPOSITS_PER_PAYLOAD %d does not match the deposit contract tree size", maxDeposits)
+	}
+
+	if _, found := cfg.Get("GENESIS_ETH1_DEPOSIT_ROOT"); found {
+		return nil, fmt.Errorf("deposit contract storage cannot be derived for an overridden GENESIS_ETH1_DEPOSIT_ROOT")
+	}
+
+	storage := make(map[common.Hash]common.Hash, depositContractTreeDepth)
+
+	// zero_hashes[0] is zero, zero_hashes[i] = hash(zero_hashes[i-1] + zero_hashes[i-1])
+	zeroHash := [32]byte{}
+	for i := 1; i < depositContractTreeDepth; i++ {
+		zeroHash = sha256.Sum256(append(zeroHash[:], zeroHash[:]...))
+		storage[depositContractSlot(depositContractZeroHashesSlot+i)] = zeroHash
+	}
+
+	return storage, nil
+}
+
+func depositContractSlot(slot int) common.Hash {
+	return common.BigToHash(big.NewInt(int64(slot)))
+}
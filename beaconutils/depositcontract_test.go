@@ -0,0 +1,64 @@
+package beaconutils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// contractDepositRoot mirrors get_deposit_root of the deposit contract, reading its state from storage.
+func contractDepositRoot(storage map[common.Hash]common.Hash) phase0.Root {
+	depositCount := storage[depositContractSlot(depositContractCountSlot)].Big().Uint64()
+
+	node := [32]byte{}
+	size := depositCount
+
+	for height := 0; height < depositContractTreeDepth; height++ {
+		if size&1 == 1 {
+			branch := storage[depositContractSlot(depositContractBranchSlot+height)]
+			node = sha256.Sum256(append(branch[:], node[:]...))
+		} else {
+			zeroHash := storage[depositContractSlot(depositContractZeroHashesSlot+height)]
+			node = sha256.Sum256(append(node[:], zeroHash[:]...))
+		}
+
+		size /= 2
+	}
+
+	countBytes := make([]byte, 32)
+	binary.LittleEndian.PutUint64(countBytes, depositCount)
+
+	return sha256.Sum256(append(node[:], countBytes...))
+}
+
+func TestDepositContractStorage(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+
+	storage, err := DepositContractStorage(cfg)
+	if err != nil {
+		t.Fatalf("failed to compute deposit contract storage: %v", err)
+	}
+
+	depositRoot, err := ComputeDepositRoot(cfg)
+	if err != nil {
+		t.Fatalf("failed to compute deposit root: %v", err)
+	}
+
+	if root := contractDepositRoot(storage); root != depositRoot {
+		t.Fatalf("contract deposit root %s does not match genesis deposit root %s", root.String(), depositRoot.String())
+	}
+
+	// zero_hashes[1] at slot 0x22, as in the deposit contract alloc of public testnets
+	if value := storage[common.HexToHash("0x22")]; value != common.HexToHash("0xf5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b") {
+		t.Fatalf("unexpected zero_hashes[1] storage value %s", value.Hex())
+	}
+
+	if _, err := DepositContractStorage(createTestConfig(t, "minimal", map[string]interface{}{
+		"DEPOSIT_CONTRACT_TREE_DEPTH": uint64(10),
+	})); err == nil {
+		t.Fatalf("expected error for a tree depth the contract does not use")
+	}
+}
@@ -1,21 +1,35 @@
 package beaconutils
 
 import (
+	"fmt"
+
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ssz "github.com/ferranbt/fastssz"
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 )
 
+// maxDepositTreeDepth is the largest deposit tree depth whose leaf count fits into an uint64.
+const maxDepositTreeDepth = 63
+
 func ComputeDepositRoot(cfg *beaconconfig.Config) (phase0.Root, error) {
 	// Compute the SSZ hash-tree-root of the empty deposit tree,
 	// since that is what we put as eth1_data.deposit_root in the CL genesis state.
-	maxDeposits := cfg.GetUintDefault("MAX_DEPOSITS_PER_PAYLOAD", 1<<cfg.GetUintDefault("DEPOSIT_CONTRACT_TREE_DEPTH", 32))
+	// The tree is padded to 2^DEPOSIT_CONTRACT_TREE_DEPTH leaves, unless MAX_DEPOSITS_PER_PAYLOAD sets the limit directly.
+	depositTreeDepth := cfg.GetUintDefault("DEPOSIT_CONTRACT_TREE_DEPTH", 32)
+	if depositTreeDepth > maxDepositTreeDepth {
+		return phase0.Root{}, fmt.Errorf("invalid DEPOSIT_CONTRACT_TREE_DEPTH %d, max is %d", depositTreeDepth, maxDepositTreeDepth)
+	}
+
+	maxDeposits := cfg.GetUintDefault("MAX_DEPOSITS_PER_PAYLOAD", 1<<depositTreeDepth)
 
-	depositRoot, _ := HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
+	depositRoot, err := HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
 		hh.MerkleizeWithMixin(0, 0, maxDeposits)
 		return nil
 	})
+	if err != nil {
+		return phase0.Root{}, fmt.Errorf("failed to compute deposit root: %w", err)
+	}
 
 	return phase0.Root(depositRoot), nil
 }
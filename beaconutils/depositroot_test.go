@@ -2,12 +2,14 @@ package beaconutils
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	"gopkg.in/yaml.v3"
 )
@@ -106,3 +108,42 @@ func TestComputeDepositRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeDepositRootDepths(t *testing.T) {
+	// empty deposit tree root: hash(zerohash[depth] + uint256(0)), zerohash[i+1] = hash(zerohash[i] + zerohash[i])
+	emptyTreeRoot := func(depth int) [32]byte {
+		zeroHash := [32]byte{}
+		for i := 0; i < depth; i++ {
+			zeroHash = sha256.Sum256(append(zeroHash[:], zeroHash[:]...))
+		}
+
+		return sha256.Sum256(append(zeroHash[:], make([]byte, 32)...))
+	}
+
+	roots := map[int]phase0.Root{}
+
+	for _, depth := range []int{32, 10} {
+		root, err := ComputeDepositRoot(createTestConfig(t, "minimal", map[string]interface{}{
+			"DEPOSIT_CONTRACT_TREE_DEPTH": uint64(depth),
+		}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if expected := emptyTreeRoot(depth); root != expected {
+			t.Errorf("root mismatch at depth %d: got %x, want %x", depth, root, expected)
+		}
+
+		roots[depth] = root
+	}
+
+	if roots[32] == roots[10] {
+		t.Errorf("expected different roots for depth 32 and 10")
+	}
+
+	if _, err := ComputeDepositRoot(createTestConfig(t, "minimal", map[string]interface{}{
+		"DEPOSIT_CONTRACT_TREE_DEPTH": uint64(64),
+	})); err == nil {
+		t.Errorf("expected error for deposit tree depth 64")
+	}
+}
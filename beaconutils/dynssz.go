@@ -1,13 +1,59 @@
 package beaconutils
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	dynssz "github.com/pk910/dynamic-ssz"
 )
 
+var (
+	// dynSszCache holds one dynssz instance per set of resolved spec values, so parallel builders share the type
+	// cache. Keying on the values instead of the config keeps the cache valid after SetUint or SetString and lets
+	// clones with the same values share an instance.
+	dynSszCache      = map[[32]byte]*dynssz.DynSsz{}
+	dynSszCacheMutex sync.Mutex
+)
+
+// GetDynSSZ returns the dynssz instance for the presets of cfg. The instance is created once per set of spec
+// values and is safe for concurrent use, dynssz guards its internal type cache itself.
 func GetDynSSZ(cfg *beaconconfig.Config) *dynssz.DynSsz {
-	spec := cfg.GetSpecs()
-	dynSsz := dynssz.NewDynSsz(spec)
+	specs := cfg.GetSpecs()
+	key := specsKey(specs)
+
+	dynSszCacheMutex.Lock()
+	defer dynSszCacheMutex.Unlock()
+
+	if dynSsz, ok := dynSszCache[key]; ok {
+		return dynSsz
+	}
+
+	dynSsz := dynssz.NewDynSsz(specs)
+	dynSszCache[key] = dynSsz
 
 	return dynSsz
 }
+
+// specsKey returns a hash of the spec values, independent of the map order.
+func specsKey(specs map[string]interface{}) [32]byte {
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%v\n", key, specs[key])
+	}
+
+	var sum [32]byte
+
+	copy(sum[:], hash.Sum(nil))
+
+	return sum
+}
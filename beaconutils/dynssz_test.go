@@ -0,0 +1,95 @@
+package beaconutils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestGetDynSSZConcurrent(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+
+	syncCommittee := &altair.SyncCommittee{
+		Pubkeys: make([]phase0.BLSPubKey, cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)),
+	}
+
+	for i := range syncCommittee.Pubkeys {
+		syncCommittee.Pubkeys[i][0] = byte(i)
+	}
+
+	expectedRoot, err := GetDynSSZ(cfg).HashTreeRoot(syncCommittee)
+	if err != nil {
+		t.Fatalf("failed to hash sync committee: %v", err)
+	}
+
+	const workers = 16
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, workers)
+	roots := make(chan [32]byte, workers)
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if GetDynSSZ(cfg) != GetDynSSZ(cfg) {
+				t.Errorf("expected the cached dynssz instance for the same config")
+			}
+
+			root, err := GetDynSSZ(cfg).HashTreeRoot(syncCommittee)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			roots <- root
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	close(roots)
+
+	for err := range errs {
+		t.Fatalf("failed to hash sync committee concurrently: %v", err)
+	}
+
+	for root := range roots {
+		if root != expectedRoot {
+			t.Fatalf("concurrent hash mismatch: got %x, want %x", root, expectedRoot)
+		}
+	}
+
+	if GetDynSSZ(cfg) != GetDynSSZ(cfg.Clone()) {
+		t.Errorf("expected the cached dynssz instance for a config with the same values")
+	}
+}
+
+func TestGetDynSSZConfigChange(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+	before := GetDynSSZ(cfg)
+
+	cfg.SetUint("SYNC_COMMITTEE_SIZE", 64)
+
+	after := GetDynSSZ(cfg)
+	if after == before {
+		t.Fatalf("expected a new dynssz instance after changing a spec value")
+	}
+
+	syncCommittee := &altair.SyncCommittee{
+		Pubkeys: make([]phase0.BLSPubKey, 64),
+	}
+
+	if _, err := after.HashTreeRoot(syncCommittee); err != nil {
+		t.Fatalf("expected the changed SYNC_COMMITTEE_SIZE to be used: %v", err)
+	}
+
+	if _, err := before.HashTreeRoot(syncCommittee); err == nil {
+		t.Fatalf("expected the original instance to keep the preset SYNC_COMMITTEE_SIZE")
+	}
+}
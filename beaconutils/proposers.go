@@ -9,11 +9,16 @@ import (
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 )
 
-// GetGenesisProposers returns the proposer indices for the first 2 epochs
+// GetGenesisProposers returns the proposer indices for the first MIN_SEED_LOOKAHEAD + 1 epochs
 func GetGenesisProposers(clConfig *beaconconfig.Config, validators []*phase0.Validator, genesisBlockHash phase0.Hash32) ([]phase0.ValidatorIndex, error) {
 	// Get configuration values
-	slotsPerEpoch := clConfig.GetUintDefault("SLOTS_PER_EPOCH", 32)
-	totalSlots := slotsPerEpoch * 2 // First 2 epochs
+	slotsPerEpoch := getSlotsPerEpoch(clConfig)
+	if slotsPerEpoch == 0 {
+		return nil, fmt.Errorf("SLOTS_PER_EPOCH must not be zero")
+	}
+
+	minSeedLookahead := clConfig.GetUintDefault("MIN_SEED_LOOKAHEAD", 1)
+	totalSlots := slotsPerEpoch * (minSeedLookahead + 1)
 
 	// Get active validator indices
 	activeIndices := []phase0.ValidatorIndex{}
@@ -38,10 +43,19 @@ func GetGenesisProposers(clConfig *beaconconfig.Config, validators []*phase0.Val
 	return proposers, nil
 }
 
+// getSlotsPerEpoch returns SLOTS_PER_EPOCH from the config.
+func getSlotsPerEpoch(clConfig *beaconconfig.Config) uint64 {
+	return clConfig.GetUintDefault("SLOTS_PER_EPOCH", 32)
+}
+
+// computeEpochAtSlot returns the epoch of the given slot
+func computeEpochAtSlot(clConfig *beaconconfig.Config, slot phase0.Slot) phase0.Epoch {
+	return phase0.Epoch(uint64(slot) / getSlotsPerEpoch(clConfig))
+}
+
 // computeProposerIndex calculates the proposer for a given slot
 func computeProposerIndex(clConfig *beaconconfig.Config, validators []*phase0.Validator, activeIndices []phase0.ValidatorIndex, slot phase0.Slot, genesisBlockHash phase0.Hash32) phase0.ValidatorIndex {
-	slotsPerEpoch := clConfig.GetUintDefault("SLOTS_PER_EPOCH", 32)
-	epoch := phase0.Epoch(uint64(slot) / slotsPerEpoch)
+	epoch := computeEpochAtSlot(clConfig, slot)
 
 	// Get domain from config
 	domainBeaconProposer := clConfig.GetBytesDefault("DOMAIN_BEACON_PROPOSER", []byte{0x00, 0x00, 0x00, 0x00})
@@ -90,6 +90,20 @@ func TestGetGenesisProposersNoActiveValidators(t *testing.T) {
 	}
 }
 
+func TestGetGenesisProposersZeroSlotsPerEpoch(t *testing.T) {
+	clConfig := createTestConfig(t, "minimal", map[string]interface{}{
+		"SLOTS_PER_EPOCH": uint64(0),
+	})
+
+	validators := []*phase0.Validator{
+		{PublicKey: phase0.BLSPubKey{0x01}, EffectiveBalance: phase0.Gwei(32_000_000_000)},
+	}
+
+	if _, err := GetGenesisProposers(clConfig, validators, phase0.Hash32{0x01}); err == nil {
+		t.Error("Expected error for SLOTS_PER_EPOCH 0, got nil")
+	}
+}
+
 func TestGetGenesisProposersMainnetExample(t *testing.T) {
 	// Create mainnet config
 	configValues := map[string]interface{}{
@@ -187,3 +201,87 @@ func TestGetGenesisProposersMainnetExample(t *testing.T) {
 		t.Errorf("Expected proposers for 2 epochs (64 slots), got %d", len(proposers))
 	}
 }
+
+func TestGetGenesisProposersCustomSlotsPerEpoch(t *testing.T) {
+	validators := make([]*phase0.Validator, 64)
+	for i := range validators {
+		validators[i] = &phase0.Validator{
+			PublicKey:        phase0.BLSPubKey{byte(i)},
+			ActivationEpoch:  0,
+			ExitEpoch:        phase0.Epoch(18446744073709551615),
+			EffectiveBalance: phase0.Gwei(32_000_000_000),
+		}
+	}
+
+	genesisBlockHash := phase0.Hash32{0x01, 0x02, 0x03}
+
+	defaultConfig := createTestConfig(t, "minimal", map[string]interface{}{
+		"SLOTS_PER_EPOCH":    uint64(32),
+		"MIN_SEED_LOOKAHEAD": uint64(1),
+	})
+	customConfig := createTestConfig(t, "minimal", map[string]interface{}{
+		"SLOTS_PER_EPOCH":    uint64(6),
+		"MIN_SEED_LOOKAHEAD": uint64(1),
+	})
+
+	// slot 12 is in epoch 0 with 32 slots per epoch, but in epoch 2 with 6 slots per epoch
+	if epoch := computeEpochAtSlot(defaultConfig, 12); epoch != 0 {
+		t.Errorf("expected epoch 0 for slot 12 with 32 slots per epoch, got %d", epoch)
+	}
+
+	if epoch := computeEpochAtSlot(customConfig, 12); epoch != 2 {
+		t.Errorf("expected epoch 2 for slot 12 with 6 slots per epoch, got %d", epoch)
+	}
+
+	// the genesis sync committee is always seeded from the genesis epoch
+	if epoch := getGenesisSyncCommitteeSeedEpoch(customConfig); epoch != 0 {
+		t.Errorf("expected sync committee seed epoch 0, got %d", epoch)
+	}
+
+	proposers, err := GetGenesisProposers(customConfig, validators, genesisBlockHash)
+	if err != nil {
+		t.Fatalf("Failed to get genesis proposers: %v", err)
+	}
+
+	if len(proposers) != 12 {
+		t.Fatalf("Expected 12 proposers for 2 epochs of 6 slots, got %d", len(proposers))
+	}
+
+	defaultProposers, err := GetGenesisProposers(defaultConfig, validators, genesisBlockHash)
+	if err != nil {
+		t.Fatalf("Failed to get genesis proposers: %v", err)
+	}
+
+	// slots 0-5 are in epoch 0 for both configs, slots 6-11 use the epoch 1 seed with the custom config
+	for slot := 0; slot < 6; slot++ {
+		if proposers[slot] != defaultProposers[slot] {
+			t.Errorf("Proposer mismatch at epoch 0 slot %d: %d vs %d", slot, proposers[slot], defaultProposers[slot])
+		}
+	}
+
+	differs := false
+
+	for slot := 6; slot < 12; slot++ {
+		if proposers[slot] != defaultProposers[slot] {
+			differs = true
+		}
+	}
+
+	if !differs {
+		t.Errorf("expected epoch 1 proposers to use a different seed than epoch 0")
+	}
+
+	lookaheadConfig := createTestConfig(t, "minimal", map[string]interface{}{
+		"SLOTS_PER_EPOCH":    uint64(6),
+		"MIN_SEED_LOOKAHEAD": uint64(2),
+	})
+
+	proposers, err = GetGenesisProposers(lookaheadConfig, validators, genesisBlockHash)
+	if err != nil {
+		t.Fatalf("Failed to get genesis proposers: %v", err)
+	}
+
+	if len(proposers) != 18 {
+		t.Errorf("Expected 18 proposers for 3 epochs of 6 slots, got %d", len(proposers))
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 )
 
+// SeedRandomMixes returns the genesis RANDAO mixes, one per EPOCHS_PER_HISTORICAL_VECTOR entry, all set to the genesis block hash.
+// The length comes from the preset (65536 mainnet, 64 minimal) unless overridden in the config.
 func SeedRandomMixes(genesisBlockHash phase0.Hash32, cfg *beaconconfig.Config) []phase0.Root {
 	epochsPerHistoricalVector := cfg.GetUintDefault("EPOCHS_PER_HISTORICAL_VECTOR", 65536)
 	randomMixes := make([]phase0.Root, epochsPerHistoricalVector)
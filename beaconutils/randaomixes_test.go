@@ -63,3 +63,37 @@ func TestSeedRandomMixes(t *testing.T) {
 		})
 	}
 }
+
+func TestSeedRandomMixesPresetLength(t *testing.T) {
+	tests := []struct {
+		preset         string
+		configValues   map[string]interface{}
+		expectedLength int
+	}{
+		{preset: "mainnet", configValues: map[string]interface{}{}, expectedLength: 65536},
+		{preset: "minimal", configValues: map[string]interface{}{}, expectedLength: 64},
+		{
+			// the mixes are sized by EPOCHS_PER_HISTORICAL_VECTOR, not SLOTS_PER_HISTORICAL_ROOT
+			preset: "minimal",
+			configValues: map[string]interface{}{
+				"SLOTS_PER_HISTORICAL_ROOT": uint64(32),
+			},
+			expectedLength: 64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			cfg := createTestConfig(t, tt.preset, tt.configValues)
+
+			if expected := cfg.GetUintDefault("EPOCHS_PER_HISTORICAL_VECTOR", 0); expected != uint64(tt.expectedLength) {
+				t.Fatalf("unexpected EPOCHS_PER_HISTORICAL_VECTOR in %s preset: %d", tt.preset, expected)
+			}
+
+			randomMixes := SeedRandomMixes(phase0.Hash32{0x01}, cfg)
+			if len(randomMixes) != tt.expectedLength {
+				t.Errorf("wrong length: got %v, want %v", len(randomMixes), tt.expectedLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package beaconutils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+const (
+	defaultHTTPTimeout    = 30 * time.Second
+	defaultHTTPRetries    = 1
+	defaultHTTPRetryDelay = 500 * time.Millisecond
+)
+
+// RemoteFetchConfig holds the timeout and retry settings shared by all remote fetches of a build.
+type RemoteFetchConfig struct {
+	// Timeout limits each attempt.
+	Timeout time.Duration
+	// Retries is the number of attempts made after the first one failed.
+	Retries uint64
+	// RetryDelay is the pause before a retry.
+	RetryDelay time.Duration
+	// Warn receives the warnings about failed attempts, they are logged if nil.
+	Warn WarnFn
+}
+
+// DefaultRemoteFetchConfig returns the remote fetch settings used if the config sets neither HTTP_TIMEOUT nor
+// HTTP_RETRIES: a 30 second timeout and a single retry.
+func DefaultRemoteFetchConfig() RemoteFetchConfig {
+	return RemoteFetchConfig{
+		Timeout:    defaultHTTPTimeout,
+		Retries:    defaultHTTPRetries,
+		RetryDelay: defaultHTTPRetryDelay,
+	}
+}
+
+// GetRemoteFetchConfig returns the remote fetch settings from HTTP_TIMEOUT (seconds, default 30) and
+// HTTP_RETRIES (default 1). A nil config returns the defaults.
+func GetRemoteFetchConfig(cfg *beaconconfig.Config) RemoteFetchConfig {
+	fetchCfg := DefaultRemoteFetchConfig()
+
+	if cfg == nil {
+		return fetchCfg
+	}
+
+	if timeout, found := cfg.GetUint("HTTP_TIMEOUT"); found {
+		fetchCfg.Timeout = time.Duration(timeout) * time.Second //nolint:gosec // no overflow
+	}
+
+	fetchCfg.Retries = cfg.GetUintDefault("HTTP_RETRIES", defaultHTTPRetries)
+
+	return fetchCfg
+}
+
+// HTTPClient returns a http client that applies the configured timeout to each request.
+func (c RemoteFetchConfig) HTTPClient() *http.Client {
+	return &http.Client{Timeout: c.Timeout}
+}
+
+// Retry calls fn until it succeeds or all retries are used up. Each attempt gets its own context
+// limited by the configured timeout. The error of the last attempt is returned.
+func (c RemoteFetchConfig) Retry(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	var err error
+
+	for attempt := uint64(0); attempt <= c.Retries; attempt++ {
+		if attempt > 0 {
+			c.Warn.warnf(WarnCodeRemoteFetch, "%s failed (attempt %d of %d): %v, retrying", name, attempt, c.Retries+1, err)
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(c.RetryDelay):
+			}
+		}
+
+		err = c.attempt(ctx, fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (c RemoteFetchConfig) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	return fn(ctx)
+}
+
+// httpStatusError is returned by FetchURL for non-200 responses.
+type httpStatusError struct {
+	status string
+	code   int
+}
+
+func (e *httpStatusError) Error() string {
+	return "unexpected status " + e.status
+}
+
+// isRetryable returns false for client errors, repeating the request would fail the same way.
+func isRetryable(err error) bool {
+	if statusErr, ok := err.(*httpStatusError); ok { //nolint:errorlint // returned unwrapped by FetchURL
+		return statusErr.code >= http.StatusInternalServerError || statusErr.code == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// FetchURL fetches url with the configured timeout and retries and returns at most maxSize bytes of the
+// response body, or the whole body if maxSize is 0. Only 200 responses are accepted.
+func FetchURL(ctx context.Context, fetchCfg RemoteFetchConfig, url string, maxSize int64) ([]byte, error) {
+	if _, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody); err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", url, err)
+	}
+
+	var body []byte
+
+	err := fetchCfg.Retry(ctx, "fetch "+url, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return err
+		}
+
+		rsp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer rsp.Body.Close()
+
+		if rsp.StatusCode != http.StatusOK {
+			return &httpStatusError{status: rsp.Status, code: rsp.StatusCode}
+		}
+
+		var reader io.Reader = rsp.Body
+		if maxSize > 0 {
+			reader = io.LimitReader(rsp.Body, maxSize)
+		}
+
+		body, err = io.ReadAll(reader)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,78 @@
+package beaconutils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchURLRetry(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := requests.Add(1)
+
+		switch r.URL.Path {
+		case "/flaky":
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			_, _ = w.Write([]byte("payload"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{})
+
+	fetchCfg := GetRemoteFetchConfig(cfg)
+	if fetchCfg.Retries != 1 || fetchCfg.Timeout != 30*time.Second {
+		t.Fatalf("unexpected default fetch config: %+v", fetchCfg)
+	}
+
+	fetchCfg.RetryDelay = 0
+
+	body, err := FetchURL(context.Background(), fetchCfg, server.URL+"/flaky", 0)
+	if err != nil {
+		t.Fatalf("expected flaky fetch to succeed on retry: %v", err)
+	}
+
+	if string(body) != "payload" || requests.Load() != 2 {
+		t.Fatalf("unexpected fetch result %q after %d requests", body, requests.Load())
+	}
+
+	// client errors are not retried
+	requests.Store(0)
+
+	if _, err := FetchURL(context.Background(), fetchCfg, server.URL+"/missing", 0); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected 404 error, got %v", err)
+	}
+
+	if requests.Load() != 1 {
+		t.Fatalf("expected 404 not to be retried, got %d requests", requests.Load())
+	}
+
+	// without retries the flaky fetch fails
+	requests.Store(0)
+
+	cfg = createTestConfig(t, "mainnet", map[string]interface{}{
+		"HTTP_RETRIES": uint64(0),
+		"HTTP_TIMEOUT": uint64(5),
+	})
+
+	fetchCfg = GetRemoteFetchConfig(cfg)
+	if fetchCfg.Retries != 0 || fetchCfg.Timeout != 5*time.Second {
+		t.Fatalf("unexpected fetch config: %+v", fetchCfg)
+	}
+
+	if _, err := FetchURL(context.Background(), fetchCfg, server.URL+"/flaky", 0); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected 503 error without retries, got %v", err)
+	}
+}
@@ -12,7 +12,25 @@ import (
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 )
 
-func GetGenesisSyncCommittee(cfg *beaconconfig.Config, validators []*phase0.Validator, randaoMix phase0.Hash32) (*altair.SyncCommittee, error) {
+// GetGenesisSyncCommittee computes the genesis sync committee. With SKIP_SYNC_COMMITTEE enabled a zeroed placeholder
+// committee of SYNC_COMMITTEE_SIZE members is returned instead, which is only meant for throwaway states (e.g. SSZ
+// size benchmarks) and must not be used for a real network. SYNC_COMMITTEE_FILE replaces the derived committee with
+// the one loaded by LoadSyncCommitteeFile, it is used for both the current and the next sync committee.
+// Warnings are passed to warn.
+func GetGenesisSyncCommittee(cfg *beaconconfig.Config, validators []*phase0.Validator, randaoMix phase0.Hash32, warn WarnFn) (*altair.SyncCommittee, error) {
+	if cfg.GetBoolDefault("SKIP_SYNC_COMMITTEE", false) {
+		warn.warnf(WarnCodeSyncCommittee, "SKIP_SYNC_COMMITTEE is set, genesis state carries a placeholder sync committee and is not usable for a real network")
+
+		return &altair.SyncCommittee{
+			Pubkeys:         make([]phase0.BLSPubKey, cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)),
+			AggregatePubkey: phase0.BLSPubKey{},
+		}, nil
+	}
+
+	if path, found := cfg.GetString("SYNC_COMMITTEE_FILE"); found && path != "" {
+		return LoadSyncCommitteeFile(cfg, path, validators)
+	}
+
 	activeIndices := make([]phase0.ValidatorIndex, 0, len(validators))
 
 	for index, validator := range validators {
@@ -67,7 +85,7 @@ func computeGenesisSyncCommitteeIndices(cfg *beaconconfig.Config, active []phase
 	maxEffectiveBalance := cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32000000000)
 	domainSyncCommittee := cfg.GetBytesDefault("DOMAIN_SYNC_COMMITTEE", []byte{0x07, 0x00, 0x00, 0x00})
 	syncCommitteeIndices := make([]phase0.ValidatorIndex, 0, syncCommitteeSize)
-	periodSeed := computeGenesisSeed(randaoMix, 0, phase0.DomainType(domainSyncCommittee))
+	periodSeed := computeGenesisSeed(randaoMix, getGenesisSyncCommitteeSeedEpoch(cfg), phase0.DomainType(domainSyncCommittee))
 
 	if len(active) == 0 {
 		return syncCommitteeIndices
@@ -116,7 +134,7 @@ func computeGenesisSyncCommitteeIndicesElectra(cfg *beaconconfig.Config, active
 	maxEffectiveBalance := cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32000000000)
 	domainSyncCommittee := cfg.GetBytesDefault("DOMAIN_SYNC_COMMITTEE", []byte{0x07, 0x00, 0x00, 0x00})
 	syncCommitteeIndices := make([]phase0.ValidatorIndex, 0, syncCommitteeSize)
-	periodSeed := computeGenesisSeed(randaoMix, 0, phase0.DomainType(domainSyncCommittee))
+	periodSeed := computeGenesisSeed(randaoMix, getGenesisSyncCommitteeSeedEpoch(cfg), phase0.DomainType(domainSyncCommittee))
 
 	if len(active) == 0 {
 		return syncCommitteeIndices
@@ -160,6 +178,12 @@ func computeGenesisSyncCommitteeIndicesElectra(cfg *beaconconfig.Config, active
 	return syncCommitteeIndices
 }
 
+// getGenesisSyncCommitteeSeedEpoch returns the epoch used to seed the genesis sync committee.
+// The genesis state is at slot 0, so this is the epoch of slot 0 for any SLOTS_PER_EPOCH.
+func getGenesisSyncCommitteeSeedEpoch(cfg *beaconconfig.Config) phase0.Epoch {
+	return computeEpochAtSlot(cfg, 0)
+}
+
 func computeGenesisSeed(mix phase0.Hash32, epoch phase0.Epoch, domainType phase0.DomainType) phase0.Root {
 	data := []byte{}
 	data = append(data, domainType[:]...)
@@ -3,8 +3,13 @@ package beaconutils
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
@@ -193,7 +198,7 @@ func TestGetGenesisSyncCommittee(t *testing.T) {
 				t.Fatalf("failed to decode randao mix: %v", err)
 			}
 
-			committee, err := GetGenesisSyncCommittee(cfg, tt.validators, phase0.Hash32(randaoMix))
+			committee, err := GetGenesisSyncCommittee(cfg, tt.validators, phase0.Hash32(randaoMix), nil)
 
 			if tt.expectedError {
 				if err == nil {
@@ -236,6 +241,47 @@ func TestGetGenesisSyncCommittee(t *testing.T) {
 	}
 }
 
+func TestGetGenesisSyncCommitteeSkip(t *testing.T) {
+	pubkeys := []string{
+		"b4702b219bcf6691b580aa96814b170713451bcfd75d2f6ebd241df7e4f6b6e30f0ec16c9098242c11c95acade4120ec",
+		"90588ecdaff043834c21035154c5820d02df74d06535bee41c330871a070a66920c22631574d46bb7e9ce5f890449d7d",
+		"a6c0b935ecd925451824d563fa5d5e2dd5c8fe2ae26fed844ee369876896f5f8e764a2cfddc2c86b6e2354249849a829",
+		"80804dcea8e0a7925083250ee74ec20e1353a9c4d564e98a5cdd9ffee3a3319100cf89b2eb3458718d2baeb6413251f5",
+	}
+
+	vals := make([]*phase0.Validator, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		vals[i] = &phase0.Validator{
+			PublicKey:             mustDecodeHexPubkey(pubkey),
+			WithdrawalCredentials: makeBytes(32, 1),
+			EffectiveBalance:      32000000000,
+		}
+	}
+
+	skipCfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"SKIP_SYNC_COMMITTEE": "true",
+	})
+
+	committee, err := GetGenesisSyncCommittee(skipCfg, vals, phase0.Hash32{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create placeholder sync committee: %v", err)
+	}
+
+	if uint64(len(committee.Pubkeys)) != skipCfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512) {
+		t.Fatalf("wrong placeholder committee size: got %d", len(committee.Pubkeys))
+	}
+
+	for i, pubkey := range committee.Pubkeys {
+		if pubkey != (phase0.BLSPubKey{}) {
+			t.Fatalf("expected zeroed placeholder pubkey at index %d", i)
+		}
+	}
+
+	if committee.AggregatePubkey != (phase0.BLSPubKey{}) {
+		t.Fatalf("expected zeroed placeholder aggregate pubkey")
+	}
+}
+
 func TestPermuteIndex(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -326,3 +372,99 @@ func mustDecodeHexPubkey(s string) phase0.BLSPubKey {
 
 	return pubkey
 }
+
+func TestLoadSyncCommitteeFile(t *testing.T) {
+	pubkeys := []string{
+		"b4702b219bcf6691b580aa96814b170713451bcfd75d2f6ebd241df7e4f6b6e30f0ec16c9098242c11c95acade4120ec",
+		"90588ecdaff043834c21035154c5820d02df74d06535bee41c330871a070a66920c22631574d46bb7e9ce5f890449d7d",
+		"a6c0b935ecd925451824d563fa5d5e2dd5c8fe2ae26fed844ee369876896f5f8e764a2cfddc2c86b6e2354249849a829",
+		"80804dcea8e0a7925083250ee74ec20e1353a9c4d564e98a5cdd9ffee3a3319100cf89b2eb3458718d2baeb6413251f5",
+	}
+
+	vals := make([]*phase0.Validator, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		vals[i] = &phase0.Validator{
+			PublicKey:             mustDecodeHexPubkey(pubkey),
+			WithdrawalCredentials: makeBytes(32, 1),
+			EffectiveBalance:      32000000000,
+		}
+	}
+
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+
+	// the supplied committee deliberately differs from the derived one
+	committee := &altair.SyncCommittee{
+		Pubkeys:         make([]phase0.BLSPubKey, cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)),
+		AggregatePubkey: mustDecodeHexPubkey(pubkeys[3]),
+	}
+	for i := range committee.Pubkeys {
+		committee.Pubkeys[i] = vals[i%2].PublicKey
+	}
+
+	dir := t.TempDir()
+
+	jsonData, err := json.Marshal(committee)
+	if err != nil {
+		t.Fatalf("failed to encode committee: %v", err)
+	}
+
+	sszData, err := GetDynSSZ(cfg).MarshalSSZ(committee)
+	if err != nil {
+		t.Fatalf("failed to encode committee: %v", err)
+	}
+
+	files := map[string][]byte{
+		"committee.json": jsonData,
+		"committee.ssz":  sszData,
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+
+		fileCfg := createTestConfig(t, "minimal", map[string]interface{}{
+			"SYNC_COMMITTEE_FILE": path,
+		})
+
+		loaded, err := GetGenesisSyncCommittee(fileCfg, vals, phase0.Hash32{}, nil)
+		if err != nil {
+			t.Fatalf("failed to load %s: %v", name, err)
+		}
+
+		if loaded.AggregatePubkey != committee.AggregatePubkey {
+			t.Fatalf("%s: aggregate pubkey mismatch", name)
+		}
+
+		for i := range committee.Pubkeys {
+			if loaded.Pubkeys[i] != committee.Pubkeys[i] {
+				t.Fatalf("%s: pubkey mismatch at index %d", name, i)
+			}
+		}
+	}
+
+	// members must be genesis validators
+	if _, err := LoadSyncCommitteeFile(cfg, filepath.Join(dir, "committee.json"), vals[1:]); err == nil ||
+		!strings.Contains(err.Error(), "sync committee member 0") {
+		t.Fatalf("expected error for non-genesis committee member, got %v", err)
+	}
+
+	// the committee size must match SYNC_COMMITTEE_SIZE
+	committee.Pubkeys = committee.Pubkeys[:4]
+
+	jsonData, err = json.Marshal(committee)
+	if err != nil {
+		t.Fatalf("failed to encode committee: %v", err)
+	}
+
+	shortPath := filepath.Join(dir, "short.json")
+	if err := os.WriteFile(shortPath, jsonData, 0o600); err != nil {
+		t.Fatalf("failed to write short committee: %v", err)
+	}
+
+	if _, err := LoadSyncCommitteeFile(cfg, shortPath, vals); err == nil ||
+		!strings.Contains(err.Error(), "has 4 members, SYNC_COMMITTEE_SIZE is 32") {
+		t.Fatalf("expected committee size error, got %v", err)
+	}
+}
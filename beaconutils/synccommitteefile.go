@@ -0,0 +1,56 @@
+package beaconutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// LoadSyncCommitteeFile loads a sync committee from path, JSON encoded if the path ends in .json and SSZ
+// encoded otherwise. The committee must have SYNC_COMMITTEE_SIZE members which are all part of the genesis
+// validator set. The aggregate pubkey is taken as is, so test vectors can carry any aggregate.
+func LoadSyncCommitteeFile(cfg *beaconconfig.Config, path string, validators []*phase0.Validator) (*altair.SyncCommittee, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	syncCommittee := &altair.SyncCommittee{}
+
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		err = json.Unmarshal(data, syncCommittee)
+	} else {
+		err = GetDynSSZ(cfg).UnmarshalSSZ(syncCommittee, data)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sync committee %s: %w", path, err)
+	}
+
+	syncCommitteeSize := cfg.GetUintDefault("SYNC_COMMITTEE_SIZE", 512)
+	if uint64(len(syncCommittee.Pubkeys)) != syncCommitteeSize {
+		return nil, fmt.Errorf("sync committee %s has %d members, SYNC_COMMITTEE_SIZE is %d", path, len(syncCommittee.Pubkeys), syncCommitteeSize)
+	}
+
+	genesisPubkeys := make(map[phase0.BLSPubKey]bool, len(validators))
+	for _, validator := range validators {
+		genesisPubkeys[validator.PublicKey] = true
+	}
+
+	for i, pubkey := range syncCommittee.Pubkeys {
+		if !genesisPubkeys[pubkey] {
+			return nil, fmt.Errorf("sync committee member %d (%s) is not a genesis validator", i, pubkey.String())
+		}
+	}
+
+	logrus.Infof("loaded genesis sync committee from %s", path)
+
+	return syncCommittee, nil
+}
@@ -2,13 +2,23 @@ package beaconutils
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha512"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	"github.com/ethpandaops/eth-beacon-genesis/validators"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/hkdf"
 )
 
 // TEEType enumerates the supported TEE vendor encodings used by the execution
@@ -28,7 +38,7 @@ const (
 
 var (
 	// hardcodedTEEQuote is a hardcoded 8192-byte string used to populate genesis
-	// headers. The quote is always set to this fixed value.
+	// headers if no quote is loaded from TEE_QUOTE_URL or TEE_QUOTE_DIR.
 	hardcodedTEEQuote = make([]byte, 8192)
 
 	// defaultTEEType identifies the placeholder TEE vendor used when no
@@ -40,11 +50,41 @@ var (
 	teeTypeField  = "ProposerTEEType"
 	teeQuoteField = "ProposerTEEQuote"
 
+	// teeDerivedQuoteInfo is the HKDF info string used for quotes derived from the validators root.
+	teeDerivedQuoteInfo = "PoTE-genesis-TEE-quote"
+
+	// minTEEQuoteEntropyBits is the entropy per byte a quote needs with REQUIRE_REAL_TEE_QUOTE. The hardcoded
+	// filler has about 3.2 bits, signed attestation reports are well above 5.
+	minTEEQuoteEntropyBits = 4.5
+
+	// teeExtraDataTag prefixes the TEE vendor hint in the execution genesis extra data (e.g. "tee=tdx").
+	teeExtraDataTag = "tee="
+
 	teeTypeLookup = map[string]TEEType{
 		"sev": defaultTEEType,
 		"tdx": TEETypeTDX,
 		"cca": TEETypeCCA,
 	}
+
+	// teeQuoteLengths holds the accepted quote length range per vendor. All quotes are
+	// zero padded to the 8192 byte header field, so that is the upper bound for all vendors.
+	teeQuoteLengths = map[TEEType]struct{ min, max int }{
+		TEETypeSEV: {min: 1184, max: 1184}, // SEV-SNP attestation report
+		TEETypeTDX: {min: 632, max: 8192},  // TDX quote: 48 byte header + 584 byte TD report + signature data
+		TEETypeCCA: {min: 1, max: 8192},    // CCA attestation token (CBOR, variable length)
+	}
+
+	// teeQuoteMagics holds the accepted leading bytes of a quote per vendor, checked with VALIDATE_TEE_QUOTE.
+	teeQuoteMagics = map[TEEType][][]byte{
+		TEETypeSEV: {{0x02, 0x00, 0x00, 0x00}, {0x03, 0x00, 0x00, 0x00}},                         // report version 2 or 3
+		TEETypeTDX: {{0x04, 0x00, 0x02, 0x00, 0x81, 0x00, 0x00, 0x00}, {0x05, 0x00, 0x02, 0x00}}, // quote v4 (ECDSA-256, TDX) or v5
+		TEETypeCCA: {{0xd9, 0x01, 0x8f}, {0xd9, 0xac, 0xca}},                                     // CBOR tag 399 or legacy tag 44234
+	}
+
+	// invalidTEEQuote is the 8192-byte quote used with TEE_QUOTE_MODE=invalid. It starts with
+	// teeInvalidQuoteMagic, which matches no vendor, so quote validation rejects it by design.
+	invalidTEEQuote      = make([]byte, 8192)
+	teeInvalidQuoteMagic = []byte{0xde, 0xad, 0xbe, 0xef}
 )
 
 func init() {
@@ -54,13 +94,18 @@ func init() {
 	repeat := (8192 + len(chunk) - 1) / len(chunk)
 	buf := bytes.Repeat(chunk, repeat)
 	copy(hardcodedTEEQuote, buf[:8192])
+
+	invalidChunk := []byte("PoTE-invalid-TEE-quote")
+	invalidBuf := bytes.Repeat(invalidChunk, (8192+len(invalidChunk)-1)/len(invalidChunk))
+	copy(invalidTEEQuote, teeInvalidQuoteMagic)
+	copy(invalidTEEQuote[len(teeInvalidQuoteMagic):], invalidBuf)
 }
 
 // ApplyDefaultTEEToHeader populates the proposer TEE fields on a beacon block
 // header if the build includes the extended metadata. Older builds that do not
-// expose these fields are left untouched.
-func ApplyDefaultTEEToHeader(header interface{}) {
-	applyTEEToHeader(header, defaultTEEType, hardcodedTEEQuote)
+// expose these fields are left untouched. Returns true if both fields were set.
+func ApplyDefaultTEEToHeader(header interface{}) bool {
+	return applyTEEToHeader(header, defaultTEEType, hardcodedTEEQuote, nil)
 }
 
 // ExtractVendorTypeFromValidators extracts the vendor type from validators.
@@ -77,91 +122,586 @@ func ExtractVendorTypeFromValidators(vals []*validators.Validator) string {
 // GetGenesisProposerTEEFields resolves the proposer TEE metadata that should be embedded in the
 // genesis block header. It prefers vendor type from validators, then from mnemonics.yml config
 // (TEE_VENDOR_FROM_MNEMONICS), then a dedicated TEE_PROPOSER_VENDOR override, and falls back to
-// the global TEE_VENDOR default. The quote is always hardcoded to an 8192-byte string.
-func GetGenesisProposerTEEFields(cfg *beaconconfig.Config, vals []*validators.Validator) (TEEType, []byte, error) {
-	const teeVendorMin = 0
-	const teeVendorMax = 2
+// the global TEE_VENDOR default. The vendor must be in TEE_ALLOWED_VENDORS if that is set.
+// The quote is fetched from TEE_QUOTE_URL or loaded from TEE_QUOTE_DIR if configured, and falls
+// back to the hardcoded quote otherwise. It is zero padded to the 8192-byte header field.
+// On errors the default vendor and the hardcoded quote are returned along with the error.
+// The quote is fetched on every call, so builders resolve the fields once per build. Warnings are passed to warn.
+func GetGenesisProposerTEEFields(cfg *beaconconfig.Config, vals []*validators.Validator, warn WarnFn) (TEEType, []byte, error) {
 	const teeQuoteSize = 8192
 
-	// Quote is always hardcoded to 8192 bytes
+	// Start from the hardcoded quote, padded to 8192 bytes
 	quoteBytes := make([]byte, teeQuoteSize)
 	copy(quoteBytes, hardcodedTEEQuote)
 
-	var proposerVendor uint64
-	var found bool
+	resolution, err := resolveProposerTEEVendor(cfg, vals)
+
+	for _, invalid := range resolution.invalid {
+		warn.warnf(WarnCodeTEE, "invalid vendor type from %s: %s (not a valid TEEType)", invalid.source, invalid.value)
+	}
+
+	if err != nil {
+		return 0, quoteBytes, err
+	}
+
+	proposerVendor := uint64(resolution.vendor)
 
-	// First, try to get vendor type from validators
-	if len(vals) > 0 {
-		vendorTypeStr := ExtractVendorTypeFromValidators(vals)
-		if vendorTypeStr != "" {
-			// Convert vendor type string to TEEType
-			if teeType, ok := TEETypeFromString(vendorTypeStr); ok {
-				proposerVendor = uint64(teeType)
-				found = true
-				logrus.Infof("using vendor type from validators: %s (TEEType: %d)", vendorTypeStr, proposerVendor)
-			} else {
-				logrus.Warnf("invalid vendor type from validators: %s (not a valid TEEType)", vendorTypeStr)
-			}
+	switch resolution.source {
+	case teeVendorSourceValidators, teeVendorSourceMnemonics:
+		logrus.Infof("using vendor type from %s: %s (TEEType: %d)", resolution.source, resolution.vendor.String(), proposerVendor)
+	case teeVendorSourceProposerVendor:
+		logrus.Infof("using vendor type from TEE_PROPOSER_VENDOR config: %d", proposerVendor)
+	default:
+		logrus.Infof("using default vendor type from TEE_VENDOR config: %d", proposerVendor)
+	}
+
+	if err := checkTEEVendorAllowed(cfg, TEEType(proposerVendor)); err != nil {
+		return 0, quoteBytes, err
+	}
+
+	vendorQuote, err := fetchVendorTEEQuote(cfg, TEEType(proposerVendor), warn)
+	if err != nil {
+		return 0, quoteBytes, err
+	}
+
+	if vendorQuote == nil {
+		vendorQuote, err = loadVendorTEEQuote(cfg, TEEType(proposerVendor), warn)
+		if err != nil {
+			return 0, quoteBytes, err
 		}
 	}
 
-	// If not found from validators, try to get vendor type from mnemonics.yml config
-	if !found {
-		if vendorTypeStr, ok := cfg.GetString("TEE_VENDOR_FROM_MNEMONICS"); ok && vendorTypeStr != "" {
-			// Convert vendor type string to TEEType
-			if teeType, ok := TEETypeFromString(vendorTypeStr); ok {
-				proposerVendor = uint64(teeType)
-				found = true
-				logrus.Infof("using vendor type from mnemonics config: %s (TEEType: %d)", vendorTypeStr, proposerVendor)
-			} else {
-				logrus.Warnf("invalid vendor type from mnemonics config: %s (not a valid TEEType)", vendorTypeStr)
-			}
+	if vendorQuote != nil {
+		quoteBytes = make([]byte, teeQuoteSize)
+		copy(quoteBytes, vendorQuote)
+	}
+
+	return TEEType(proposerVendor), quoteBytes, nil
+}
+
+const (
+	teeVendorSourceValidators     = "validators"
+	teeVendorSourceMnemonics      = "mnemonics config"
+	teeVendorSourceProposerVendor = "TEE_PROPOSER_VENDOR"
+	teeVendorSourceDefault        = "TEE_VENDOR"
+)
+
+type teeVendorCandidate struct {
+	source string
+	value  string
+}
+
+// teeVendorResolution is the outcome of the proposer TEE vendor precedence chain.
+type teeVendorResolution struct {
+	vendor  TEEType
+	source  string
+	invalid []teeVendorCandidate
+}
+
+// resolveProposerTEEVendor walks the proposer TEE vendor precedence chain of GetGenesisProposerTEEFields without
+// logging: vendor type of the validators, TEE_VENDOR_FROM_MNEMONICS, TEE_PROPOSER_VENDOR and the TEE_VENDOR default.
+// Invalid vendor names are skipped and returned in the resolution.
+func resolveProposerTEEVendor(cfg *beaconconfig.Config, vals []*validators.Validator) (*teeVendorResolution, error) {
+	const teeVendorMin = 0
+	const teeVendorMax = 2
+
+	resolution := &teeVendorResolution{}
+
+	candidates := []teeVendorCandidate{
+		{source: teeVendorSourceValidators, value: ExtractVendorTypeFromValidators(vals)},
+	}
+
+	if vendorTypeStr, ok := cfg.GetString("TEE_VENDOR_FROM_MNEMONICS"); ok {
+		candidates = append(candidates, teeVendorCandidate{source: teeVendorSourceMnemonics, value: vendorTypeStr})
+	}
+
+	for _, candidate := range candidates {
+		if candidate.value == "" {
+			continue
 		}
+
+		if teeType, ok := TEETypeFromString(candidate.value); ok {
+			resolution.vendor = teeType
+			resolution.source = candidate.source
+
+			return resolution, nil
+		}
+
+		resolution.invalid = append(resolution.invalid, candidate)
 	}
 
-	// If not found from mnemonics, try TEE_PROPOSER_VENDOR
-	if !found {
-		defaultVendor := cfg.GetUintDefault("TEE_VENDOR", uint64(teeVendorMin))
-		if defaultVendor < teeVendorMin || defaultVendor > teeVendorMax {
-			return 0, quoteBytes, fmt.Errorf("invalid TEE_VENDOR value: %d (must be between %d and %d)", defaultVendor, teeVendorMin, teeVendorMax)
+	defaultVendor := cfg.GetUintDefault("TEE_VENDOR", uint64(teeVendorMin))
+	if defaultVendor > teeVendorMax {
+		return resolution, fmt.Errorf("invalid TEE_VENDOR value: %d (must be between %d and %d)", defaultVendor, teeVendorMin, teeVendorMax)
+	}
+
+	proposerVendor := cfg.GetUintDefault("TEE_PROPOSER_VENDOR", defaultVendor)
+	if proposerVendor > teeVendorMax {
+		return resolution, fmt.Errorf("invalid TEE_PROPOSER_VENDOR value: %d (must be between %d and %d)", proposerVendor, teeVendorMin, teeVendorMax)
+	}
+
+	resolution.vendor = TEEType(proposerVendor)
+	resolution.source = teeVendorSourceDefault
+
+	if proposerVendor != defaultVendor {
+		resolution.source = teeVendorSourceProposerVendor
+	}
+
+	return resolution, nil
+}
+
+// LogTEEResolution logs the proposer TEE vendor precedence chain (vendor type of the validators, mnemonics config,
+// TEE_PROPOSER_VENDOR, TEE_VENDOR default), the resolved vendor and the quote source. It makes the silent
+// precedence in GetGenesisProposerTEEFields visible and does not fetch or load any quote.
+func LogTEEResolution(cfg *beaconconfig.Config, vals []*validators.Validator) {
+	if cfg == nil {
+		return
+	}
+
+	mnemonicsVendor, _ := cfg.GetString("TEE_VENDOR_FROM_MNEMONICS")
+	proposerVendor, hasProposerVendor := cfg.GetUint("TEE_PROPOSER_VENDOR")
+	defaultVendor := cfg.GetUintDefault("TEE_VENDOR", 0)
+
+	logrus.Infof("TEE vendor resolution:")
+	logrus.Infof("  1. validators: %s", formatTEEVendorCandidate(ExtractVendorTypeFromValidators(vals)))
+	logrus.Infof("  2. mnemonics config: %s", formatTEEVendorCandidate(mnemonicsVendor))
+
+	if hasProposerVendor {
+		logrus.Infof("  3. TEE_PROPOSER_VENDOR: %d", proposerVendor)
+	} else {
+		logrus.Infof("  3. TEE_PROPOSER_VENDOR: unset")
+	}
+
+	logrus.Infof("  4. TEE_VENDOR (default): %d", defaultVendor)
+
+	resolution, err := resolveProposerTEEVendor(cfg, vals)
+	if err != nil {
+		logrus.Infof("  resolved vendor: none (%v)", err)
+		return
+	}
+
+	logrus.Infof("  resolved vendor: %s (TEEType: %d, from %s)", resolution.vendor.String(), uint64(resolution.vendor), resolution.source)
+	logrus.Infof("  quote source: %s", teeQuoteSourceName(cfg))
+}
+
+func formatTEEVendorCandidate(value string) string {
+	if value == "" {
+		return "unset"
+	}
+
+	if _, ok := TEETypeFromString(value); !ok {
+		return value + " (invalid, skipped)"
+	}
+
+	return value
+}
+
+// teeQuoteSourceName describes where the proposer TEE quote comes from, following the precedence of
+// TEE_QUOTE_MODE, TEE_QUOTE_URL and TEE_QUOTE_DIR over the hardcoded quote.
+func teeQuoteSourceName(cfg *beaconconfig.Config) string {
+	quoteMode, _ := cfg.GetString("TEE_QUOTE_MODE")
+
+	switch strings.ToLower(quoteMode) {
+	case "derived":
+		return "derived from the genesis validators root (TEE_QUOTE_MODE=derived)"
+	case "invalid":
+		return "deliberately invalid quote (TEE_QUOTE_MODE=invalid)"
+	}
+
+	if quoteURL, _ := cfg.GetString("TEE_QUOTE_URL"); quoteURL != "" {
+		return "TEE_QUOTE_URL " + quoteURL
+	}
+
+	if quoteDir, _ := cfg.GetString("TEE_QUOTE_DIR"); quoteDir != "" {
+		return "TEE_QUOTE_DIR " + quoteDir + " (hardcoded quote if the vendor file is missing)"
+	}
+
+	return "hardcoded"
+}
+
+// RequireProposerTEEFields reports whether a failure to resolve the proposer TEE fields must fail the build.
+// That is the case if TEE_ALLOWED_VENDORS, TEE_QUOTE_URL, TEE_QUOTE_DIR or TEE_VENDOR_EXTRA_DATA_CHECK is set,
+// otherwise the genesis block header falls back to the default vendor and the hardcoded quote.
+func RequireProposerTEEFields(cfg *beaconconfig.Config) bool {
+	if cfg == nil {
+		return false
+	}
+
+	for _, key := range []string{"TEE_ALLOWED_VENDORS", "TEE_QUOTE_URL", "TEE_QUOTE_DIR"} {
+		if value, _ := cfg.GetString(key); value != "" {
+			return true
 		}
+	}
+
+	return cfg.GetBoolDefault("TEE_VENDOR_EXTRA_DATA_CHECK", false)
+}
+
+// checkTEEVendorAllowed checks teeType against TEE_ALLOWED_VENDORS, a comma separated list of vendor
+// names (e.g. "sev,tdx"). All known vendors are allowed if the key is not set.
+func checkTEEVendorAllowed(cfg *beaconconfig.Config, teeType TEEType) error {
+	allowedList, found := cfg.GetString("TEE_ALLOWED_VENDORS")
+	if !found || allowedList == "" {
+		return nil
+	}
+
+	allowed := false
 
-		proposerVendor = cfg.GetUintDefault("TEE_PROPOSER_VENDOR", defaultVendor)
-		if proposerVendor < teeVendorMin || proposerVendor > teeVendorMax {
-			return 0, quoteBytes, fmt.Errorf("invalid TEE_PROPOSER_VENDOR value: %d (must be between %d and %d)", proposerVendor, teeVendorMin, teeVendorMax)
+	for _, name := range strings.Split(allowedList, ",") {
+		allowedType, ok := TEETypeFromString(name)
+		if !ok {
+			return fmt.Errorf("invalid vendor in TEE_ALLOWED_VENDORS: %q", strings.TrimSpace(name))
 		}
-		if proposerVendor == defaultVendor {
-			logrus.Infof("using default vendor type from TEE_VENDOR config: %d", proposerVendor)
-		} else {
-			logrus.Infof("using vendor type from TEE_PROPOSER_VENDOR config: %d", proposerVendor)
+
+		if allowedType == teeType {
+			allowed = true
 		}
 	}
 
-	return TEEType(proposerVendor), quoteBytes, nil
+	if allowed {
+		return nil
+	}
+
+	return fmt.Errorf("TEE vendor %s is not allowed on this network (allowed: %s)", teeType.String(), allowedList)
+}
+
+// loadVendorTEEQuote loads the quote for teeType from TEE_QUOTE_DIR, using the "<vendor>.bin" filename
+// convention (e.g. quotes/tdx.bin). Returns nil if TEE_QUOTE_DIR is not set or has no file for the vendor,
+// in which case the hardcoded quote is used.
+func loadVendorTEEQuote(cfg *beaconconfig.Config, teeType TEEType, warn WarnFn) ([]byte, error) {
+	quoteDir, found := cfg.GetString("TEE_QUOTE_DIR")
+	if !found || quoteDir == "" {
+		return nil, nil
+	}
+
+	quotePath := filepath.Join(quoteDir, teeType.String()+".bin")
+
+	quote, err := os.ReadFile(quotePath)
+	if errors.Is(err, os.ErrNotExist) {
+		warn.warnf(WarnCodeTEE, "no TEE quote file for vendor %s in %s, using hardcoded quote", teeType.String(), quoteDir)
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read TEE quote file %s: %w", quotePath, err)
+	}
+
+	if err := checkTEEQuoteLength(teeType, quote, quotePath); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// fetchVendorTEEQuote fetches the quote for teeType from TEE_QUOTE_URL. The response body is used as quote and
+// must match the length expected for the vendor. Returns nil if TEE_QUOTE_URL is not set.
+func fetchVendorTEEQuote(cfg *beaconconfig.Config, teeType TEEType, warn WarnFn) ([]byte, error) {
+	quoteURL, found := cfg.GetString("TEE_QUOTE_URL")
+	if !found || quoteURL == "" {
+		return nil, nil
+	}
+
+	fetchCfg := GetRemoteFetchConfig(cfg)
+	fetchCfg.Warn = warn
+
+	if timeout, found := cfg.GetUint("TEE_QUOTE_URL_TIMEOUT"); found {
+		fetchCfg.Timeout = time.Duration(timeout) * time.Second //nolint:gosec // no overflow
+	}
+
+	// read one byte more than the header field can hold to detect oversized quotes
+	quote, err := FetchURL(context.Background(), fetchCfg, quoteURL, int64(len(hardcodedTEEQuote))+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch TEE quote from %s: %w", quoteURL, err)
+	}
+
+	if err := checkTEEQuoteLength(teeType, quote, quoteURL); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("fetched %d byte %s TEE quote from %s", len(quote), teeType.String(), quoteURL)
+
+	return quote, nil
+}
+
+// checkTEEQuoteLength checks the quote length against the accepted range of the vendor.
+func checkTEEQuoteLength(teeType TEEType, quote []byte, source string) error {
+	lengths, ok := teeQuoteLengths[teeType]
+	if !ok || (len(quote) >= lengths.min && len(quote) <= lengths.max) {
+		return nil
+	}
+
+	if lengths.min == lengths.max {
+		return fmt.Errorf("invalid %s quote length in %s: %d bytes, expected %d", teeType.String(), source, len(quote), lengths.min)
+	}
+
+	return fmt.Errorf("invalid %s quote length in %s: %d bytes, expected %d-%d", teeType.String(), source, len(quote), lengths.min, lengths.max)
 }
 
 // ApplyTEEToHeaderFromConfig populates the proposer TEE fields on a beacon block header
-// using configuration values and validators. Always applies TEE info with hardcoded quote and vendor type
+// using configuration values and validators. Always applies TEE info with the resolved quote and vendor type
 // from validators (if available), then from mnemonics.yml config (if available), or config. Falls back
 // to defaults if config values are not available. This should be used instead of ApplyDefaultTEEToHeader
-// when config is available.
-func ApplyTEEToHeaderFromConfig(header interface{}, cfg *beaconconfig.Config, vals []*validators.Validator) {
+// when config is available. With TEE_QUOTE_MODE=derived the quote is derived from the genesis validators root,
+// with TEE_QUOTE_MODE=invalid a quote that fails vendor validation is used for testing rejection paths.
+// Returns true if both fields were set, and passes a warning to warn if the header does not have them.
+func ApplyTEEToHeaderFromConfig(header interface{}, cfg *beaconconfig.Config, vals []*validators.Validator, validatorsRoot phase0.Root, warn WarnFn) bool {
+	applied := applyTEEToHeaderFromConfig(header, cfg, vals, validatorsRoot, warn)
+	if !applied {
+		warn.warnf(WarnCodeTEE, "TEE metadata configured, but header %T has no %s/%s fields", header, teeTypeField, teeQuoteField)
+	}
+
+	return applied
+}
+
+func applyTEEToHeaderFromConfig(header interface{}, cfg *beaconconfig.Config, vals []*validators.Validator, validatorsRoot phase0.Root, warn WarnFn) bool {
 	if cfg == nil {
 		// Fallback to defaults if no config provided
-		ApplyDefaultTEEToHeader(header)
-		return
+		return ApplyDefaultTEEToHeader(header)
 	}
 
-	teeType, teeQuote, err := GetGenesisProposerTEEFields(cfg, vals)
+	teeType, teeQuote, err := GetGenesisProposerTEEFields(cfg, vals, warn)
 	if err != nil {
-		// Log error but fallback to defaults
-		// Note: In production, you might want to return the error instead
-		ApplyDefaultTEEToHeader(header)
-		return
+		// fall back to the defaults, builders that must not do so check RequireProposerTEEFields
+		warn.warnf(WarnCodeTEE, "failed to resolve proposer TEE fields, using defaults: %v", err)
+
+		return ApplyDefaultTEEToHeader(header)
+	}
+
+	return applyProposerTEEToHeader(header, cfg, teeType, teeQuote, validatorsRoot, warn)
+}
+
+// ApplyProposerTEEToHeader populates the proposer TEE fields on a beacon block header with proposer TEE fields
+// resolved beforehand by GetGenesisProposerTEEFields, so a build resolves them once. TEE_QUOTE_MODE is applied
+// as in ApplyTEEToHeaderFromConfig. Returns true if both fields were set, and passes a warning to warn if the
+// header does not have them.
+func ApplyProposerTEEToHeader(header interface{}, cfg *beaconconfig.Config, teeType TEEType, teeQuote []byte, validatorsRoot phase0.Root, warn WarnFn) bool {
+	applied := applyProposerTEEToHeader(header, cfg, teeType, teeQuote, validatorsRoot, warn)
+	if !applied {
+		warn.warnf(WarnCodeTEE, "TEE metadata configured, but header %T has no %s/%s fields", header, teeTypeField, teeQuoteField)
+	}
+
+	return applied
+}
+
+func applyProposerTEEToHeader(header interface{}, cfg *beaconconfig.Config, teeType TEEType, teeQuote []byte, validatorsRoot phase0.Root, warn WarnFn) bool {
+	quoteMode := ""
+	if cfg != nil {
+		quoteMode, _ = cfg.GetString("TEE_QUOTE_MODE")
+	}
+
+	switch strings.ToLower(quoteMode) {
+	case "", "hardcoded":
+	case "derived":
+		teeQuote = DeriveTEEQuote(validatorsRoot)
+	case "invalid":
+		logrus.Infof("TEE_QUOTE_MODE=invalid, genesis block header carries a deliberately invalid %s quote", teeType.String())
+
+		teeQuote = invalidTEEQuote
+	default:
+		warn.warnf(WarnCodeTEE, "unknown TEE_QUOTE_MODE %q, using hardcoded quote", quoteMode)
 	}
 
 	// Always apply TEE info to header
-	applyTEEToHeader(header, teeType, teeQuote)
+	return applyTEEToHeader(header, teeType, teeQuote, warn)
+}
+
+// DeriveTEEQuote deterministically expands the genesis validators root to an 8192-byte quote using
+// HKDF-SHA512, so genesis states with different validator sets carry distinguishable quotes.
+func DeriveTEEQuote(validatorsRoot phase0.Root) []byte {
+	quote := make([]byte, len(hardcodedTEEQuote))
+
+	reader := hkdf.New(sha512.New, validatorsRoot[:], nil, []byte(teeDerivedQuoteInfo))
+	if _, err := io.ReadFull(reader, quote); err != nil {
+		// cannot happen, hkdf-sha512 can expand up to 16320 bytes
+		panic(fmt.Sprintf("failed to derive TEE quote: %v", err))
+	}
+
+	return quote
+}
+
+// ValidateTEEQuote checks that the quote starts with the magic bytes of the vendor quote format.
+// Only the format header is checked, the quote signature is not verified.
+func ValidateTEEQuote(teeType TEEType, quote []byte) error {
+	magics, ok := teeQuoteMagics[teeType]
+	if !ok {
+		return fmt.Errorf("unknown TEE type: %d", byte(teeType))
+	}
+
+	for _, magic := range magics {
+		if bytes.HasPrefix(quote, magic) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid %s quote: unexpected header 0x%x", teeType.String(), quote[:min(len(quote), 8)])
+}
+
+// ValidateTEEQuoteEntropy checks that the Shannon entropy of the quote is at least minEntropyBits bits per byte.
+// The zero padding up to the header field size is not counted. All zero quotes and the repeating hardcoded
+// filler fail this check, while real attestations are dominated by keys and signatures. Note that the
+// quote derived with TEE_QUOTE_MODE=derived is pseudo random and passes it.
+func ValidateTEEQuoteEntropy(quote []byte, minEntropyBits float64) error {
+	quote = bytes.TrimRight(quote, "\x00")
+	if len(quote) == 0 {
+		return errors.New("TEE quote is empty or all zeros")
+	}
+
+	counts := [256]int{}
+	for _, b := range quote {
+		counts[b]++
+	}
+
+	entropy := 0.0
+
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		p := float64(count) / float64(len(quote))
+		entropy -= p * math.Log2(p)
+	}
+
+	if entropy < minEntropyBits {
+		return fmt.Errorf("TEE quote entropy too low: %.2f bits per byte, expected at least %.2f", entropy, minEntropyBits)
+	}
+
+	return nil
+}
+
+// ValidateHeaderTEEQuote validates the proposer TEE quote set on a beacon block header. With VALIDATE_TEE_QUOTE
+// the quote format is checked with ValidateTEEQuote, the hardcoded and derived placeholder quotes do not pass it.
+// With REQUIRE_REAL_TEE_QUOTE the quote entropy is checked with ValidateTEEQuoteEntropy, which rejects the
+// hardcoded filler. Real quotes have to be provided via TEE_QUOTE_DIR or TEE_QUOTE_URL for both checks.
+func ValidateHeaderTEEQuote(cfg *beaconconfig.Config, header interface{}) error {
+	if cfg == nil {
+		return nil
+	}
+
+	validateFormat := cfg.GetBoolDefault("VALIDATE_TEE_QUOTE", false)
+	requireReal := cfg.GetBoolDefault("REQUIRE_REAL_TEE_QUOTE", false)
+
+	if !validateFormat && !requireReal {
+		return nil
+	}
+
+	teeType, quote, found := readTEEFromHeader(header)
+	if !found {
+		return fmt.Errorf("TEE quote validation enabled, but header %T has no %s/%s fields", header, teeTypeField, teeQuoteField)
+	}
+
+	if validateFormat {
+		if err := ValidateTEEQuote(teeType, quote); err != nil {
+			return err
+		}
+	}
+
+	if requireReal {
+		if err := ValidateTEEQuoteEntropy(quote, minTEEQuoteEntropyBits); err != nil {
+			return fmt.Errorf("REQUIRE_REAL_TEE_QUOTE: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readTEEFromHeader reads the proposer TEE fields from header via reflection.
+// Returns false if the header does not have both fields.
+func readTEEFromHeader(header interface{}) (TEEType, []byte, bool) {
+	v := reflect.ValueOf(header)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return 0, nil, false
+	}
+
+	typeField := v.Elem().FieldByName(teeTypeField)
+	quoteField := v.Elem().FieldByName(teeQuoteField)
+
+	if !typeField.IsValid() || !quoteField.IsValid() || !typeField.CanUint() {
+		return 0, nil, false
+	}
+
+	switch quoteField.Kind() {
+	case reflect.Array, reflect.Slice:
+		if quoteField.Type().Elem().Kind() != reflect.Uint8 {
+			return 0, nil, false
+		}
+	default:
+		return 0, nil, false
+	}
+
+	quote := make([]byte, quoteField.Len())
+	for i := range quote {
+		quote[i] = byte(quoteField.Index(i).Uint())
+	}
+
+	return TEEType(typeField.Uint()), quote, true //nolint:gosec // tee type fits into a byte
+}
+
+// ApplyTEETypeToHeader sets the proposer TEE vendor on a beacon block header together with the hardcoded quote.
+// Returns true if both fields were set.
+func ApplyTEETypeToHeader(header interface{}, teeType TEEType) bool {
+	return applyTEEToHeader(header, teeType, hardcodedTEEQuote, nil)
+}
+
+// OverrideHeaderTEEFields replaces the proposer TEE fields of a beacon block header. A nil teeType or teeQuote
+// keeps the current value of that field, so changing only the vendor does not replace the quote. A new quote must
+// be within the accepted length range of the resulting vendor. Returns an error if the header has no TEE fields.
+func OverrideHeaderTEEFields(header interface{}, teeType *TEEType, teeQuote []byte) error {
+	currentType, currentQuote, found := readTEEFromHeader(header)
+	if !found {
+		return fmt.Errorf("header %T has no %s/%s fields", header, teeTypeField, teeQuoteField)
+	}
+
+	if teeType != nil {
+		if _, err := teeType.ToSpec(); err != nil {
+			return err
+		}
+
+		currentType = *teeType
+	}
+
+	if teeQuote != nil {
+		if err := checkTEEQuoteLength(currentType, teeQuote, "override"); err != nil {
+			return err
+		}
+
+		currentQuote = teeQuote
+	}
+
+	if !applyTEEToHeader(header, currentType, currentQuote, nil) {
+		return fmt.Errorf("failed to set the %s/%s fields of header %T", teeTypeField, teeQuoteField, header)
+	}
+
+	return nil
+}
+
+// String returns the lower case vendor identifier of the TEE type (e.g. "tdx").
+func (t TEEType) String() string {
+	for name, teeType := range teeTypeLookup {
+		if teeType == t {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("unknown(%d)", byte(t))
+}
+
+// ToSpec converts the TEE type to the ProposerTEEType value of the go-eth2-client beacon block header.
+// Returns an error for unknown vendors.
+func (t TEEType) ToSpec() (uint8, error) {
+	if _, known := teeQuoteLengths[t]; !known {
+		return 0, fmt.Errorf("unknown TEE type: %d", byte(t))
+	}
+
+	return uint8(t), nil
+}
+
+// TEETypeFromSpec converts a ProposerTEEType value of the go-eth2-client beacon block header to the TEE type.
+// Returns an error for unknown vendors.
+func TEETypeFromSpec(value uint8) (TEEType, error) {
+	teeType := TEEType(value)
+	if _, known := teeQuoteLengths[teeType]; !known {
+		return 0, fmt.Errorf("unknown proposer TEE type: %d", value)
+	}
+
+	return teeType, nil
 }
 
 // TEETypeFromString converts a human-readable vendor identifier (case
@@ -171,55 +711,116 @@ func TEETypeFromString(name string) (TEEType, bool) {
 	return teeType, found
 }
 
-func applyTEEToHeader(header interface{}, teeType TEEType, teeQuote []byte) {
+// TEETypeFromExtraData parses the TEE vendor hint encoded in the execution-layer genesis extra data.
+// The hint is expected in the form "tee=<vendor>" (case insensitive) and may be surrounded by other
+// text, e.g. "pote-devnet tee=sev". Returns false if no valid hint is present.
+func TEETypeFromExtraData(extra []byte) (TEEType, bool) {
+	lowerExtra := strings.ToLower(string(extra))
+
+	tagIdx := strings.Index(lowerExtra, teeExtraDataTag)
+	if tagIdx < 0 {
+		return 0, false
+	}
+
+	vendor := lowerExtra[tagIdx+len(teeExtraDataTag):]
+	if endIdx := strings.IndexFunc(vendor, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}); endIdx >= 0 {
+		vendor = vendor[:endIdx]
+	}
+
+	return TEETypeFromString(vendor)
+}
+
+// ValidateTEEVendorExtraData cross-checks the resolved proposer TEE vendor against the vendor hint
+// in the execution-layer genesis extra data. The check is only performed when
+// TEE_VENDOR_EXTRA_DATA_CHECK is enabled in the config.
+func ValidateTEEVendorExtraData(cfg *beaconconfig.Config, teeType TEEType, extra []byte) error {
+	if cfg == nil || !cfg.GetBoolDefault("TEE_VENDOR_EXTRA_DATA_CHECK", false) {
+		return nil
+	}
+
+	extraTEEType, found := TEETypeFromExtraData(extra)
+	if !found {
+		return fmt.Errorf("TEE vendor check enabled, but execution genesis extra data has no valid %q tag: 0x%x", teeExtraDataTag, extra)
+	}
+
+	if teeType != extraTEEType {
+		return fmt.Errorf("TEE vendor mismatch: resolved proposer vendor %d, execution genesis extra data vendor %d", teeType, extraTEEType)
+	}
+
+	return nil
+}
+
+// applyTEEToHeader sets the proposer TEE fields on header via reflection.
+// Returns true if both fields were found and set.
+func applyTEEToHeader(header interface{}, teeType TEEType, teeQuote []byte, warn WarnFn) bool {
 	if header == nil {
-		return
+		return false
 	}
 
 	v := reflect.ValueOf(header)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return
+		return false
 	}
 
 	elem := v.Elem()
-	if !elem.IsValid() {
-		return
+	if !elem.IsValid() || elem.Kind() != reflect.Struct {
+		return false
 	}
 
-	applyTEEType(elem.FieldByName(teeTypeField), teeType)
-	applyTEEQuote(elem.FieldByName(teeQuoteField), teeQuote)
+	typeApplied := applyTEEType(elem.FieldByName(teeTypeField), teeType, warn)
+	quoteApplied := applyTEEQuote(elem.FieldByName(teeQuoteField), teeQuote)
+
+	return typeApplied && quoteApplied
 }
 
-func applyTEEType(field reflect.Value, teeType TEEType) {
+func applyTEEType(field reflect.Value, teeType TEEType, warn WarnFn) bool {
 	if !field.IsValid() || !field.CanSet() {
-		return
+		return false
+	}
+
+	specValue, err := teeType.ToSpec()
+	if err != nil {
+		warn.warnf(WarnCodeTEE, "not applying proposer TEE type: %v", err)
+		return false
 	}
 
-	value := uint64(teeType)
+	value := uint64(specValue)
 
 	switch field.Kind() {
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
 		field.SetUint(value)
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
 		field.SetInt(int64(value))
+	default:
+		return false
 	}
+
+	return true
 }
 
-func applyTEEQuote(field reflect.Value, teeQuote []byte) {
+func applyTEEQuote(field reflect.Value, teeQuote []byte) bool {
 	if !field.IsValid() || !field.CanSet() {
-		return
+		return false
 	}
 
 	switch field.Kind() {
 	case reflect.Array:
 		writeArray(field, teeQuote)
 	case reflect.Slice:
-		if field.Type().Elem().Kind() == reflect.Uint8 {
-			tmp := make([]byte, len(teeQuote))
-			copy(tmp, teeQuote)
-			field.SetBytes(tmp)
+		if field.Type().Elem().Kind() != reflect.Uint8 {
+			return false
 		}
+
+		tmp := make([]byte, len(teeQuote))
+		copy(tmp, teeQuote)
+		field.SetBytes(tmp)
+	default:
+		return false
 	}
+
+	return true
 }
 
 func writeArray(field reflect.Value, teeQuote []byte) {
@@ -1,6 +1,20 @@
 package beaconutils
 
-import "testing"
+import (
+	"bytes"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
 
 type (
 	testHeader struct {
@@ -48,13 +62,48 @@ func TestApplyDefaultTEEToHeader_NoFields(t *testing.T) {
 	}
 
 	header := &minimalHeader{}
-	ApplyDefaultTEEToHeader(header)
+	if ApplyDefaultTEEToHeader(header) {
+		t.Fatalf("expected no TEE fields to be applied")
+	}
 	// ensure there is no panic and header untouched.
 	if header.Slot != 0 {
 		t.Fatalf("unexpected mutation of unrelated fields")
 	}
 }
 
+func TestApplyTEEToHeaderFromConfig_Applied(t *testing.T) {
+	type typeOnlyHeader struct {
+		ProposerTEEType uint8
+	}
+
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"TEE_VENDOR": uint64(TEETypeTDX),
+	})
+
+	header := &testHeader{}
+	if !ApplyTEEToHeaderFromConfig(header, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected TEE fields to be applied")
+	}
+
+	if header.ProposerTEEType != uint8(TEETypeTDX) {
+		t.Fatalf("unexpected tee type: got %d want %d", header.ProposerTEEType, TEETypeTDX)
+	}
+
+	// headers with only one of the fields are not reported as applied
+	partialHeader := &typeOnlyHeader{}
+	if ApplyTEEToHeaderFromConfig(partialHeader, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected partial header to not report TEE fields as applied")
+	}
+
+	if ApplyTEEToHeaderFromConfig(&struct{ Slot uint64 }{}, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected header without TEE fields to not report TEE fields as applied")
+	}
+
+	if ApplyTEEToHeaderFromConfig(nil, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected nil header to not report TEE fields as applied")
+	}
+}
+
 func TestTEETypeFromString(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -90,3 +139,428 @@ func TestTEETypeFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestTEETypeFromExtraData(t *testing.T) {
+	tests := []struct {
+		name     string
+		extra    []byte
+		expected TEEType
+		found    bool
+	}{
+		{name: "plain tag", extra: []byte("tee=tdx"), expected: TEETypeTDX, found: true},
+		{name: "tag with surrounding text", extra: []byte("pote-devnet TEE=CCA/v1"), expected: TEETypeCCA, found: true},
+		{name: "no tag", extra: []byte("pote-devnet"), found: false},
+		{name: "unknown vendor", extra: []byte("tee=sgx"), found: false},
+		{name: "empty", extra: nil, found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := TEETypeFromExtraData(tt.extra)
+			if found != tt.found {
+				t.Fatalf("unexpected found flag: got %v want %v", found, tt.found)
+			}
+
+			if found && got != tt.expected {
+				t.Fatalf("unexpected tee type: got %d want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateTEEVendorExtraData(t *testing.T) {
+	tests := []struct {
+		name      string
+		check     string
+		extra     []byte
+		shouldErr bool
+	}{
+		{name: "check disabled", check: "false", extra: []byte("tee=sev")},
+		{name: "matching vendor", check: "true", extra: []byte("pote tee=tdx")},
+		{name: "mismatching vendor", check: "true", extra: []byte("pote tee=sev"), shouldErr: true},
+		{name: "missing vendor tag", check: "true", extra: []byte("pote"), shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", map[string]interface{}{
+				"TEE_VENDOR_EXTRA_DATA_CHECK": tt.check,
+			})
+
+			err := ValidateTEEVendorExtraData(cfg, TEETypeTDX, tt.extra)
+			if tt.shouldErr && err == nil {
+				t.Fatalf("expected error for extra data %q", tt.extra)
+			}
+
+			if !tt.shouldErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetGenesisProposerTEEFields_QuoteDir(t *testing.T) {
+	quoteDir := t.TempDir()
+
+	sevQuote := bytes.Repeat([]byte{0x5e}, 1184)
+	tdxQuote := bytes.Repeat([]byte{0x7d}, 1000)
+
+	if err := os.WriteFile(filepath.Join(quoteDir, "sev.bin"), sevQuote, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write quote file: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(quoteDir, "tdx.bin"), tdxQuote, 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write quote file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		vendor        uint64
+		expectedQuote []byte
+	}{
+		{name: "sev quote file", vendor: uint64(TEETypeSEV), expectedQuote: sevQuote},
+		{name: "tdx quote file", vendor: uint64(TEETypeTDX), expectedQuote: tdxQuote},
+		{name: "missing cca quote file", vendor: uint64(TEETypeCCA), expectedQuote: hardcodedTEEQuote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+				"TEE_QUOTE_DIR": quoteDir,
+				"TEE_VENDOR":    tt.vendor,
+			})
+
+			teeType, quote, err := GetGenesisProposerTEEFields(cfg, nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if uint64(teeType) != tt.vendor {
+				t.Fatalf("unexpected tee type: got %d want %d", teeType, tt.vendor)
+			}
+
+			if len(quote) != 8192 {
+				t.Fatalf("unexpected quote length: got %d want 8192", len(quote))
+			}
+
+			if !bytes.Equal(quote[:len(tt.expectedQuote)], tt.expectedQuote) {
+				t.Fatalf("unexpected quote content")
+			}
+
+			if !bytes.Equal(quote[len(tt.expectedQuote):], make([]byte, 8192-len(tt.expectedQuote))) {
+				t.Fatalf("expected quote to be zero padded")
+			}
+		})
+	}
+
+	if err := os.WriteFile(filepath.Join(quoteDir, "sev.bin"), sevQuote[:100], 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write quote file: %v", err)
+	}
+
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"TEE_QUOTE_DIR": quoteDir,
+	})
+
+	if _, _, err := GetGenesisProposerTEEFields(cfg, nil, nil); err == nil {
+		t.Fatalf("expected error for invalid sev quote length")
+	}
+}
+
+func TestDeriveTEEQuote(t *testing.T) {
+	rootA := phase0.Root{0x01}
+	rootB := phase0.Root{0x02}
+
+	quoteA := DeriveTEEQuote(rootA)
+
+	if len(quoteA) != 8192 {
+		t.Fatalf("unexpected quote length: got %d want 8192", len(quoteA))
+	}
+
+	if !bytes.Equal(quoteA, DeriveTEEQuote(rootA)) {
+		t.Fatalf("expected derived quote to be deterministic")
+	}
+
+	if bytes.Equal(quoteA, DeriveTEEQuote(rootB)) {
+		t.Fatalf("expected derived quote to change with the validators root")
+	}
+
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"TEE_QUOTE_MODE": "derived",
+	})
+
+	header := &testHeader{}
+	ApplyTEEToHeaderFromConfig(header, cfg, nil, rootA, nil)
+
+	if !bytes.Equal(header.ProposerTEEQuote[:], quoteA) {
+		t.Fatalf("expected header to carry the derived quote")
+	}
+
+	cfg = createTestConfig(t, "mainnet", map[string]interface{}{})
+
+	header = &testHeader{}
+	ApplyTEEToHeaderFromConfig(header, cfg, nil, rootA, nil)
+
+	if !bytes.Equal(header.ProposerTEEQuote[:], hardcodedTEEQuote) {
+		t.Fatalf("expected header to carry the hardcoded quote by default")
+	}
+}
+
+func TestGetGenesisProposerTEEFields_AllowedVendors(t *testing.T) {
+	tests := []struct {
+		name        string
+		vendor      uint64
+		allowed     string
+		expectError bool
+	}{
+		{name: "unset allows all", vendor: uint64(TEETypeCCA), allowed: "", expectError: false},
+		{name: "allowed vendor", vendor: uint64(TEETypeTDX), allowed: "sev, tdx", expectError: false},
+		{name: "disallowed vendor", vendor: uint64(TEETypeCCA), allowed: "sev,tdx", expectError: true},
+		{name: "invalid allowlist", vendor: uint64(TEETypeSEV), allowed: "sev,foo", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{
+				"TEE_VENDOR": tt.vendor,
+			}
+			if tt.allowed != "" {
+				values["TEE_ALLOWED_VENDORS"] = tt.allowed
+			}
+
+			cfg := createTestConfig(t, "mainnet", values)
+
+			_, _, err := GetGenesisProposerTEEFields(cfg, nil, nil)
+			if tt.expectError && err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetGenesisProposerTEEFields_QuoteURL(t *testing.T) {
+	sevQuote := bytes.Repeat([]byte{0x5e}, 1184)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/quote":
+			_, _ = w.Write(sevQuote)
+		case "/short":
+			_, _ = w.Write(sevQuote[:100])
+		case "/slow":
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		expectedErr string
+	}{
+		{name: "valid quote", path: "/quote"},
+		{name: "wrong length", path: "/short", expectedErr: "invalid sev quote length"},
+		{name: "non-200 status", path: "/missing", expectedErr: "unexpected status 404"},
+		{name: "timeout", path: "/slow", expectedErr: "deadline exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+				"TEE_QUOTE_URL":         server.URL + tt.path,
+				"TEE_QUOTE_URL_TIMEOUT": uint64(1),
+				"TEE_VENDOR":            uint64(TEETypeSEV),
+			})
+
+			_, quote, err := GetGenesisProposerTEEFields(cfg, nil, nil)
+
+			if !RequireProposerTEEFields(cfg) {
+				t.Fatalf("expected TEE_QUOTE_URL to require the proposer TEE fields")
+			}
+
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(quote) != len(hardcodedTEEQuote) {
+				t.Fatalf("unexpected quote length: got %d want %d", len(quote), len(hardcodedTEEQuote))
+			}
+
+			if !bytes.Equal(quote[:len(sevQuote)], sevQuote) || quote[len(sevQuote)] != 0 {
+				t.Fatalf("quote was not loaded from url")
+			}
+		})
+	}
+}
+
+func TestTEETypeSpecConversion(t *testing.T) {
+	for _, teeType := range []TEEType{TEETypeSEV, TEETypeTDX, TEETypeCCA} {
+		specValue, err := teeType.ToSpec()
+		if err != nil {
+			t.Fatalf("unexpected error converting %s to spec: %v", teeType, err)
+		}
+
+		converted, err := TEETypeFromSpec(specValue)
+		if err != nil {
+			t.Fatalf("unexpected error converting %d from spec: %v", specValue, err)
+		}
+
+		if converted != teeType {
+			t.Fatalf("round trip mismatch: got %s, want %s", converted, teeType)
+		}
+
+		// the reflection based applier writes the spec value
+		header := &testHeader{}
+		if !ApplyTEETypeToHeader(header, teeType) || header.ProposerTEEType != specValue {
+			t.Fatalf("applier wrote %d for %s, want %d", header.ProposerTEEType, teeType, specValue)
+		}
+	}
+
+	if _, err := TEEType(3).ToSpec(); err == nil {
+		t.Fatalf("expected error for unknown TEE type")
+	}
+
+	if _, err := TEETypeFromSpec(255); err == nil {
+		t.Fatalf("expected error for unknown spec value")
+	}
+
+	header := &testHeader{}
+	if ApplyTEETypeToHeader(header, TEEType(3)) {
+		t.Fatalf("expected unknown TEE type to not be applied")
+	}
+}
+
+func TestTEEQuoteModeInvalid(t *testing.T) {
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"TEE_VENDOR":         uint64(TEETypeTDX),
+		"TEE_QUOTE_MODE":     "invalid",
+		"VALIDATE_TEE_QUOTE": "true",
+	})
+
+	header := &testHeader{}
+	if !ApplyTEEToHeaderFromConfig(header, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected TEE fields to be applied")
+	}
+
+	if !bytes.Equal(header.ProposerTEEQuote[:], invalidTEEQuote) {
+		t.Fatalf("expected header to carry the invalid quote")
+	}
+
+	if !bytes.HasPrefix(header.ProposerTEEQuote[:], teeInvalidQuoteMagic) {
+		t.Fatalf("expected invalid quote to start with 0x%x, got 0x%x", teeInvalidQuoteMagic, header.ProposerTEEQuote[:4])
+	}
+
+	if err := ValidateHeaderTEEQuote(cfg, header); err == nil {
+		t.Fatalf("expected validation to reject the invalid quote")
+	}
+
+	validQuote := append([]byte{0x04, 0x00, 0x02, 0x00, 0x81, 0x00, 0x00, 0x00}, make([]byte, 624)...)
+	if !applyTEEToHeader(header, TEETypeTDX, validQuote, nil) {
+		t.Fatalf("expected TEE fields to be applied")
+	}
+
+	if err := ValidateHeaderTEEQuote(cfg, header); err != nil {
+		t.Fatalf("unexpected error for a quote with tdx header: %v", err)
+	}
+
+	disabledCfg := createTestConfig(t, "mainnet", map[string]interface{}{})
+	if err := ValidateHeaderTEEQuote(disabledCfg, &testHeader{}); err != nil {
+		t.Fatalf("unexpected error with VALIDATE_TEE_QUOTE disabled: %v", err)
+	}
+}
+
+func TestValidateTEEQuoteEntropy(t *testing.T) {
+	if err := ValidateTEEQuoteEntropy(hardcodedTEEQuote, minTEEQuoteEntropyBits); err == nil {
+		t.Fatalf("expected the hardcoded filler quote to fail the entropy check")
+	}
+
+	if err := ValidateTEEQuoteEntropy(make([]byte, 8192), minTEEQuoteEntropyBits); err == nil {
+		t.Fatalf("expected an all zero quote to fail the entropy check")
+	}
+
+	randomQuote := make([]byte, 1184)
+	if _, err := rand.Read(randomQuote); err != nil {
+		t.Fatalf("failed to generate random quote: %v", err)
+	}
+
+	// zero padded to the header field size, like a real SEV report
+	paddedQuote := append(randomQuote, make([]byte, 8192-len(randomQuote))...)
+	if err := ValidateTEEQuoteEntropy(paddedQuote, minTEEQuoteEntropyBits); err != nil {
+		t.Fatalf("unexpected error for a random quote: %v", err)
+	}
+
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"REQUIRE_REAL_TEE_QUOTE": "true",
+	})
+
+	header := &testHeader{}
+	if !ApplyTEEToHeaderFromConfig(header, cfg, nil, phase0.Root{}, nil) {
+		t.Fatalf("expected TEE fields to be applied")
+	}
+
+	if err := ValidateHeaderTEEQuote(cfg, header); err == nil {
+		t.Fatalf("expected REQUIRE_REAL_TEE_QUOTE to reject the hardcoded quote")
+	}
+
+	if !applyTEEToHeader(header, TEETypeSEV, paddedQuote, nil) {
+		t.Fatalf("expected TEE fields to be applied")
+	}
+
+	if err := ValidateHeaderTEEQuote(cfg, header); err != nil {
+		t.Fatalf("unexpected error for a random quote: %v", err)
+	}
+}
+
+func TestLogTEEResolution(t *testing.T) {
+	var logBuffer bytes.Buffer
+
+	logrus.SetOutput(&logBuffer)
+
+	defer logrus.SetOutput(os.Stderr)
+
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"TEE_VENDOR_FROM_MNEMONICS": "tdx",
+		"TEE_PROPOSER_VENDOR":       uint64(TEETypeSEV),
+		"TEE_VENDOR":                uint64(TEETypeSEV),
+		"TEE_QUOTE_DIR":             "/quotes",
+	})
+
+	LogTEEResolution(cfg, []*validators.Validator{{}})
+
+	output := logBuffer.String()
+
+	for _, expected := range []string{
+		"1. validators: unset",
+		"2. mnemonics config: tdx",
+		"3. TEE_PROPOSER_VENDOR: 0",
+		"4. TEE_VENDOR (default): 0",
+		"resolved vendor: tdx (TEEType: 1, from mnemonics config)",
+		"quote source: TEE_QUOTE_DIR /quotes",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Fatalf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	teeType, _, err := GetGenesisProposerTEEFields(cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to resolve TEE fields: %v", err)
+	}
+
+	if teeType != TEETypeTDX {
+		t.Fatalf("logged resolution differs from GetGenesisProposerTEEFields: got %s", teeType.String())
+	}
+}
@@ -0,0 +1,139 @@
+package beaconutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+const (
+	// validatorsCacheVersion is part of the cache key and must be bumped when the validator conversion changes.
+	validatorsCacheVersion = "v4"
+	validatorSSZSize       = 121
+)
+
+// validatorsCacheConfigKeys are the config values the validator conversion depends on.
+var validatorsCacheConfigKeys = []string{
+	"MAX_EFFECTIVE_BALANCE",
+	"MAX_EFFECTIVE_BALANCE_ELECTRA",
+	"EFFECTIVE_BALANCE_INCREMENT",
+	"ELECTRA_FORK_EPOCH",
+	"FAR_FUTURE_EPOCH",
+	"VALIDATOR_REGISTRY_LIMIT",
+	"VALIDATORS_ROOT_SALT",
+}
+
+// getValidatorsCacheKey returns a hash of all inputs of the genesis validator conversion.
+func getValidatorsCacheKey(cfg *beaconconfig.Config, vals []*validators.Validator) (string, error) {
+	hasher := sha256.New()
+	hasher.Write([]byte(validatorsCacheVersion))
+
+	uintBuf := make([]byte, 8)
+
+	for _, key := range validatorsCacheConfigKeys {
+		value, found := cfg.GetUint(key)
+
+		hasher.Write([]byte(key))
+
+		if found {
+			binary.LittleEndian.PutUint64(uintBuf, value)
+			hasher.Write(uintBuf)
+		}
+	}
+
+	// the fingerprint root covers pubkeys, withdrawal credentials, balances and the validator order
+	fingerprintRoot, err := validators.FingerprintRoot(vals)
+	if err != nil {
+		return "", err
+	}
+
+	hasher.Write(fingerprintRoot[:])
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// loadCachedGenesisValidators loads a cache entry. The entry consists of the validators root followed by
+// the SSZ encoded validator records.
+func loadCachedGenesisValidators(cacheDir, cacheKey string, warn WarnFn) ([]*phase0.Validator, phase0.Root, bool) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, cacheKey+".ssz"))
+	if err != nil {
+		return nil, phase0.Root{}, false
+	}
+
+	if len(data) < 32 || (len(data)-32)%validatorSSZSize != 0 {
+		warn.warnf(WarnCodeValidatorsCache, "ignoring corrupted validators cache entry %s", cacheKey)
+		return nil, phase0.Root{}, false
+	}
+
+	var validatorsRoot phase0.Root
+
+	copy(validatorsRoot[:], data[:32])
+	data = data[32:]
+
+	clValidators := make([]*phase0.Validator, 0, len(data)/validatorSSZSize)
+
+	for offset := 0; offset < len(data); offset += validatorSSZSize {
+		validator := &phase0.Validator{}
+		if err := validator.UnmarshalSSZ(data[offset : offset+validatorSSZSize]); err != nil {
+			warn.warnf(WarnCodeValidatorsCache, "ignoring corrupted validators cache entry %s: %v", cacheKey, err)
+			return nil, phase0.Root{}, false
+		}
+
+		clValidators = append(clValidators, validator)
+	}
+
+	logrus.Infof("loaded %d genesis validators from cache", len(clValidators))
+
+	return clValidators, validatorsRoot, true
+}
+
+// storeCachedGenesisValidators writes a cache entry. Failures are passed to warn, as the cache is optional.
+func storeCachedGenesisValidators(cacheDir, cacheKey string, clValidators []*phase0.Validator, validatorsRoot phase0.Root, warn WarnFn) {
+	buf := bytes.NewBuffer(make([]byte, 0, 32+len(clValidators)*validatorSSZSize))
+	buf.Write(validatorsRoot[:])
+
+	for _, validator := range clValidators {
+		data, err := validator.MarshalSSZ()
+		if err != nil {
+			warn.warnf(WarnCodeValidatorsCache, "failed to encode validator for cache: %v", err)
+			return
+		}
+
+		buf.Write(data)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		warn.warnf(WarnCodeValidatorsCache, "failed to create validators cache dir: %v", err)
+		return
+	}
+
+	// write to a temporary file first, so concurrent runs never read a partial entry
+	tmpFile, err := os.CreateTemp(cacheDir, cacheKey+".tmp*")
+	if err != nil {
+		warn.warnf(WarnCodeValidatorsCache, "failed to write validators cache: %v", err)
+		return
+	}
+
+	_, err = tmpFile.Write(buf.Bytes())
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), filepath.Join(cacheDir, cacheKey+".ssz"))
+	}
+
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		warn.warnf(WarnCodeValidatorsCache, "failed to write validators cache: %v", err)
+	}
+}
@@ -1,17 +1,114 @@
 package beaconutils
 
 import (
+	"fmt"
+	"math/bits"
+
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	ssz "github.com/ferranbt/fastssz"
+	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 	"github.com/ethpandaops/eth-beacon-genesis/validators"
 )
 
+// ProgressFn receives progress updates of long running operations.
+type ProgressFn func(done, total uint64)
+
 func GetGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator) ([]*phase0.Validator, phase0.Root) {
+	return GetGenesisValidatorsWithProgress(cfg, vals, nil)
+}
+
+// GetGenesisValidatorsWithProgress works like GetGenesisValidators and reports the number of processed validators
+// to progress. The callback is throttled to about 100 invocations and is always called once on completion.
+// If VALIDATORS_CACHE_DIR is set, the result is cached on disk and reused by later runs with the same inputs.
+// Errors are logged and returned as nil validators, use GetGenesisValidatorsWithOptions to get them.
+func GetGenesisValidatorsWithProgress(cfg *beaconconfig.Config, vals []*validators.Validator, progress ProgressFn) ([]*phase0.Validator, phase0.Root) {
+	clValidators, validatorsRoot, err := GetGenesisValidatorsWithOptions(cfg, vals, GenesisValidatorsOptions{
+		Progress: progress,
+	})
+	if err != nil {
+		logrus.Errorf("failed to compute genesis validators: %v", err)
+		return nil, phase0.Root{}
+	}
+
+	return clValidators, validatorsRoot
+}
+
+// GenesisValidatorsOptions holds the optional callbacks of GetGenesisValidatorsWithOptions.
+type GenesisValidatorsOptions struct {
+	// Progress receives the number of processed validators, see GetGenesisValidatorsWithProgress.
+	Progress ProgressFn
+	// Warn receives the warnings of the conversion and the validators cache, they are logged if nil.
+	Warn WarnFn
+}
+
+// GetGenesisValidatorsWithOptions converts the validators to genesis validator records and computes the validators
+// root like GetGenesisValidatorsWithProgress, but returns an error if the validators cannot be converted.
+func GetGenesisValidatorsWithOptions(cfg *beaconconfig.Config, vals []*validators.Validator, opts GenesisValidatorsOptions) ([]*phase0.Validator, phase0.Root, error) {
+	if salt, ok := cfg.GetUint("VALIDATORS_ROOT_SALT"); ok && salt != 0 {
+		opts.Warn.warnf(WarnCodeValidatorsRootSalt, "VALIDATORS_ROOT_SALT is set, genesis validator set is salted for test vectors and not meant for production")
+	}
+
+	cacheDir, _ := cfg.GetString("VALIDATORS_CACHE_DIR")
+	if cacheDir == "" {
+		return computeGenesisValidators(cfg, vals, opts.Progress)
+	}
+
+	cacheKey, err := getValidatorsCacheKey(cfg, vals)
+	if err != nil {
+		opts.Warn.warnf(WarnCodeValidatorsCache, "failed to compute validators cache key, skipping cache: %v", err)
+		return computeGenesisValidators(cfg, vals, opts.Progress)
+	}
+
+	if clValidators, validatorsRoot, found := loadCachedGenesisValidators(cacheDir, cacheKey, opts.Warn); found {
+		if opts.Progress != nil {
+			opts.Progress(uint64(len(vals)), uint64(len(vals)))
+		}
+
+		return clValidators, validatorsRoot, nil
+	}
+
+	clValidators, validatorsRoot, err := computeGenesisValidators(cfg, vals, opts.Progress)
+	if err != nil {
+		return nil, phase0.Root{}, err
+	}
+
+	storeCachedGenesisValidators(cacheDir, cacheKey, clValidators, validatorsRoot, opts.Warn)
+
+	return clValidators, validatorsRoot, nil
+}
+
+func computeGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator, progress ProgressFn) ([]*phase0.Validator, phase0.Root, error) {
+	clValidators, err := buildGenesisValidators(cfg, vals, progress)
+	if err != nil {
+		return nil, phase0.Root{}, err
+	}
+
+	if salt, ok := cfg.GetUint("VALIDATORS_ROOT_SALT"); ok && salt != 0 {
+		if err := applyValidatorsRootSalt(cfg, clValidators, salt); err != nil {
+			return nil, phase0.Root{}, err
+		}
+	}
+
+	validatorsRoot, err := computeValidatorsRoot(cfg, clValidators)
+	if err != nil {
+		return nil, phase0.Root{}, fmt.Errorf("failed to compute validators root: %w", err)
+	}
+
+	if progress != nil {
+		progress(uint64(len(vals)), uint64(len(vals)))
+	}
+
+	return clValidators, validatorsRoot, nil
+}
+
+// buildGenesisValidators converts the validators to genesis validator records.
+func buildGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator, progress ProgressFn) ([]*phase0.Validator, error) {
 	// Process activations
 	maxEffectiveBalance := phase0.Gwei(cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32_000_000_000))
 	maxEffectiveBalanceElectra := phase0.Gwei(cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE_ELECTRA", 2_048_000_000_000))
+	effectiveBalanceIncrement := phase0.Gwei(cfg.GetUintDefault("EFFECTIVE_BALANCE_INCREMENT", 1_000_000_000))
 	isElectraActive := false
 
 	if electraActivationEpoch, ok := cfg.GetUint("ELECTRA_FORK_EPOCH"); ok && electraActivationEpoch == 0 {
@@ -19,21 +116,34 @@ func GetGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator
 	}
 
 	clValidators := make([]*phase0.Validator, 0, len(vals))
+	total := uint64(len(vals))
+	progressInterval := max((total+99)/100, 1)
 
 	for i := 0; i < len(vals); i++ {
 		val := vals[i]
 
+		if progress != nil && i > 0 && uint64(i)%progressInterval == 0 {
+			progress(uint64(i), total)
+		}
+
 		if val == nil {
-			return nil, phase0.Root{}
+			return nil, fmt.Errorf("genesis validator %d is nil", i)
 		}
 
 		effectiveBalance := phase0.Gwei(0)
-		if val.Balance != nil {
+		if val.EffectiveBalance != nil {
+			effectiveBalance = phase0.Gwei(*val.EffectiveBalance)
+		} else if val.Balance != nil {
 			effectiveBalance = phase0.Gwei(*val.Balance)
 		} else {
 			effectiveBalance = maxEffectiveBalance
 		}
 
+		// effective balances are rounded down to a multiple of EFFECTIVE_BALANCE_INCREMENT
+		if effectiveBalanceIncrement > 0 {
+			effectiveBalance -= effectiveBalance % effectiveBalanceIncrement
+		}
+
 		if isElectraActive && val.WithdrawalCredentials[0] == 0x02 {
 			// allow electra validators with 0x02 withdrawal credentials to have a higher max effective balance
 			if effectiveBalance > maxEffectiveBalanceElectra {
@@ -63,8 +173,14 @@ func GetGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator
 		clValidators = append(clValidators, validator)
 	}
 
+	return clValidators, nil
+}
+
+// computeValidatorsRoot returns the hash tree root of the validator registry list.
+func computeValidatorsRoot(cfg *beaconconfig.Config, clValidators []*phase0.Validator) (phase0.Root, error) {
 	maxValidators := cfg.GetUintDefault("VALIDATOR_REGISTRY_LIMIT", 1099511627776)
-	validatorsRoot, err := HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
+
+	return HashWithFastSSZHasher(func(hh *ssz.Hasher) error {
 		for _, elem := range clValidators {
 			if err := elem.HashTreeRootWith(hh); err != nil {
 				return err
@@ -75,14 +191,10 @@ func GetGenesisValidators(cfg *beaconconfig.Config, vals []*validators.Validator
 
 		return nil
 	})
-
-	if err != nil {
-		return nil, phase0.Root{}
-	}
-
-	return clValidators, validatorsRoot
 }
 
+// GetGenesisBalances returns the genesis balance of each validator. Balances are taken per validator
+// without arithmetic, use SumGwei to total them.
 func GetGenesisBalances(cfg *beaconconfig.Config, vals []*validators.Validator) []phase0.Gwei {
 	maxEffectiveBalance := phase0.Gwei(cfg.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32_000_000_000))
 	balances := make([]phase0.Gwei, len(vals))
@@ -90,6 +202,8 @@ func GetGenesisBalances(cfg *beaconconfig.Config, vals []*validators.Validator)
 	for i, validator := range vals {
 		if validator.Balance != nil {
 			balances[i] = phase0.Gwei(*validator.Balance)
+		} else if validator.EffectiveBalance != nil {
+			balances[i] = phase0.Gwei(*validator.EffectiveBalance)
 		} else {
 			balances[i] = maxEffectiveBalance
 		}
@@ -97,3 +211,20 @@ func GetGenesisBalances(cfg *beaconconfig.Config, vals []*validators.Validator)
 
 	return balances
 }
+
+// SumGwei returns the sum of the given gwei amounts. An error is returned if the sum overflows uint64,
+// which a large set of compounding validators near the max effective balance can get close to.
+func SumGwei(values []phase0.Gwei) (phase0.Gwei, error) {
+	total := uint64(0)
+
+	for i, value := range values {
+		sum, carry := bits.Add64(total, uint64(value), 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("gwei sum overflows uint64 at entry %d", i)
+		}
+
+		total = sum
+	}
+
+	return phase0.Gwei(total), nil
+}
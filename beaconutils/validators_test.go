@@ -3,6 +3,8 @@ package beaconutils
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -217,3 +219,291 @@ func TestGetGenesisBalances(t *testing.T) {
 func ptr(v uint64) *uint64 {
 	return &v
 }
+
+func TestSumGweiOverflow(t *testing.T) {
+	cfg := createTestConfig(t, "mainnet", map[string]interface{}{})
+
+	// three compounding validators at half the uint64 range: a naive uint64 sum wraps around
+	halfRange := uint64(math.MaxUint64/2 + 1)
+	vals := make([]*validators.Validator, 3)
+
+	for i := range vals {
+		vals[i] = &validators.Validator{
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, byte(i+1))),
+			WithdrawalCredentials: append([]byte{0x02}, makeBytes(31, byte(i+1))...),
+			Balance:               &halfRange,
+		}
+	}
+
+	balances := GetGenesisBalances(cfg, vals)
+
+	naiveSum := uint64(0)
+	for _, balance := range balances {
+		naiveSum += uint64(balance)
+	}
+
+	if naiveSum >= halfRange*2-1 {
+		t.Fatalf("expected the naive sum to wrap around, got %d", naiveSum)
+	}
+
+	if _, err := SumGwei(balances); err == nil {
+		t.Fatalf("expected overflow error")
+	}
+
+	total, err := SumGwei(balances[:1])
+	if err != nil || uint64(total) != halfRange {
+		t.Fatalf("unexpected sum of a single balance: %d, %v", total, err)
+	}
+
+	total, err = SumGwei([]phase0.Gwei{math.MaxUint64 - 1, 1})
+	if err != nil || uint64(total) != math.MaxUint64 {
+		t.Fatalf("expected sum up to the uint64 limit to succeed: %d, %v", total, err)
+	}
+}
+
+func TestGetGenesisValidatorsWithProgress(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+
+	vals := make([]*validators.Validator, 250)
+	for i := range vals {
+		vals[i] = &validators.Validator{
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, byte(i))),
+			WithdrawalCredentials: makeBytes(32, 0),
+		}
+	}
+
+	calls := []uint64{}
+
+	_, validatorsRoot := GetGenesisValidatorsWithProgress(cfg, vals, func(done, total uint64) {
+		if total != uint64(len(vals)) {
+			t.Fatalf("unexpected total: got %d, want %d", total, len(vals))
+		}
+
+		calls = append(calls, done)
+	})
+
+	if len(calls) < 2 {
+		t.Fatalf("expected multiple progress updates, got %d", len(calls))
+	}
+
+	if len(calls) > 101 {
+		t.Fatalf("expected progress updates to be throttled, got %d", len(calls))
+	}
+
+	for i := 1; i < len(calls); i++ {
+		if calls[i] <= calls[i-1] {
+			t.Fatalf("expected increasing progress counts, got %v", calls)
+		}
+	}
+
+	if calls[len(calls)-1] != uint64(len(vals)) {
+		t.Fatalf("expected final progress update to report completion, got %d", calls[len(calls)-1])
+	}
+
+	_, expectedRoot := GetGenesisValidators(cfg, vals)
+	if validatorsRoot != expectedRoot {
+		t.Fatalf("validators root differs with progress callback")
+	}
+}
+
+func TestGetGenesisValidatorsCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"VALIDATORS_CACHE_DIR": cacheDir,
+	})
+
+	vals := make([]*validators.Validator, 16)
+	for i := range vals {
+		vals[i] = &validators.Validator{
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, byte(i))),
+			WithdrawalCredentials: makeBytes(32, 0),
+		}
+	}
+
+	cacheKey, err := getValidatorsCacheKey(cfg, vals)
+	if err != nil {
+		t.Fatalf("failed to compute cache key: %v", err)
+	}
+
+	if _, _, found := loadCachedGenesisValidators(cacheDir, cacheKey, nil); found {
+		t.Fatalf("expected empty cache")
+	}
+
+	firstValidators, firstRoot := GetGenesisValidators(cfg, vals)
+
+	cachedValidators, cachedRoot, found := loadCachedGenesisValidators(cacheDir, cacheKey, nil)
+	if !found {
+		t.Fatalf("expected cache entry after first build")
+	}
+
+	if cachedRoot != firstRoot || len(cachedValidators) != len(firstValidators) {
+		t.Fatalf("cache entry does not match the computed validators")
+	}
+
+	secondValidators, secondRoot := GetGenesisValidators(cfg, vals)
+	if secondRoot != firstRoot {
+		t.Fatalf("validators root mismatch on cache hit: got %x, want %x", secondRoot, firstRoot)
+	}
+
+	for i := range firstValidators {
+		if !reflect.DeepEqual(secondValidators[i], firstValidators[i]) {
+			t.Fatalf("validator %d mismatch on cache hit", i)
+		}
+	}
+
+	balance := uint64(16_000_000_000)
+	vals[0].Balance = &balance
+
+	if changedKey, _ := getValidatorsCacheKey(cfg, vals); changedKey == cacheKey {
+		t.Fatalf("expected cache key to change with the validator inputs")
+	}
+}
+
+func TestGetGenesisValidatorsEffectiveBalanceOverride(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"MAX_EFFECTIVE_BALANCE":         uint64(32_000_000_000),
+		"MAX_EFFECTIVE_BALANCE_ELECTRA": uint64(2_048_000_000_000),
+		"ELECTRA_FORK_EPOCH":            uint64(0),
+	})
+
+	compoundingCreds := makeBytes(32, 0)
+	compoundingCreds[0] = 0x02
+	executionCreds := makeBytes(32, 0)
+	executionCreds[0] = 0x01
+
+	vals := []*validators.Validator{
+		{
+			// 2048 ETH compounding validator
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 1)),
+			WithdrawalCredentials: compoundingCreds,
+			EffectiveBalance:      ptr(2_048_000_000_000),
+		},
+		{
+			// above the electra cap
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 2)),
+			WithdrawalCredentials: compoundingCreds,
+			EffectiveBalance:      ptr(4_096_000_000_000),
+		},
+		{
+			// 0x01 credentials are capped to MAX_EFFECTIVE_BALANCE
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 3)),
+			WithdrawalCredentials: executionCreds,
+			EffectiveBalance:      ptr(2_048_000_000_000),
+		},
+		{
+			// the override takes precedence over the balance
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 4)),
+			WithdrawalCredentials: compoundingCreds,
+			Balance:               ptr(2_050_000_000_000),
+			EffectiveBalance:      ptr(1_024_000_000_000),
+		},
+	}
+
+	clValidators, _ := GetGenesisValidators(cfg, vals)
+	balances := GetGenesisBalances(cfg, vals)
+
+	expectedEffective := []phase0.Gwei{2_048_000_000_000, 2_048_000_000_000, 32_000_000_000, 1_024_000_000_000}
+	expectedBalances := []phase0.Gwei{2_048_000_000_000, 4_096_000_000_000, 2_048_000_000_000, 2_050_000_000_000}
+
+	for i, val := range clValidators {
+		if val.EffectiveBalance != expectedEffective[i] {
+			t.Errorf("effective balance mismatch at index %d: got %d, want %d", i, val.EffectiveBalance, expectedEffective[i])
+		}
+
+		if balances[i] != expectedBalances[i] {
+			t.Errorf("balance mismatch at index %d: got %d, want %d", i, balances[i], expectedBalances[i])
+		}
+
+		if val.ActivationEpoch != 0 {
+			t.Errorf("expected validator %d to be active at genesis", i)
+		}
+	}
+}
+
+func TestGetGenesisValidatorsPartialDeposit(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"MAX_EFFECTIVE_BALANCE":       uint64(32_000_000_000),
+		"EFFECTIVE_BALANCE_INCREMENT": uint64(1_000_000_000),
+	})
+
+	vals := []*validators.Validator{
+		{
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 1)),
+			WithdrawalCredentials: makeBytes(32, 0),
+			Balance:               ptr(16_000_000_000),
+		},
+		{
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 2)),
+			WithdrawalCredentials: makeBytes(32, 0),
+			Balance:               ptr(16_500_000_000),
+		},
+		{
+			// default deposit amount
+			PublicKey:             phase0.BLSPubKey(makeBytes(48, 3)),
+			WithdrawalCredentials: makeBytes(32, 0),
+		},
+	}
+
+	clValidators, _ := GetGenesisValidators(cfg, vals)
+	balances := GetGenesisBalances(cfg, vals)
+
+	expectedEffective := []phase0.Gwei{16_000_000_000, 16_000_000_000, 32_000_000_000}
+	expectedBalances := []phase0.Gwei{16_000_000_000, 16_500_000_000, 32_000_000_000}
+	expectedActive := []bool{false, false, true}
+
+	for i, val := range clValidators {
+		if val.EffectiveBalance != expectedEffective[i] {
+			t.Errorf("effective balance mismatch at index %d: got %d, want %d", i, val.EffectiveBalance, expectedEffective[i])
+		}
+
+		if balances[i] != expectedBalances[i] {
+			t.Errorf("balance mismatch at index %d: got %d, want %d", i, balances[i], expectedBalances[i])
+		}
+
+		if (val.ActivationEpoch == 0) != expectedActive[i] {
+			t.Errorf("activation mismatch at index %d: activation epoch %d", i, val.ActivationEpoch)
+		}
+	}
+}
+
+func TestFindValidatorsRootSalt(t *testing.T) {
+	vals := []*validators.Validator{
+		{PublicKey: phase0.BLSPubKey(makeBytes(48, 1)), WithdrawalCredentials: makeBytes(32, 0)},
+		{PublicKey: phase0.BLSPubKey(makeBytes(48, 2)), WithdrawalCredentials: makeBytes(32, 0)},
+	}
+
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{})
+	_, unsaltedRoot := GetGenesisValidators(cfg, vals)
+
+	prefix := []byte{0xab}
+
+	salt, saltedRoot, err := FindValidatorsRootSalt(cfg, vals, prefix, 1<<16)
+	if err != nil {
+		t.Fatalf("failed to find salt: %v", err)
+	}
+
+	if !bytes.HasPrefix(saltedRoot[:], prefix) {
+		t.Fatalf("salted root %x does not start with %x", saltedRoot, prefix)
+	}
+
+	if salt == 0 && saltedRoot != unsaltedRoot {
+		t.Fatalf("salt 0 must not change the validators root")
+	}
+
+	saltedCfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"VALIDATORS_ROOT_SALT": salt,
+	})
+
+	clValidators, validatorsRoot := GetGenesisValidators(saltedCfg, vals)
+	if validatorsRoot != saltedRoot {
+		t.Fatalf("VALIDATORS_ROOT_SALT %d does not reproduce the root: got %x, want %x", salt, validatorsRoot, saltedRoot)
+	}
+
+	if clValidators[0].ActivationEligibilityEpoch != phase0.Epoch(salt) || clValidators[0].ActivationEpoch != 0 {
+		t.Fatalf("unexpected salted validator epochs: %d, %d", clValidators[0].ActivationEligibilityEpoch, clValidators[0].ActivationEpoch)
+	}
+
+	if _, _, err := FindValidatorsRootSalt(cfg, vals, []byte{0xab, 0xcd, 0xef}, 16); err == nil {
+		t.Fatalf("expected no salt for a 3-byte prefix in a tiny range")
+	}
+}
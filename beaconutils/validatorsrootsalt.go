@@ -0,0 +1,65 @@
+package beaconutils
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// The genesis validators root commits to every field of every validator, so there is no field that can be
+// salted without being visible in the state. VALIDATORS_ROOT_SALT is written to the activation eligibility
+// epoch of the first validator that is active at genesis: the spec only reads that epoch for validators that
+// are not yet active, so the salt changes the root without changing the behaviour of the chain.
+// A salt of 0 is the regular genesis value and leaves the validators untouched.
+
+// applyValidatorsRootSalt writes salt to the activation eligibility epoch of the first active validator.
+// Returns an error if the salt is out of range or no validator is active at genesis.
+func applyValidatorsRootSalt(cfg *beaconconfig.Config, clValidators []*phase0.Validator, salt uint64) error {
+	if salt >= cfg.GetUintDefault("FAR_FUTURE_EPOCH", 18446744073709551615) {
+		return fmt.Errorf("VALIDATORS_ROOT_SALT %d must be lower than FAR_FUTURE_EPOCH", salt)
+	}
+
+	for _, validator := range clValidators {
+		if validator.ActivationEpoch == 0 {
+			validator.ActivationEligibilityEpoch = phase0.Epoch(salt)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("VALIDATORS_ROOT_SALT is set, but no validator is active at genesis")
+}
+
+// FindValidatorsRootSalt searches the salts 0 to maxSalt for one that yields a genesis validators root
+// starting with prefix. The salt can be set as VALIDATORS_ROOT_SALT to reproduce the root. Each additional
+// prefix byte multiplies the expected search time by 256, so only short prefixes are practical.
+func FindValidatorsRootSalt(cfg *beaconconfig.Config, vals []*validators.Validator, prefix []byte, maxSalt uint64) (uint64, phase0.Root, error) {
+	clValidators, err := buildGenesisValidators(cfg, vals, nil)
+	if err != nil {
+		return 0, phase0.Root{}, fmt.Errorf("failed to compute genesis validators: %w", err)
+	}
+
+	for salt := uint64(0); salt <= maxSalt; salt++ {
+		if err := applyValidatorsRootSalt(cfg, clValidators, salt); err != nil {
+			return 0, phase0.Root{}, fmt.Errorf("cannot salt genesis validators with salt %d: %w", salt, err)
+		}
+
+		validatorsRoot, err := computeValidatorsRoot(cfg, clValidators)
+		if err != nil {
+			return 0, phase0.Root{}, fmt.Errorf("failed to compute validators root: %w", err)
+		}
+
+		if bytes.HasPrefix(validatorsRoot[:], prefix) {
+			return salt, validatorsRoot, nil
+		}
+
+		if salt == maxSalt {
+			break
+		}
+	}
+
+	return 0, phase0.Root{}, fmt.Errorf("no salt up to %d yields a validators root with prefix 0x%x", maxSalt, prefix)
+}
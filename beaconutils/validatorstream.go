@@ -0,0 +1,205 @@
+package beaconutils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+// GenesisValidatorStream converts genesis validators batch by batch. Each batch is turned into validator records,
+// balances and leaves of the validators root as it is added, so the input validators do not have to be kept until
+// the whole set is known. The result equals GetGenesisValidatorsWithOptions and GetGenesisBalances for the
+// concatenated batches. The validators cache is not used.
+type GenesisValidatorStream struct {
+	cfg      *beaconconfig.Config
+	records  []*phase0.Validator
+	balances []phase0.Gwei
+	root     *listRootAccumulator
+
+	salt   uint64
+	salted bool
+
+	// vendorValidator is the first validator with a vendor type, the proposer TEE vendor is taken from it.
+	vendorValidator *validators.Validator
+}
+
+// NewGenesisValidatorStream creates an empty validator stream for the config.
+func NewGenesisValidatorStream(cfg *beaconconfig.Config) *GenesisValidatorStream {
+	salt, _ := cfg.GetUint("VALIDATORS_ROOT_SALT")
+
+	return &GenesisValidatorStream{
+		cfg:  cfg,
+		root: newListRootAccumulator(cfg.GetUintDefault("VALIDATOR_REGISTRY_LIMIT", 1099511627776)),
+		salt: salt,
+	}
+}
+
+// Add converts a batch of validators and appends it to the stream.
+func (s *GenesisValidatorStream) Add(vals []*validators.Validator) error {
+	records, err := buildGenesisValidators(s.cfg, vals, nil)
+	if err != nil {
+		return fmt.Errorf("batch at validator %d: %w", len(s.records), err)
+	}
+
+	farFutureEpoch := s.cfg.GetUintDefault("FAR_FUTURE_EPOCH", 18446744073709551615)
+
+	for _, record := range records {
+		// the salt goes to the first validator active at genesis, see applyValidatorsRootSalt
+		if s.salt != 0 && s.salt < farFutureEpoch && !s.salted && record.ActivationEpoch == 0 {
+			record.ActivationEligibilityEpoch = phase0.Epoch(s.salt)
+			s.salted = true
+		}
+
+		leaf, err := record.HashTreeRoot()
+		if err != nil {
+			return fmt.Errorf("failed to hash validator %d: %w", len(s.records), err)
+		}
+
+		if err := s.root.add(leaf); err != nil {
+			return err
+		}
+
+		s.records = append(s.records, record)
+	}
+
+	s.balances = append(s.balances, GetGenesisBalances(s.cfg, vals)...)
+
+	for _, val := range vals {
+		if s.vendorValidator == nil && val.VendorType != "" {
+			s.vendorValidator = val
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of validators added so far.
+func (s *GenesisValidatorStream) Count() uint64 {
+	return uint64(len(s.records))
+}
+
+// Balances returns the genesis balances of the validators added so far.
+func (s *GenesisValidatorStream) Balances() []phase0.Gwei {
+	return s.balances
+}
+
+// VendorValidators returns the first validator with a vendor type, or nil if there is none. It is all
+// GetGenesisProposerTEEFields needs of the validator set.
+func (s *GenesisValidatorStream) VendorValidators() []*validators.Validator {
+	if s.vendorValidator == nil {
+		return nil
+	}
+
+	return []*validators.Validator{s.vendorValidator}
+}
+
+// Finish returns the validator records and the validators root of all added validators. It does not modify the
+// stream and may be called again. Warnings are passed to warn.
+func (s *GenesisValidatorStream) Finish(warn WarnFn) ([]*phase0.Validator, phase0.Root, error) {
+	if s.salt != 0 {
+		warn.warnf(WarnCodeValidatorsRootSalt, "VALIDATORS_ROOT_SALT is set, genesis validator set is salted for test vectors and not meant for production")
+
+		if s.salt >= s.cfg.GetUintDefault("FAR_FUTURE_EPOCH", 18446744073709551615) {
+			return nil, phase0.Root{}, fmt.Errorf("VALIDATORS_ROOT_SALT %d must be lower than FAR_FUTURE_EPOCH", s.salt)
+		}
+
+		if !s.salted {
+			return nil, phase0.Root{}, fmt.Errorf("VALIDATORS_ROOT_SALT is set, but no validator is active at genesis")
+		}
+	}
+
+	return s.records, s.root.root(), nil
+}
+
+// listRootAccumulator computes the hash tree root of an SSZ list of containers from the element roots as they are
+// added. Like the deposit contract it keeps a single node per tree level instead of all element roots.
+type listRootAccumulator struct {
+	depth int
+	// branch[h] is the root of the last complete subtree at level h, branch[depth] is the root of a full tree.
+	branch []phase0.Root
+	count  uint64
+}
+
+func newListRootAccumulator(limit uint64) *listRootAccumulator {
+	depth := 0
+	if limit > 1 {
+		depth = bits.Len64(limit - 1)
+	}
+
+	return &listRootAccumulator{
+		depth:  depth,
+		branch: make([]phase0.Root, depth+1),
+	}
+}
+
+// capacity returns the number of elements the tree can hold.
+func (a *listRootAccumulator) capacity() uint64 {
+	if a.depth >= 64 {
+		return math.MaxUint64
+	}
+
+	return 1 << a.depth
+}
+
+func (a *listRootAccumulator) add(leaf phase0.Root) error {
+	if a.count >= a.capacity() {
+		return fmt.Errorf("validator list exceeds VALIDATOR_REGISTRY_LIMIT")
+	}
+
+	a.count++
+
+	node := leaf
+	size := a.count
+
+	for level := 0; level <= a.depth; level++ {
+		if size&1 == 1 {
+			a.branch[level] = node
+			return nil
+		}
+
+		node = hashPair(a.branch[level], node)
+		size >>= 1
+	}
+
+	return nil
+}
+
+// root returns the list root, the tree root mixed in with the element count.
+func (a *listRootAccumulator) root() phase0.Root {
+	node := phase0.Root{}
+
+	if a.count == a.capacity() {
+		node = a.branch[a.depth]
+	} else {
+		zeroHash := phase0.Root{}
+		size := a.count
+
+		for level := 0; level < a.depth; level++ {
+			if size&1 == 1 {
+				node = hashPair(a.branch[level], node)
+			} else {
+				node = hashPair(node, zeroHash)
+			}
+
+			zeroHash = hashPair(zeroHash, zeroHash)
+			size >>= 1
+		}
+	}
+
+	var length phase0.Root
+
+	binary.LittleEndian.PutUint64(length[:8], a.count)
+
+	return hashPair(node, length)
+}
+
+func hashPair(left, right phase0.Root) phase0.Root {
+	return sha256.Sum256(append(left[:], right[:]...))
+}
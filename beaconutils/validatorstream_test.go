@@ -0,0 +1,114 @@
+package beaconutils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/eth-beacon-genesis/validators"
+)
+
+func TestGenesisValidatorStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		configValues map[string]interface{}
+		count        int
+		expectAddErr bool
+	}{
+		{name: "empty", configValues: map[string]interface{}{}, count: 0},
+		{name: "single validator", configValues: map[string]interface{}{}, count: 1},
+		{name: "partial tree", configValues: map[string]interface{}{}, count: 37},
+		{name: "full tree", configValues: map[string]interface{}{"VALIDATOR_REGISTRY_LIMIT": uint64(8)}, count: 8},
+		{name: "partial small tree", configValues: map[string]interface{}{"VALIDATOR_REGISTRY_LIMIT": uint64(8)}, count: 5},
+		{name: "salted", configValues: map[string]interface{}{"VALIDATORS_ROOT_SALT": uint64(7)}, count: 10},
+		{name: "over limit", configValues: map[string]interface{}{"VALIDATOR_REGISTRY_LIMIT": uint64(8)}, count: 9, expectAddErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createTestConfig(t, "minimal", test.configValues)
+
+			vals := make([]*validators.Validator, test.count)
+			for i := range vals {
+				vals[i] = &validators.Validator{
+					PublicKey:             phase0.BLSPubKey(makeBytes(48, byte(i+1))),
+					WithdrawalCredentials: makeBytes(32, byte(i)),
+				}
+			}
+
+			if len(vals) > 3 {
+				// the salt goes to the first active validator, which is not in the first batch
+				for _, val := range vals[:3] {
+					val.Balance = ptr(16_000_000_000)
+				}
+
+				vals[4].VendorType = "tdx"
+			}
+
+			stream := NewGenesisValidatorStream(cfg)
+
+			var addErr error
+
+			for start := 0; start < len(vals) && addErr == nil; start += 3 {
+				addErr = stream.Add(vals[start:min(start+3, len(vals))])
+			}
+
+			if test.expectAddErr {
+				if addErr == nil {
+					t.Fatalf("expected an error adding %d validators", test.count)
+				}
+
+				return
+			}
+
+			if addErr != nil {
+				t.Fatalf("failed to add validators: %v", addErr)
+			}
+
+			records, root, err := stream.Finish(nil)
+			if err != nil {
+				t.Fatalf("failed to finish stream: %v", err)
+			}
+
+			expectedRecords, expectedRoot, err := GetGenesisValidatorsWithOptions(cfg, vals, GenesisValidatorsOptions{})
+			if err != nil {
+				t.Fatalf("failed to compute genesis validators: %v", err)
+			}
+
+			if root != expectedRoot {
+				t.Fatalf("stream root %x differs from %x", root, expectedRoot)
+			}
+
+			if len(records) != len(expectedRecords) || (len(records) > 0 && !reflect.DeepEqual(records, expectedRecords)) {
+				t.Fatalf("stream records differ from the genesis validators")
+			}
+
+			if balances := GetGenesisBalances(cfg, vals); len(balances) > 0 && !reflect.DeepEqual(stream.Balances(), balances) {
+				t.Fatalf("stream balances %v differ from %v", stream.Balances(), balances)
+			}
+
+			if vendorVals := stream.VendorValidators(); len(vals) > 3 && (len(vendorVals) != 1 || vendorVals[0] != vals[4]) {
+				t.Fatalf("unexpected vendor validators %v", vendorVals)
+			}
+		})
+	}
+}
+
+func TestGenesisValidatorStreamSaltErrors(t *testing.T) {
+	cfg := createTestConfig(t, "minimal", map[string]interface{}{
+		"VALIDATORS_ROOT_SALT": uint64(7),
+	})
+
+	stream := NewGenesisValidatorStream(cfg)
+
+	err := stream.Add([]*validators.Validator{
+		{PublicKey: phase0.BLSPubKey(makeBytes(48, 1)), WithdrawalCredentials: makeBytes(32, 0), Balance: ptr(16_000_000_000)},
+	})
+	if err != nil {
+		t.Fatalf("failed to add validators: %v", err)
+	}
+
+	if _, _, err := stream.Finish(nil); err == nil {
+		t.Fatalf("expected an error for a salt without active validators")
+	}
+}
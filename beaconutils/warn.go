@@ -0,0 +1,32 @@
+package beaconutils
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Warning codes passed to WarnFn, they name the helper that raised the warning.
+const (
+	WarnCodeTEE                = "tee"
+	WarnCodeSyncCommittee      = "sync_committee"
+	WarnCodeValidatorsRootSalt = "validators_root_salt"
+	WarnCodeValidatorsCache    = "validators_cache"
+	WarnCodeRemoteFetch        = "remote_fetch"
+)
+
+// WarnFn receives the warnings of the helpers on the genesis build path, so the builders can record them and fail
+// on them in strict mode. A nil WarnFn logs the warnings.
+type WarnFn func(code, message string)
+
+// warnf passes the formatted warning to fn, or logs it if fn is nil.
+func (fn WarnFn) warnf(code, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	if fn == nil {
+		logrus.WithField("code", code).Warn(message)
+		return
+	}
+
+	fn(code, message)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
 )
 
+// ComputeWithdrawalsRoot returns the SSZ hash tree root of the withdrawals as list bounded by the
+// MAX_WITHDRAWALS_PER_PAYLOAD of the config (16 if unset), so chains with a custom bound get a matching root.
 func ComputeWithdrawalsRoot(withdrawals types.Withdrawals, cfg *beaconconfig.Config) (phase0.Root, error) {
 	// Compute the SSZ hash-tree-root of the withdrawals,
 	// since that is what we put as withdrawals_root in the CL execution-payload.
@@ -20,7 +22,7 @@ func ComputeWithdrawalsRoot(withdrawals types.Withdrawals, cfg *beaconconfig.Con
 	maxWithdrawalsPerPayload := cfg.GetUintDefault("MAX_WITHDRAWALS_PER_PAYLOAD", 16)
 
 	if num > maxWithdrawalsPerPayload {
-		return phase0.Root{}, fmt.Errorf("withdrawals list is too long")
+		return phase0.Root{}, fmt.Errorf("withdrawals list is too long: %d withdrawals, MAX_WITHDRAWALS_PER_PAYLOAD is %d", num, maxWithdrawalsPerPayload)
 	}
 
 	clWithdrawals := make([]capella.Withdrawal, len(withdrawals))
@@ -135,3 +135,43 @@ func TestComputeWithdrawalsRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeWithdrawalsRootCustomBound(t *testing.T) {
+	defaultCfg := createTestConfig(t, "mainnet", map[string]interface{}{})
+	customCfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"MAX_WITHDRAWALS_PER_PAYLOAD": uint64(64),
+	})
+
+	withdrawals := types.Withdrawals{
+		&types.Withdrawal{
+			Index:     0,
+			Validator: 1,
+			Address:   common.HexToAddress("0x1234567890123456789012345678901234567890"),
+			Amount:    uint64(32000000000),
+		},
+	}
+
+	for _, list := range []types.Withdrawals{{}, withdrawals} {
+		defaultRoot, err := ComputeWithdrawalsRoot(list, defaultCfg)
+		if err != nil {
+			t.Fatalf("failed to compute default bound root: %v", err)
+		}
+
+		customRoot, err := ComputeWithdrawalsRoot(list, customCfg)
+		if err != nil {
+			t.Fatalf("failed to compute custom bound root: %v", err)
+		}
+
+		if defaultRoot == customRoot {
+			t.Fatalf("expected root of %d withdrawals to depend on MAX_WITHDRAWALS_PER_PAYLOAD", len(list))
+		}
+	}
+
+	smallCfg := createTestConfig(t, "mainnet", map[string]interface{}{
+		"MAX_WITHDRAWALS_PER_PAYLOAD": uint64(1),
+	})
+
+	if _, err := ComputeWithdrawalsRoot(append(withdrawals, withdrawals[0]), smallCfg); err == nil {
+		t.Fatalf("expected error for withdrawals above MAX_WITHDRAWALS_PER_PAYLOAD")
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/ethpandaops/eth-beacon-genesis/beaconchain"
 	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
 	"github.com/ethpandaops/eth-beacon-genesis/buildinfo"
 	"github.com/ethpandaops/eth-beacon-genesis/eth1"
 	"github.com/ethpandaops/eth-beacon-genesis/validators"
@@ -38,6 +42,14 @@ var (
 		Name:  "additional-validators",
 		Usage: "Path to the file with a list of additional genesis validators validators",
 	}
+	validatorsStartFlag = &cli.Uint64Flag{
+		Name:  "additional-validators-start",
+		Usage: "Index of the first validator to take from the additional validators file",
+	}
+	validatorsCountFlag = &cli.Uint64Flag{
+		Name:  "additional-validators-count",
+		Usage: "Number of validators to take from the additional validators file (default: all)",
+	}
 	shadowForkBlockFlag = &cli.StringFlag{
 		Name:  "shadow-fork-block",
 		Usage: "Path to the file with a execution block to create a shadow fork from",
@@ -50,16 +62,57 @@ var (
 		Name:  "state-output",
 		Usage: "Path to the file to write the genesis state to in SSZ format",
 	}
+	stateOutputFramedFlag = &cli.BoolFlag{
+		Name:  "state-output-framed",
+		Usage: "Prepend a 1 byte fork version and a 4 byte little-endian length to the --state-output SSZ file",
+	}
 	jsonOutputFlag = &cli.StringFlag{
 		Name:  "json-output",
 		Usage: "Path to the file to write the genesis state to in JSON format",
 	}
+	outputDirFlag = &cli.StringFlag{
+		Name:  "output-dir",
+		Usage: "Path to a directory to write the genesis state to in all formats (genesis.ssz, genesis.json)",
+	}
+	outputNameFlag = &cli.StringFlag{
+		Name:  "output-name",
+		Usage: "File name template (without extension) for the --output-dir files, supports {state_root} and {genesis_time}",
+		Value: beaconchain.DefaultOutputFileTemplate,
+	}
+	metaOutputFlag = &cli.StringFlag{
+		Name:  "meta-output",
+		Usage: "Path to the file to write the genesis metadata sidecar to (genesis-meta.json)",
+	}
+	validatorsOutputFlag = &cli.StringFlag{
+		Name:  "validators-output",
+		Usage: "Path to the file to write the genesis validators to in SSZ format (JSON format if the path ends in .json)",
+	}
+	validatorsBalancesFlag = &cli.BoolFlag{
+		Name:  "validators-output-balances",
+		Usage: "Include the genesis balances in the validators output file",
+	}
+	validatorsManifestFlag = &cli.StringFlag{
+		Name:  "validators-manifest",
+		Usage: "Path to the file to write the validator manifest (index, pubkey, withdrawal credentials, deposit amount) to in JSON format (CSV format if the path ends in .csv)",
+	}
+	depositContractStorageFlag = &cli.StringFlag{
+		Name:  "deposit-contract-storage",
+		Usage: "Path to the file to write the deposit contract storage matching the genesis deposit root to in JSON format, for the execution genesis alloc",
+	}
 
+	debugFlag = &cli.BoolFlag{
+		Name:  "debug",
+		Usage: "Enable debug logging, including the SSZ size of each genesis state field",
+	}
 	quietFlag = &cli.BoolFlag{
 		Name:    "quiet",
 		Aliases: []string{"q"},
 		Usage:   "Suppress output",
 	}
+	logFormatFlag = &cli.StringFlag{
+		Name:  "log-format",
+		Usage: "Log format, text or json. Defaults to GENESIS_LOG_FORMAT of the consensus config",
+	}
 
 	app = &cli.Command{
 		Name:  "eth-genesis-state-generator",
@@ -71,8 +124,10 @@ var (
 				Aliases: []string{"bc", "beacon", "devnet"},
 				Flags: []cli.Flag{
 					eth1ConfigFlag, configFlag, mnemonicsFileFlag, validatorsFileFlag,
-					shadowForkBlockFlag, shadowForkRPCFlag, stateOutputFlag, jsonOutputFlag,
-					quietFlag,
+					validatorsStartFlag, validatorsCountFlag, shadowForkBlockFlag, shadowForkRPCFlag,
+					stateOutputFlag, stateOutputFramedFlag, jsonOutputFlag, outputDirFlag, outputNameFlag, metaOutputFlag,
+					validatorsOutputFlag, validatorsBalancesFlag, validatorsManifestFlag, depositContractStorageFlag,
+					debugFlag, quietFlag, logFormatFlag,
 				},
 				Action:    runDevnet,
 				UsageText: "eth-beacon-genesis beaconchain [options]",
@@ -107,10 +162,24 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 	shadowForkRPC := cmd.String(shadowForkRPCFlag.Name)
 	stateOutputFile := cmd.String(stateOutputFlag.Name)
 	jsonOutputFile := cmd.String(jsonOutputFlag.Name)
+	outputDir := cmd.String(outputDirFlag.Name)
+	outputName := cmd.String(outputNameFlag.Name)
+	metaOutputFile := cmd.String(metaOutputFlag.Name)
+	validatorsOutputFile := cmd.String(validatorsOutputFlag.Name)
+	validatorsManifestFile := cmd.String(validatorsManifestFlag.Name)
+	depositContractStorageFile := cmd.String(depositContractStorageFlag.Name)
 	quiet := cmd.Bool(quietFlag.Name)
 
 	if quiet {
 		logrus.SetLevel(logrus.PanicLevel)
+	} else if cmd.Bool(debugFlag.Name) {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	if cmd.IsSet(logFormatFlag.Name) {
+		if err := configureLogFormat(cmd.String(logFormatFlag.Name)); err != nil {
+			return err
+		}
 	}
 
 	if !quiet {
@@ -129,6 +198,12 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("failed to load consensus config: %w", err)
 	}
 
+	if logFormat, found := clConfig.GetString("GENESIS_LOG_FORMAT"); found && !cmd.IsSet(logFormatFlag.Name) {
+		if err := configureLogFormat(logFormat); err != nil {
+			return err
+		}
+	}
+
 	logrus.Infof("loaded consensus config. genesis fork version: 0x%x", clConfig.GetBytesDefault("GENESIS_FORK_VERSION", []byte{}))
 
 	var clValidators []*validators.Validator
@@ -150,6 +225,20 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 			return fmt.Errorf("failed to load validators from file: %w", err2)
 		}
 
+		if cmd.IsSet(validatorsStartFlag.Name) || cmd.IsSet(validatorsCountFlag.Name) {
+			start := cmd.Uint64(validatorsStartFlag.Name)
+			count := uint64(len(vals)) - min(start, uint64(len(vals)))
+
+			if cmd.IsSet(validatorsCountFlag.Name) {
+				count = cmd.Uint64(validatorsCountFlag.Name)
+			}
+
+			vals, err2 = validators.Slice(vals, start, count)
+			if err2 != nil {
+				return fmt.Errorf("failed to select validators from file: %w", err2)
+			}
+		}
+
 		if len(vals) > 0 {
 			clValidators = append(clValidators, vals...)
 		}
@@ -159,15 +248,9 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("no validators found")
 	}
 
-	defaultBalance := clConfig.GetUintDefault("MAX_EFFECTIVE_BALANCE", 32_000_000_000)
-	totalBalance := uint64(0)
-
-	for _, val := range clValidators {
-		if val.Balance != nil {
-			totalBalance += *val.Balance
-		} else {
-			totalBalance += defaultBalance
-		}
+	totalBalance, err := beaconutils.SumGwei(beaconutils.GetGenesisBalances(clConfig, clValidators))
+	if err != nil {
+		return fmt.Errorf("failed to compute total validator balance: %w", err)
 	}
 
 	// check for duplicate public keys
@@ -181,7 +264,7 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 		pubkeyMap[val.PublicKey] = true
 	}
 
-	logrus.Infof("loaded %d validators. total balance: %d ETH", len(clValidators), totalBalance/1_000_000_000)
+	logrus.Infof("loaded %d validators. total balance: %d ETH", len(clValidators), uint64(totalBalance)/1_000_000_000)
 
 	builder := beaconchain.NewGenesisBuilder(elGenesis, clConfig)
 	builder.AddValidators(clValidators)
@@ -190,7 +273,7 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 		var gensisBlock *types.Block
 
 		if shadowForkBlock != "" {
-			block, err2 := eth1.LoadBlockFromFile(shadowForkBlock)
+			block, err2 := eth1.LoadBlockFromFileWithConfig(shadowForkBlock, beaconutils.GetRemoteFetchConfig(clConfig))
 			if err2 != nil {
 				return fmt.Errorf("failed to load shadow fork block from file: %w", err2)
 			}
@@ -199,7 +282,7 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 
 			gensisBlock = block
 		} else {
-			block, err2 := eth1.GetBlockFromRPC(ctx, shadowForkRPC)
+			block, err2 := eth1.GetBlockFromRPCWithConfig(ctx, shadowForkRPC, beaconutils.GetRemoteFetchConfig(clConfig))
 			if err2 != nil {
 				return fmt.Errorf("failed to get shadow fork block: %w", err2)
 			}
@@ -219,8 +302,20 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 
 	logrus.Infof("successfully built genesis state.")
 
+	dynSsz := beaconutils.GetDynSSZ(clConfig)
+	if provider, ok := builder.(beaconchain.DynSSZProvider); ok {
+		dynSsz = provider.DynSSZ()
+	}
+
 	if stateOutputFile != "" {
-		sszData, err := builder.Serialize(genesisState, http.ContentTypeSSZ)
+		var sszData []byte
+
+		if cmd.Bool(stateOutputFramedFlag.Name) {
+			sszData, err = beaconchain.SerializeFramed(builder, genesisState)
+		} else {
+			sszData, err = builder.Serialize(genesisState, http.ContentTypeSSZ)
+		}
+
 		if err != nil {
 			return fmt.Errorf("failed to serialize genesis state: %w", err)
 		}
@@ -247,7 +342,95 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	if stateOutputFile == "" && jsonOutputFile == "" {
+	if outputDir != "" {
+		if err := beaconchain.WriteAllWithTemplate(builder, genesisState, outputDir, []http.ContentType{http.ContentTypeSSZ, http.ContentTypeJSON}, outputName); err != nil {
+			return fmt.Errorf("failed to write genesis state to output directory: %w", err)
+		}
+
+		logrus.Infof("serialized genesis state to output directory: %s", outputDir)
+	}
+
+	if metaOutputFile != "" {
+		networkName, _ := clConfig.GetString("CONFIG_NAME")
+
+		meta, err := beaconchain.NewGenesisMeta(dynSsz, genesisState, networkName, buildinfo.GetBuildVersion())
+		if err != nil {
+			return fmt.Errorf("failed to collect genesis metadata: %w", err)
+		}
+
+		if err := beaconchain.WriteGenesisMeta(metaOutputFile, meta); err != nil {
+			return err
+		}
+
+		logrus.Infof("wrote genesis metadata to file: %s", metaOutputFile)
+	}
+
+	if validatorsOutputFile != "" {
+		genesisValidators, err := beaconchain.NewGenesisValidators(genesisState, cmd.Bool(validatorsBalancesFlag.Name))
+		if err != nil {
+			return fmt.Errorf("failed to collect genesis validators: %w", err)
+		}
+
+		contentType := http.ContentTypeSSZ
+		if strings.HasSuffix(validatorsOutputFile, ".json") {
+			contentType = http.ContentTypeJSON
+		}
+
+		validatorsData, err := beaconchain.SerializeGenesisValidators(dynSsz, genesisValidators, contentType)
+		if err != nil {
+			return fmt.Errorf("failed to serialize genesis validators: %w", err)
+		}
+
+		if err := os.WriteFile(validatorsOutputFile, validatorsData, 0o644); err != nil { //nolint:gosec // no strict permissions needed
+			return fmt.Errorf("failed to write genesis validators: %w", err)
+		}
+
+		logrus.Infof("wrote genesis validators to file: %s", validatorsOutputFile)
+	}
+
+	if validatorsManifestFile != "" {
+		manifestFormat := "json"
+		if strings.HasSuffix(validatorsManifestFile, ".csv") {
+			manifestFormat = "csv"
+		}
+
+		provider, ok := builder.(beaconchain.StateValidatorsProvider)
+		if !ok {
+			return fmt.Errorf("failed to export validator manifest: builder does not provide the state validators")
+		}
+
+		var manifest bytes.Buffer
+		// the builder's validator list is in state order, i.e. sorted and extended by filler validators if configured
+		if err := validators.ExportManifest(provider.StateValidators(), &manifest, manifestFormat); err != nil {
+			return fmt.Errorf("failed to export validator manifest: %w", err)
+		}
+
+		if err := os.WriteFile(validatorsManifestFile, manifest.Bytes(), 0o644); err != nil { //nolint:gosec // no strict permissions needed
+			return fmt.Errorf("failed to write validator manifest: %w", err)
+		}
+
+		logrus.Infof("wrote validator manifest to file: %s", validatorsManifestFile)
+	}
+
+	if depositContractStorageFile != "" {
+		storage, err := beaconutils.DepositContractStorage(clConfig)
+		if err != nil {
+			return fmt.Errorf("failed to compute deposit contract storage: %w", err)
+		}
+
+		storageData, err := json.MarshalIndent(storage, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize deposit contract storage: %w", err)
+		}
+
+		if err := os.WriteFile(depositContractStorageFile, storageData, 0o644); err != nil { //nolint:gosec // no strict permissions needed
+			return fmt.Errorf("failed to write deposit contract storage: %w", err)
+		}
+
+		logrus.Infof("wrote deposit contract storage to file: %s", depositContractStorageFile)
+	}
+
+	if stateOutputFile == "" && jsonOutputFile == "" && outputDir == "" {
 		jsonData, err := builder.Serialize(genesisState, http.ContentTypeJSON)
 		if err != nil {
 			return fmt.Errorf("failed to serialize genesis state: %w", err)
@@ -258,3 +441,16 @@ func runDevnet(ctx context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+// configureLogFormat sets the format of the global logger once for the whole run, "text" (default) or "json".
+func configureLogFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", "text":
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+
+	return nil
+}
@@ -1,15 +1,16 @@
 package eth1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
 )
 
 type rpcBlock struct {
@@ -62,20 +63,23 @@ func ParseEthBlock(blockData json.RawMessage) (*types.Block, error) {
 	}), nil
 }
 
+// LoadBlockFromFile loads a block from a file or a http(s) URL. URLs are fetched with the default remote
+// fetch settings.
 func LoadBlockFromFile(filePath string) (*types.Block, error) {
+	return LoadBlockFromFileWithConfig(filePath, beaconutils.DefaultRemoteFetchConfig())
+}
+
+// LoadBlockFromFileWithConfig loads a block from a file or a http(s) URL. URLs are fetched with the timeout and
+// retries of fetchCfg.
+func LoadBlockFromFileWithConfig(filePath string, fetchCfg beaconutils.RemoteFetchConfig) (*types.Block, error) {
 	var blockBytes []byte
 
 	if strings.HasPrefix(filePath, "http://") || strings.HasPrefix(filePath, "https://") {
-		resp, err := http.Get(filePath) //nolint:gosec // This is a valid use case as we want to load the block from a variable URL
-		if err != nil {
-			return nil, fmt.Errorf("failed to get block from URL: %w", err)
-		}
-
-		defer resp.Body.Close()
+		var err error
 
-		blockBytes, err = io.ReadAll(resp.Body)
+		blockBytes, err = beaconutils.FetchURL(context.Background(), fetchCfg, filePath, 0)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read block from URL: %w", err)
+			return nil, fmt.Errorf("failed to get block from URL: %w", err)
 		}
 	} else {
 		var err error
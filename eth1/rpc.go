@@ -8,6 +8,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconutils"
 )
 
 type JSONData struct {
@@ -16,23 +19,42 @@ type JSONData struct {
 	Result  json.RawMessage `json:"result"`
 }
 
+// GetBlockFromRPC fetches the latest block from the execution RPC at host with the default remote fetch settings.
 func GetBlockFromRPC(ctx context.Context, host string) (*types.Block, error) {
-	client, err := ethclient.Dial(host)
+	return GetBlockFromRPCWithConfig(ctx, host, beaconutils.DefaultRemoteFetchConfig())
+}
+
+// GetBlockFromRPCWithConfig fetches the latest block from the execution RPC at host. Each request is limited by
+// the timeout of fetchCfg and retried on failure.
+func GetBlockFromRPCWithConfig(ctx context.Context, host string, fetchCfg beaconutils.RemoteFetchConfig) (*types.Block, error) {
+	rpcClient, err := rpc.DialOptions(ctx, host, rpc.WithHTTPClient(fetchCfg.HTTPClient()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the ETH client %s", err)
 	}
 
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	var resultBlock *types.Block
+
 	// Get the latest block
-	blockNumberUint64, err := client.BlockNumber(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get the block number %s", err)
-	}
+	err = fetchCfg.Retry(ctx, "fetch shadow fork block from "+host, func(ctx context.Context) error {
+		blockNumberUint64, err := client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get the block number %s", err)
+		}
+
+		blockNumberBigint := new(big.Int).SetUint64(blockNumberUint64)
 
-	blockNumberBigint := new(big.Int).SetUint64(blockNumberUint64)
+		resultBlock, err = client.BlockByNumber(ctx, blockNumberBigint)
+		if err != nil {
+			return fmt.Errorf("failed to get the ETH block %s", err)
+		}
 
-	resultBlock, err := client.BlockByNumber(ctx, blockNumberBigint)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get the ETH block %s", err)
+		return nil, err
 	}
 
 	return resultBlock, nil
@@ -0,0 +1,64 @@
+package validators
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// Accumulator collects validators from multiple concurrent loaders.
+// The result is ordered by source priority, then by the index of the validator within its source,
+// so it does not depend on the order in which the loaders finish.
+type Accumulator struct {
+	mutex   sync.Mutex
+	entries []accumulatorEntry
+}
+
+type accumulatorEntry struct {
+	priority  int
+	index     int
+	validator *Validator
+}
+
+// Add adds the validators of a source with the given priority. Lower priorities are ordered first.
+// It is safe for concurrent use.
+func (a *Accumulator) Add(priority int, vals []*Validator) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for idx, val := range vals {
+		a.entries = append(a.entries, accumulatorEntry{
+			priority:  priority,
+			index:     idx,
+			validator: val,
+		})
+	}
+}
+
+// Validators returns all added validators in deterministic order.
+// Validators with equal priority and index (from sources sharing a priority) are ordered by public key.
+func (a *Accumulator) Validators() []*Validator {
+	a.mutex.Lock()
+	entries := make([]accumulatorEntry, len(a.entries))
+	copy(entries, a.entries)
+	a.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].priority != entries[j].priority {
+			return entries[i].priority < entries[j].priority
+		}
+
+		if entries[i].index != entries[j].index {
+			return entries[i].index < entries[j].index
+		}
+
+		return bytes.Compare(entries[i].validator.PublicKey[:], entries[j].validator.PublicKey[:]) < 0
+	})
+
+	vals := make([]*Validator, len(entries))
+	for idx, entry := range entries {
+		vals[idx] = entry.validator
+	}
+
+	return vals
+}
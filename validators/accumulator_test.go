@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestAccumulator(t *testing.T) {
+	sources := [][]*Validator{
+		{{PublicKey: phase0.BLSPubKey{0x10}}, {PublicKey: phase0.BLSPubKey{0x11}}},
+		{{PublicKey: phase0.BLSPubKey{0x20}}, {PublicKey: phase0.BLSPubKey{0x21}}, {PublicKey: phase0.BLSPubKey{0x22}}},
+		{{PublicKey: phase0.BLSPubKey{0x30}}},
+	}
+
+	for run := 0; run < 10; run++ {
+		accumulator := &Accumulator{}
+
+		var wg sync.WaitGroup
+
+		// add in reverse priority order to ensure the result does not depend on insertion order
+		for priority := len(sources) - 1; priority >= 0; priority-- {
+			wg.Add(1)
+
+			go func(priority int) {
+				defer wg.Done()
+
+				accumulator.Add(priority, sources[priority])
+			}(priority)
+		}
+
+		wg.Wait()
+
+		vals := accumulator.Validators()
+		expected := []byte{0x10, 0x11, 0x20, 0x21, 0x22, 0x30}
+
+		if len(vals) != len(expected) {
+			t.Fatalf("expected %d validators, got %d", len(expected), len(vals))
+		}
+
+		for i, pubkey := range expected {
+			if vals[i].PublicKey[0] != pubkey {
+				t.Fatalf("unexpected validator at index %d: got %s", i, vals[i].PublicKey.String())
+			}
+		}
+	}
+}
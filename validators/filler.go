@@ -0,0 +1,31 @@
+package validators
+
+import (
+	"encoding/binary"
+)
+
+// fillerPubkeyPrefix marks the placeholder pubkeys of filler validators.
+var fillerPubkeyPrefix = []byte("filler")
+
+// GenerateFillerValidators returns count placeholder validators for SSZ size and hashing studies. The pubkeys are
+// deterministic and unique ("filler" followed by the big endian index), but are no valid BLS keys. Filler validators
+// have a zero balance, so they stay inactive and never enter committee selection.
+// They are not meant for production networks.
+func GenerateFillerValidators(count uint64) []*Validator {
+	vals := make([]*Validator, count)
+	zeroBalance := uint64(0)
+
+	for i := range vals {
+		validator := &Validator{
+			WithdrawalCredentials: make([]byte, 32),
+			Balance:               &zeroBalance,
+		}
+
+		copy(validator.PublicKey[:], fillerPubkeyPrefix)
+		binary.BigEndian.PutUint64(validator.PublicKey[40:], uint64(i))
+
+		vals[i] = validator
+	}
+
+	return vals
+}
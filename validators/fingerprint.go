@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"encoding/hex"
+
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// fingerprintListLimit is the list limit used for the fingerprint root, the mainnet VALIDATOR_REGISTRY_LIMIT.
+const fingerprintListLimit = 1 << 40
+
+// FingerprintRoot returns the hash tree root of the validator list, with each validator hashed as a container
+// of pubkey, withdrawal credentials and the optional balance and effective balance (each as presence flag and value).
+func FingerprintRoot(vals []*Validator) ([32]byte, error) {
+	hh := ssz.DefaultHasherPool.Get()
+	defer ssz.DefaultHasherPool.Put(hh)
+
+	listIndx := hh.Index()
+
+	for _, val := range vals {
+		indx := hh.Index()
+
+		hh.PutBytes(val.PublicKey[:])
+		hh.PutBytes(val.WithdrawalCredentials)
+
+		for _, balance := range []*uint64{val.Balance, val.EffectiveBalance} {
+			hh.PutBool(balance != nil)
+
+			if balance != nil {
+				hh.PutUint64(*balance)
+			} else {
+				hh.PutUint64(0)
+			}
+		}
+
+		hh.Merkleize(indx)
+	}
+
+	hh.MerkleizeWithMixin(listIndx, uint64(len(vals)), fingerprintListLimit)
+
+	return hh.HashRoot()
+}
+
+// Fingerprint returns a short identifier of the validator set, the first 8 bytes of FingerprintRoot as hex.
+// It changes with any pubkey, withdrawal credential or balance change and with the validator order.
+func Fingerprint(vals []*Validator) string {
+	root, err := FingerprintRoot(vals)
+	if err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(root[:8])
+}
@@ -0,0 +1,47 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestFingerprint(t *testing.T) {
+	newValidators := func() []*Validator {
+		return []*Validator{
+			{PublicKey: phase0.BLSPubKey{0x01}, WithdrawalCredentials: append([]byte{0x01}, make([]byte, 31)...)},
+			{PublicKey: phase0.BLSPubKey{0x02}, WithdrawalCredentials: append([]byte{0x00}, make([]byte, 31)...)},
+		}
+	}
+
+	fingerprint := Fingerprint(newValidators())
+	if len(fingerprint) != 16 {
+		t.Fatalf("expected 8 byte hex fingerprint, got %q", fingerprint)
+	}
+
+	if Fingerprint(newValidators()) != fingerprint {
+		t.Fatalf("fingerprint is not stable for the same validator set")
+	}
+
+	changed := newValidators()
+	changed[1].WithdrawalCredentials[31] = 0x01
+
+	if Fingerprint(changed) == fingerprint {
+		t.Fatalf("expected fingerprint to change with the withdrawal credentials")
+	}
+
+	balance := uint64(0)
+	changed = newValidators()
+	changed[0].Balance = &balance
+
+	if Fingerprint(changed) == fingerprint {
+		t.Fatalf("expected fingerprint to change with an explicit zero balance")
+	}
+
+	swapped := newValidators()
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+
+	if Fingerprint(swapped) == fingerprint {
+		t.Fatalf("expected fingerprint to change with the validator order")
+	}
+}
@@ -42,8 +42,8 @@ func LoadValidatorsFromFile(validatorsConfigPath string) ([]*Validator, error) {
 			return nil, err
 		}
 
-		if len(pubKey) != 48 {
-			return nil, fmt.Errorf("invalid pubkey (invalid length) on line %v", lineNum)
+		if err := checkPublicKey(len(validators), pubKey); err != nil {
+			return nil, fmt.Errorf("%w on line %v", err, lineNum)
 		}
 
 		if pubkeyMap[string(pubKey)] != 0 {
@@ -62,18 +62,8 @@ func LoadValidatorsFromFile(validatorsConfigPath string) ([]*Validator, error) {
 			return nil, err
 		}
 
-		if len(withdrawalCred) != 32 {
-			return nil, fmt.Errorf("invalid withdrawal credentials (invalid length) on line %v", lineNum)
-		}
-
-		switch withdrawalCred[0] {
-		case 0x00:
-		case 0x01, 0x02:
-			if !bytes.Equal(withdrawalCred[1:12], []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}) {
-				return nil, fmt.Errorf("invalid withdrawal credentials (invalid 0x01/0x02 cred) on line %v", lineNum)
-			}
-		default:
-			return nil, fmt.Errorf("invalid withdrawal credentials (invalid type) on line %v", lineNum)
+		if err := checkWithdrawalCredentials(withdrawalCred); err != nil {
+			return nil, fmt.Errorf("invalid withdrawal credentials (%w) on line %v", err, lineNum)
 		}
 
 		copy(validatorEntry.WithdrawalCredentials, withdrawalCred)
@@ -88,8 +78,37 @@ func LoadValidatorsFromFile(validatorsConfigPath string) ([]*Validator, error) {
 			validatorEntry.Balance = &balance
 		}
 
+		// Effective balance override
+		if len(lineParts) > 3 {
+			effectiveBalance, err := strconv.ParseUint(lineParts[3], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			validatorEntry.EffectiveBalance = &effectiveBalance
+		}
+
 		validators = append(validators, validatorEntry)
 	}
 
 	return validators, nil
 }
+
+// checkWithdrawalCredentials checks the length and type prefix of genesis withdrawal credentials.
+func checkWithdrawalCredentials(withdrawalCred []byte) error {
+	if len(withdrawalCred) != 32 {
+		return fmt.Errorf("invalid length")
+	}
+
+	switch withdrawalCred[0] {
+	case 0x00:
+	case 0x01, 0x02:
+		if !bytes.Equal(withdrawalCred[1:12], []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}) {
+			return fmt.Errorf("invalid 0x01/0x02 cred")
+		}
+	default:
+		return fmt.Errorf("invalid type")
+	}
+
+	return nil
+}
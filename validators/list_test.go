@@ -256,3 +256,55 @@ func TestLoadValidatorsFromFile_InvalidBalance(t *testing.T) {
 		t.Fatalf("expected error to contain 'invalid syntax', got %s", err)
 	}
 }
+
+func TestLoadValidatorsFromFile_EffectiveBalance(t *testing.T) {
+	validatorsFile := createTestValidatorsFile(t, `
+# <validator pubkey>:<withdrawal credentials>:<balance>:<effective balance>
+0xb744b5466a214762ee17621dc4c75d1bba16417e20755f7c9c2485ea518580be50d2c87d70cc4ac393158eb34311c9a2:020000000000000000000000000000000000000000000000000000000000dEaD:2048000000000:2048000000000
+`)
+
+	validators, err := LoadValidatorsFromFile(validatorsFile)
+	if err != nil {
+		t.Fatalf("failed to load validators: %v", err)
+	}
+
+	if len(validators) != 1 {
+		t.Fatalf("expected 1 validator, got %d", len(validators))
+	}
+
+	if validators[0].EffectiveBalance == nil || *validators[0].EffectiveBalance != 2048000000000 {
+		t.Fatalf("expected validator 0 to have effective balance 2048000000000, got %v", validators[0].EffectiveBalance)
+	}
+}
+
+func TestLoadValidatorsFromFile_ShortPubkeyIndex(t *testing.T) {
+	validatorsFile := createTestValidatorsFile(t, `
+0x9824e447621e4b3bca7794b91c664cc0b43322a70b1881b2f804e3a990a3965a64bfe7f098cb4c0396cd0c89218de0b4:001547805ff0547da9e51a7463a6a0c603eeda01dd930f7016185f0642b9ecaf
+0x9824e447621e4b3bca7794b91c664cc0b43322a70b1881b2f804e3a990a3965a64bfe7f098cb4c0396cd0c89218de0:008aa7b9c37bf27e7c49a3185a3e721c7a02c94da7a0b6ad5f88f1b0477d3b88
+`)
+
+	_, err := LoadValidatorsFromFile(validatorsFile)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid pubkey (invalid length) for validator 1: 47 bytes") {
+		t.Fatalf("expected error to contain the offending index, got %s", err)
+	}
+}
+
+func TestLoadValidatorsFromFile_ZeroPubkey(t *testing.T) {
+	validatorsFile := createTestValidatorsFile(t, `
+0x9824e447621e4b3bca7794b91c664cc0b43322a70b1881b2f804e3a990a3965a64bfe7f098cb4c0396cd0c89218de0b4:001547805ff0547da9e51a7463a6a0c603eeda01dd930f7016185f0642b9ecaf
+0x`+strings.Repeat("00", 48)+`:008aa7b9c37bf27e7c49a3185a3e721c7a02c94da7a0b6ad5f88f1b0477d3b88
+`)
+
+	_, err := LoadValidatorsFromFile(validatorsFile)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "invalid pubkey (all zero) for validator 1") {
+		t.Fatalf("expected error to contain the offending index, got %s", err)
+	}
+}
@@ -0,0 +1,67 @@
+package validators
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ManifestEntry is one row of a validator manifest.
+type ManifestEntry struct {
+	Index                 int    `json:"index"`
+	PublicKey             string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	// DepositAmount is the deposit amount in gwei, nil if the validator gets the max effective balance.
+	DepositAmount *uint64 `json:"deposit_amount"`
+}
+
+// ExportManifest writes the mapping of validator index to pubkey, withdrawal credentials and deposit amount
+// to w, for audits of a genesis. The format is either "csv" or "json". Validators without an explicit
+// balance have an empty deposit amount, they get the max effective balance of the genesis fork.
+// The indices are the positions in vals, so vals must be in genesis order.
+func ExportManifest(vals []*Validator, w io.Writer, format string) error {
+	entries := make([]ManifestEntry, len(vals))
+
+	for i, val := range vals {
+		entries[i] = ManifestEntry{
+			Index:                 i,
+			PublicKey:             val.PublicKey.String(),
+			WithdrawalCredentials: fmt.Sprintf("%#x", val.WithdrawalCredentials),
+			DepositAmount:         val.Balance,
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(entries)
+	case "csv":
+		writer := csv.NewWriter(w)
+
+		if err := writer.Write([]string{"index", "pubkey", "withdrawal_credentials", "deposit_amount"}); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			depositAmount := ""
+			if entry.DepositAmount != nil {
+				depositAmount = strconv.FormatUint(*entry.DepositAmount, 10)
+			}
+
+			if err := writer.Write([]string{strconv.Itoa(entry.Index), entry.PublicKey, entry.WithdrawalCredentials, depositAmount}); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported validator manifest format: %s", format)
+	}
+}
@@ -0,0 +1,66 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+func TestExportManifest(t *testing.T) {
+	balance := uint64(32000000000)
+	vals := []*Validator{
+		{PublicKey: phase0.BLSPubKey{0x01}, WithdrawalCredentials: append([]byte{0x01}, make([]byte, 31)...), Balance: &balance},
+		{PublicKey: phase0.BLSPubKey{0x02}, WithdrawalCredentials: append([]byte{0x00}, make([]byte, 31)...)},
+		{PublicKey: phase0.BLSPubKey{0x03}, WithdrawalCredentials: append([]byte{0x02}, make([]byte, 31)...)},
+	}
+
+	var jsonOut bytes.Buffer
+	if err := ExportManifest(vals, &jsonOut, "json"); err != nil {
+		t.Fatalf("failed to export json manifest: %v", err)
+	}
+
+	entries := []ManifestEntry{}
+	if err := json.Unmarshal(jsonOut.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode json manifest: %v", err)
+	}
+
+	if len(entries) != len(vals) {
+		t.Fatalf("expected %d manifest entries, got %d", len(vals), len(entries))
+	}
+
+	for i, entry := range entries {
+		if entry.Index != i || entry.PublicKey != vals[i].PublicKey.String() || entry.WithdrawalCredentials != fmt.Sprintf("%#x", vals[i].WithdrawalCredentials) {
+			t.Fatalf("manifest entry %d does not match validator: %+v", i, entry)
+		}
+	}
+
+	if entries[0].DepositAmount == nil || *entries[0].DepositAmount != balance || entries[1].DepositAmount != nil {
+		t.Fatalf("unexpected deposit amounts in manifest: %v, %v", entries[0].DepositAmount, entries[1].DepositAmount)
+	}
+
+	var csvOut bytes.Buffer
+	if err := ExportManifest(vals, &csvOut, "csv"); err != nil {
+		t.Fatalf("failed to export csv manifest: %v", err)
+	}
+
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to decode csv manifest: %v", err)
+	}
+
+	if len(rows) != len(vals)+1 {
+		t.Fatalf("expected %d csv rows with header, got %d", len(vals)+1, len(rows))
+	}
+
+	if rows[1][1] != vals[0].PublicKey.String() || rows[1][3] != "32000000000" || rows[2][3] != "" {
+		t.Fatalf("unexpected csv manifest rows: %v", rows[1:])
+	}
+
+	if err := ExportManifest(vals, &bytes.Buffer{}, "yaml"); err == nil {
+		t.Fatalf("expected error for unsupported manifest format")
+	}
+}
@@ -24,6 +24,11 @@ func GenerateValidatorsByMnemonic(mnemonicsConfigPath string) ([]*Validator, err
 		return nil, err
 	}
 
+	return GenerateValidatorsFromMnemonics(mnemonics)
+}
+
+// GenerateValidatorsFromMnemonics generates the validators of the given mnemonic sources, in source order.
+func GenerateValidatorsFromMnemonics(mnemonics []MnemonicSrc) ([]*Validator, error) {
 	var valCount uint64
 
 	for _, mnemonicSrc := range mnemonics {
@@ -109,6 +114,10 @@ func GenerateValidatorsByMnemonic(mnemonicsConfigPath string) ([]*Validator, err
 					data.Balance = &mnemonicSrc.Balance
 				}
 
+				if mnemonicSrc.EffectiveBalance > 0 {
+					data.EffectiveBalance = &mnemonicSrc.EffectiveBalance
+				}
+
 				validators[valIndex] = data
 				count := atomic.AddInt32(&prog, 1)
 
@@ -138,6 +147,14 @@ func withdrawalKeyName(i uint64) string {
 	return fmt.Sprintf("m/12381/3600/%d/0", i)
 }
 
+// MnemonicFromSeed derives a 24 word BIP39 mnemonic from an arbitrary seed. The same seed always yields the
+// same mnemonic, so test networks can be reproduced from a single seed.
+func MnemonicFromSeed(seed []byte) (string, error) {
+	entropy := sha256.Sum256(seed)
+
+	return bip39.NewMnemonic(entropy[:])
+}
+
 func seedFromMnemonic(mnemonic string) (seed []byte, err error) {
 	mnemonic = strings.TrimSpace(mnemonic)
 	if !bip39.IsMnemonicValid(mnemonic) {
@@ -148,14 +165,15 @@ func seedFromMnemonic(mnemonic string) (seed []byte, err error) {
 }
 
 type MnemonicSrc struct {
-	Mnemonic   string `yaml:"mnemonic"`
-	Start      uint64 `yaml:"start"`
-	Count      uint64 `yaml:"count"`
-	Balance    uint64 `yaml:"balance"`
-	WdAddress  string `yaml:"wd_address"`
-	WdPrefix   string `yaml:"wd_prefix"`
-	WdKeyPath  string `yaml:"wd_key_path"`
-	VendorType string `yaml:"vendor_type"`
+	Mnemonic         string `yaml:"mnemonic"`
+	Start            uint64 `yaml:"start"`
+	Count            uint64 `yaml:"count"`
+	Balance          uint64 `yaml:"balance"`
+	EffectiveBalance uint64 `yaml:"effective_balance"`
+	WdAddress        string `yaml:"wd_address"`
+	WdPrefix         string `yaml:"wd_prefix"`
+	WdKeyPath        string `yaml:"wd_key_path"`
+	VendorType       string `yaml:"vendor_type"`
 }
 
 func loadMnemonics(srcPath string) ([]MnemonicSrc, error) {
@@ -0,0 +1,171 @@
+package validators
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// validatorShard is the JSON format of a validator shard file. StartIndex is the global index of the first
+// validator in the shard, so the shard covers the index range [StartIndex, StartIndex+len(Validators)).
+type validatorShard struct {
+	StartIndex uint64                `json:"start_index"`
+	Validators []validatorShardEntry `json:"validators"`
+}
+
+type validatorShardEntry struct {
+	PublicKey             string  `json:"pubkey"`
+	WithdrawalCredentials string  `json:"withdrawal_credentials"`
+	Balance               *uint64 `json:"balance,omitempty"`
+	EffectiveBalance      *uint64 `json:"effective_balance,omitempty"`
+	VendorType            string  `json:"vendor_type,omitempty"`
+}
+
+// LoadSharded loads the validators from all JSON shard files matching the glob pattern
+// (e.g. "validators-*.json") and concatenates them in filename order. Numbers in filenames are
+// compared numerically, so validators-10.json follows validators-9.json.
+// The declared index ranges of the shards must form a contiguous range starting at 0;
+// gaps, overlaps and duplicate pubkeys are reported as errors.
+func LoadSharded(glob string) ([]*Validator, error) {
+	shardFiles, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard pattern %q: %w", glob, err)
+	}
+
+	if len(shardFiles) == 0 {
+		return nil, fmt.Errorf("no validator shard files match %q", glob)
+	}
+
+	sort.SliceStable(shardFiles, func(i, j int) bool {
+		return naturalLess(filepath.Base(shardFiles[i]), filepath.Base(shardFiles[j]))
+	})
+
+	validators := make([]*Validator, 0)
+	pubkeyMap := map[phase0.BLSPubKey]uint64{}
+
+	for _, shardFile := range shardFiles {
+		shard, err := loadValidatorShard(shardFile)
+		if err != nil {
+			return nil, err
+		}
+
+		nextIndex := uint64(len(validators))
+
+		switch {
+		case shard.StartIndex > nextIndex:
+			return nil, fmt.Errorf("gap in validator shards: %s starts at index %d, expected %d", shardFile, shard.StartIndex, nextIndex)
+		case shard.StartIndex < nextIndex:
+			return nil, fmt.Errorf("overlapping validator shards: %s starts at index %d, previous shards end at %d", shardFile, shard.StartIndex, nextIndex)
+		}
+
+		for i, entry := range shard.Validators {
+			index := shard.StartIndex + uint64(i)
+
+			validator, err := entry.toValidator(int(index)) //nolint:gosec // no overflow
+			if err != nil {
+				return nil, fmt.Errorf("invalid validator %d in %s: %w", i, shardFile, err)
+			}
+
+			if prevIdx, found := pubkeyMap[validator.PublicKey]; found {
+				return nil, fmt.Errorf("duplicate pubkey %s at index %d (first seen at index %d)", validator.PublicKey.String(), index, prevIdx)
+			}
+
+			pubkeyMap[validator.PublicKey] = index
+
+			validators = append(validators, validator)
+		}
+	}
+
+	return validators, nil
+}
+
+func loadValidatorShard(path string) (*validatorShard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	shard := &validatorShard{}
+	if err := json.Unmarshal(data, shard); err != nil {
+		return nil, fmt.Errorf("failed to decode validator shard %s: %w", path, err)
+	}
+
+	return shard, nil
+}
+
+func (e *validatorShardEntry) toValidator(index int) (*Validator, error) {
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(e.PublicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pubkey: %w", err)
+	}
+
+	if err := checkPublicKey(index, pubKey); err != nil {
+		return nil, err
+	}
+
+	withdrawalCred, err := hex.DecodeString(strings.TrimPrefix(e.WithdrawalCredentials, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid withdrawal credentials: %w", err)
+	}
+
+	if err := checkWithdrawalCredentials(withdrawalCred); err != nil {
+		return nil, fmt.Errorf("invalid withdrawal credentials (%w)", err)
+	}
+
+	return &Validator{
+		PublicKey:             phase0.BLSPubKey(pubKey),
+		WithdrawalCredentials: withdrawalCred,
+		Balance:               e.Balance,
+		EffectiveBalance:      e.EffectiveBalance,
+		VendorType:            e.VendorType,
+	}, nil
+}
+
+// naturalLess compares two strings, treating runs of digits as numbers.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		aDigits := strings.IndexFunc(a, func(r rune) bool { return !unicode.IsDigit(r) })
+		bDigits := strings.IndexFunc(b, func(r rune) bool { return !unicode.IsDigit(r) })
+
+		if aDigits == -1 {
+			aDigits = len(a)
+		}
+
+		if bDigits == -1 {
+			bDigits = len(b)
+		}
+
+		if aDigits > 0 && bDigits > 0 {
+			aNum, aErr := strconv.ParseUint(a[:aDigits], 10, 64)
+			bNum, bErr := strconv.ParseUint(b[:bDigits], 10, 64)
+
+			if aErr == nil && bErr == nil && aNum != bNum {
+				return aNum < bNum
+			}
+
+			if a[:aDigits] != b[:bDigits] {
+				return a[:aDigits] < b[:bDigits]
+			}
+
+			a, b = a[aDigits:], b[bDigits:]
+
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
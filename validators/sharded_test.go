@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestShard(t *testing.T, dir, name string, startIndex int, pubkeys ...byte) {
+	t.Helper()
+
+	entries := make([]string, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		entries = append(entries, fmt.Sprintf(`{"pubkey": "0x%s", "withdrawal_credentials": "0x%s", "balance": 32000000000}`,
+			strings.Repeat(fmt.Sprintf("%02x", pubkey), 48), strings.Repeat("00", 32)))
+	}
+
+	data := fmt.Sprintf(`{"start_index": %d, "validators": [%s]}`, startIndex, strings.Join(entries, ","))
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil { //nolint:gosec // test file
+		t.Fatalf("failed to write shard file: %v", err)
+	}
+}
+
+func TestLoadSharded(t *testing.T) {
+	dir := t.TempDir()
+
+	// filename order is numeric, validators-10.json follows validators-2.json
+	writeTestShard(t, dir, "validators-0.json", 0, 1, 2)
+	writeTestShard(t, dir, "validators-2.json", 3, 4)
+	writeTestShard(t, dir, "validators-1.json", 2, 3)
+	writeTestShard(t, dir, "validators-10.json", 4, 5, 6)
+
+	validators, err := LoadSharded(filepath.Join(dir, "validators-*.json"))
+	if err != nil {
+		t.Fatalf("failed to load sharded validators: %v", err)
+	}
+
+	if len(validators) != 6 {
+		t.Fatalf("expected 6 validators, got %d", len(validators))
+	}
+
+	for i, validator := range validators {
+		if validator.PublicKey[0] != byte(i+1) {
+			t.Errorf("unexpected validator at index %d: %s", i, validator.PublicKey.String())
+		}
+
+		if validator.Balance == nil || *validator.Balance != 32000000000 {
+			t.Errorf("unexpected balance for validator %d", i)
+		}
+	}
+}
+
+func TestLoadShardedRangeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		secondStart int
+		expectedErr string
+	}{
+		{name: "gap", secondStart: 3, expectedErr: "gap in validator shards"},
+		{name: "overlap", secondStart: 1, expectedErr: "overlapping validator shards"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			writeTestShard(t, dir, "validators-0.json", 0, 1, 2)
+			writeTestShard(t, dir, "validators-1.json", tt.secondStart, 3)
+
+			_, err := LoadSharded(filepath.Join(dir, "validators-*.json"))
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	if _, err := LoadSharded(filepath.Join(t.TempDir(), "validators-*.json")); err == nil {
+		t.Fatalf("expected error for pattern without matches")
+	}
+}
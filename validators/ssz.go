@@ -0,0 +1,62 @@
+package validators
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+
+	"github.com/ethpandaops/eth-beacon-genesis/beaconconfig"
+)
+
+// validatorSSZSize is the size of a serialized phase0.Validator record.
+const validatorSSZSize = 121
+
+// LoadSSZ loads validators from an SSZ encoded list of validator records (e.g. the validators field of a
+// previously generated state). The effective balance of each record is used as the validator balance,
+// so rebuilding a genesis from the loaded validators reproduces the original validators root.
+func LoadSSZ(path string, cfg *beaconconfig.Config) ([]*Validator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data)%validatorSSZSize != 0 {
+		return nil, fmt.Errorf("invalid validator list size: %d bytes is not a multiple of %d", len(data), validatorSSZSize)
+	}
+
+	count := uint64(len(data) / validatorSSZSize)
+	if limit := cfg.GetUintDefault("VALIDATOR_REGISTRY_LIMIT", 1099511627776); count > limit {
+		return nil, fmt.Errorf("validator list has %d entries, limit is %d", count, limit)
+	}
+
+	validators := make([]*Validator, 0, count)
+	pubkeyMap := map[phase0.BLSPubKey]int{}
+
+	for i := uint64(0); i < count; i++ {
+		record := &phase0.Validator{}
+		if err := record.UnmarshalSSZ(data[i*validatorSSZSize : (i+1)*validatorSSZSize]); err != nil {
+			return nil, fmt.Errorf("failed to decode validator %d: %w", i, err)
+		}
+
+		if err := checkPublicKey(int(i), record.PublicKey[:]); err != nil {
+			return nil, err
+		}
+
+		if prevIdx, found := pubkeyMap[record.PublicKey]; found {
+			return nil, fmt.Errorf("duplicate pubkey %s at index %d (first seen at index %d)", record.PublicKey.String(), i, prevIdx)
+		}
+
+		pubkeyMap[record.PublicKey] = int(i)
+
+		balance := uint64(record.EffectiveBalance)
+
+		validators = append(validators, &Validator{
+			PublicKey:             record.PublicKey,
+			WithdrawalCredentials: record.WithdrawalCredentials,
+			Balance:               &balance,
+		})
+	}
+
+	return validators, nil
+}
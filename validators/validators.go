@@ -1,12 +1,132 @@
 package validators
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type Validator struct {
 	PublicKey             phase0.BLSPubKey
 	WithdrawalCredentials []byte
-	Balance               *uint64
-	VendorType            string
+	// Balance is the deposit amount in gwei, the validator gets the max effective balance if it is not set.
+	Balance *uint64
+	// EffectiveBalance overrides the effective balance derived from Balance. It is still capped to the
+	// max effective balance of the genesis fork, and used as balance if Balance is not set.
+	EffectiveBalance *uint64
+	VendorType       string
+}
+
+// checkPublicKey checks that the pubkey of the validator at index is 48 bytes and not all zero.
+// Clients silently mishandle genesis states with such keys, so the loaders reject them upfront.
+func checkPublicKey(index int, pubKey []byte) error {
+	if len(pubKey) != len(phase0.BLSPubKey{}) {
+		return fmt.Errorf("invalid pubkey (invalid length) for validator %d: %d bytes", index, len(pubKey))
+	}
+
+	if bytes.Equal(pubKey, make([]byte, len(pubKey))) {
+		return fmt.Errorf("invalid pubkey (all zero) for validator %d", index)
+	}
+
+	return nil
+}
+
+// SortByPublicKey returns a copy of the validator list sorted by public key in ascending byte order.
+// The input slice is left untouched.
+func SortByPublicKey(vals []*Validator) []*Validator {
+	sorted := make([]*Validator, len(vals))
+	copy(sorted, vals)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].PublicKey[:], sorted[j].PublicKey[:]) < 0
+	})
+
+	return sorted
+}
+
+// Slice returns the contiguous subset of validators with indices [start, start+count).
+// An error is returned if the range is not fully contained in the validator list.
+func Slice(vals []*Validator, start, count uint64) ([]*Validator, error) {
+	total := uint64(len(vals))
+
+	if start >= total {
+		return nil, fmt.Errorf("validator range start %d out of range (have %d validators)", start, total)
+	}
+
+	if count > total-start {
+		return nil, fmt.Errorf("validator range [%d, %d) out of range (have %d validators)", start, start+count, total)
+	}
+
+	return vals[start : start+count], nil
+}
+
+// ValidateWithdrawalAddresses checks that every validator with 0x01 or 0x02 withdrawal credentials
+// withdraws to one of the allowed execution addresses. Validators with BLS (0x00) credentials are skipped.
+// The returned error lists all validators with a disallowed address.
+func ValidateWithdrawalAddresses(vals []*Validator, allowed []common.Address) error {
+	allowedSet := make(map[common.Address]bool, len(allowed))
+	for _, addr := range allowed {
+		allowedSet[addr] = true
+	}
+
+	invalid := []string{}
+
+	for idx, val := range vals {
+		if len(val.WithdrawalCredentials) != 32 {
+			continue
+		}
+
+		if val.WithdrawalCredentials[0] != 0x01 && val.WithdrawalCredentials[0] != 0x02 {
+			continue
+		}
+
+		addr := common.BytesToAddress(val.WithdrawalCredentials[12:])
+		if !allowedSet[addr] {
+			invalid = append(invalid, fmt.Sprintf("%d (%s)", idx, addr.Hex()))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("validators with disallowed withdrawal address: %s", strings.Join(invalid, ", "))
+	}
+
+	return nil
+}
+
+// ValidateDepositAmounts checks that the deposit amount of every validator is at least minDeposit and at most
+// maxBalance, or maxCompoundingBalance for validators with 0x02 withdrawal credentials. The deposit amount is
+// Balance, or EffectiveBalance if no balance is set. Validators without either get the max effective balance
+// and are skipped. The returned error names the first validator with an out of bounds amount.
+func ValidateDepositAmounts(vals []*Validator, minDeposit, maxBalance, maxCompoundingBalance uint64) error {
+	for idx, val := range vals {
+		var amount uint64
+
+		switch {
+		case val.Balance != nil:
+			amount = *val.Balance
+		case val.EffectiveBalance != nil:
+			amount = *val.EffectiveBalance
+		default:
+			continue
+		}
+
+		maxAmount := maxBalance
+		if len(val.WithdrawalCredentials) > 0 && val.WithdrawalCredentials[0] == 0x02 {
+			maxAmount = maxCompoundingBalance
+		}
+
+		if amount < minDeposit {
+			return fmt.Errorf("deposit amount of validator %d is %d gwei, below the minimum deposit of %d gwei", idx, amount, minDeposit)
+		}
+
+		if amount > maxAmount {
+			return fmt.Errorf("deposit amount of validator %d is %d gwei, above the max effective balance of %d gwei", idx, amount, maxAmount)
+		}
+	}
+
+	return nil
 }
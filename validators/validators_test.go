@@ -0,0 +1,130 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSortByPublicKey(t *testing.T) {
+	vals := []*Validator{
+		{PublicKey: phase0.BLSPubKey{0x03}},
+		{PublicKey: phase0.BLSPubKey{0x01}},
+		{PublicKey: phase0.BLSPubKey{0x02}},
+	}
+
+	sorted := SortByPublicKey(vals)
+
+	for i, expected := range []byte{0x01, 0x02, 0x03} {
+		if sorted[i].PublicKey[0] != expected {
+			t.Fatalf("unexpected validator at index %d: got %s", i, sorted[i].PublicKey.String())
+		}
+	}
+
+	if vals[0].PublicKey[0] != 0x03 {
+		t.Fatalf("expected input slice to be left untouched")
+	}
+}
+
+func TestSlice(t *testing.T) {
+	vals := make([]*Validator, 10)
+	for i := range vals {
+		vals[i] = &Validator{PublicKey: phase0.BLSPubKey{byte(i)}}
+	}
+
+	subset, err := Slice(vals, 2, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(subset) != 5 {
+		t.Fatalf("expected 5 validators, got %d", len(subset))
+	}
+
+	for i, val := range subset {
+		if val.PublicKey[0] != byte(i+2) {
+			t.Fatalf("unexpected validator at index %d: got %s", i, val.PublicKey.String())
+		}
+	}
+
+	if _, err := Slice(vals, 10, 1); err == nil {
+		t.Fatalf("expected error for out-of-range start")
+	}
+
+	if _, err := Slice(vals, 8, 5); err == nil {
+		t.Fatalf("expected error for out-of-range count")
+	}
+}
+
+func TestValidateWithdrawalAddresses(t *testing.T) {
+	allowedAddr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	otherAddr := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	withdrawalCreds := func(prefix byte, addr common.Address) []byte {
+		creds := make([]byte, 32)
+		creds[0] = prefix
+		copy(creds[12:], addr[:])
+
+		return creds
+	}
+
+	vals := []*Validator{
+		{WithdrawalCredentials: withdrawalCreds(0x01, allowedAddr)},
+		{WithdrawalCredentials: withdrawalCreds(0x02, allowedAddr)},
+		{WithdrawalCredentials: withdrawalCreds(0x00, otherAddr)},
+	}
+
+	if err := ValidateWithdrawalAddresses(vals, []common.Address{allowedAddr}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vals = append(vals, &Validator{WithdrawalCredentials: withdrawalCreds(0x01, otherAddr)})
+
+	err := ValidateWithdrawalAddresses(vals, []common.Address{allowedAddr})
+	if err == nil {
+		t.Fatalf("expected error for disallowed withdrawal address")
+	}
+
+	if !strings.Contains(err.Error(), "3 ("+otherAddr.Hex()+")") {
+		t.Fatalf("expected error to list validator 3, got %v", err)
+	}
+}
+
+func TestValidateDepositAmounts(t *testing.T) {
+	amount := func(gwei uint64) *uint64 { return &gwei }
+
+	compoundingCreds := make([]byte, 32)
+	compoundingCreds[0] = 0x02
+
+	vals := []*Validator{
+		{WithdrawalCredentials: make([]byte, 32)},
+		{WithdrawalCredentials: make([]byte, 32), Balance: amount(32_000_000_000)},
+		{WithdrawalCredentials: compoundingCreds, Balance: amount(64_000_000_000)},
+	}
+
+	if err := ValidateDepositAmounts(vals, 1_000_000_000, 32_000_000_000, 2_048_000_000_000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	belowMin := append(vals, &Validator{WithdrawalCredentials: make([]byte, 32), Balance: amount(500_000_000)})
+
+	err := ValidateDepositAmounts(belowMin, 1_000_000_000, 32_000_000_000, 2_048_000_000_000)
+	if err == nil || !strings.Contains(err.Error(), "validator 3 is 500000000 gwei, below the minimum deposit") {
+		t.Fatalf("expected below minimum error for validator 3, got %v", err)
+	}
+
+	aboveMax := append(vals, &Validator{WithdrawalCredentials: make([]byte, 32), EffectiveBalance: amount(33_000_000_000)})
+
+	err = ValidateDepositAmounts(aboveMax, 1_000_000_000, 32_000_000_000, 2_048_000_000_000)
+	if err == nil || !strings.Contains(err.Error(), "validator 3 is 33000000000 gwei, above the max effective balance of 32000000000 gwei") {
+		t.Fatalf("expected above maximum error for validator 3, got %v", err)
+	}
+
+	// without electra the compounding validator is capped at MAX_EFFECTIVE_BALANCE too
+	err = ValidateDepositAmounts(vals, 1_000_000_000, 32_000_000_000, 32_000_000_000)
+	if err == nil || !strings.Contains(err.Error(), "validator 2") {
+		t.Fatalf("expected above maximum error for validator 2, got %v", err)
+	}
+}